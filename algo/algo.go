@@ -0,0 +1,23 @@
+// Package algo implements graph algorithms on top of the Fibonacci heap of
+// package fibheap, whose Θ(1) decrease-key makes them run in O(m + n log n)
+// time on graphs with n vertices and m edges.
+//
+// Graphs are given as adjacency lists: adj[v] lists the edges leaving the
+// vertex v, and vertices are numbered from 0 to len(adj)-1.
+package algo
+
+// Weight is the constraint of edge weights, the integer and floating-point
+// types.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Edge is an edge of a graph.
+type Edge[W Weight] struct {
+	// To is the vertex the edge leads to.
+	To int
+	// Weight is the weight of the edge.
+	Weight W
+}
@@ -0,0 +1,80 @@
+package algo
+
+import (
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Dijkstra computes the shortest paths from the vertex source to every vertex
+// of the graph adj, whose edge weights must be non-negative. It returns the
+// distance dist[v] from source to v, and the predecessor prev[v] of v on a
+// shortest path. The predecessor of source is source itself, and the
+// predecessor of a vertex unreachable from source is -1, in which case its
+// distance is zero. Dijkstra panics if it finds a negative weight.
+func Dijkstra[W Weight](adj [][]Edge[W], source int) (dist []W, prev []int) {
+	n := len(adj)
+	dist = make([]W, n)
+	prev = make([]int, n)
+	for v := range prev {
+		prev[v] = -1
+	}
+	prev[source] = source
+
+	// queued[v] is the element of v while v is in the heap. Vertices whose
+	// distance is final are left with a nil element.
+	queued := make([]*fibheap.Element[W, int], n)
+	done := make([]bool, n)
+	h := &fibheap.Heap[W, int]{}
+	queued[source] = h.Insert(0, source)
+	// the keys decreased by the edges of a vertex are applied at once
+	var updates []fibheap.KeyUpdate[W, int]
+	for h.Size() > 0 {
+		x := h.ExtractMin()
+		u := x.Value
+		queued[u] = nil
+		done[u] = true
+		for _, e := range adj[u] {
+			if e.Weight < 0 {
+				panic("algo: Dijkstra expects non-negative edge weights")
+			}
+			v := e.To
+			if done[v] {
+				continue
+			}
+			d := x.Key() + e.Weight
+			switch {
+			case queued[v] == nil:
+				queued[v] = h.Insert(d, v)
+			case d < dist[v]:
+				updates = append(updates, fibheap.KeyUpdate[W, int]{Element: queued[v], Key: d})
+			default:
+				continue
+			}
+			dist[v] = d
+			prev[v] = u
+		}
+		h.DecreaseAll(updates)
+		updates = updates[:0]
+	}
+	return dist, prev
+}
+
+// Path returns the vertices of the shortest path from the source given to
+// Dijkstra to the vertex v, using the predecessors prev returned by it. It
+// returns nil if v is unreachable.
+func Path(prev []int, v int) []int {
+	if prev[v] < 0 {
+		return nil
+	}
+	var path []int
+	for {
+		path = append(path, v)
+		if prev[v] == v {
+			break
+		}
+		v = prev[v]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
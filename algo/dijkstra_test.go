@@ -0,0 +1,93 @@
+package algo
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDijkstra(t *testing.T) {
+	adj := [][]Edge[int]{
+		0: {{1, 7}, {2, 9}, {5, 14}},
+		1: {{0, 7}, {2, 10}, {3, 15}},
+		2: {{0, 9}, {1, 10}, {3, 11}, {5, 2}},
+		3: {{1, 15}, {2, 11}, {4, 6}},
+		4: {{3, 6}, {5, 9}},
+		5: {{0, 14}, {2, 2}, {4, 9}},
+		6: {{0, 1}},
+	}
+	dist, prev := Dijkstra(adj, 0)
+	if expected := []int{0, 7, 9, 20, 20, 11, 0}; !slices.Equal(dist, expected) {
+		t.Fatalf("expected distances %v, got %v", expected, dist)
+	}
+	if expected := []int{0, 0, 0, 2, 5, 2, -1}; !slices.Equal(prev, expected) {
+		t.Fatalf("expected predecessors %v, got %v", expected, prev)
+	}
+	if path := Path(prev, 4); !slices.Equal(path, []int{0, 2, 5, 4}) {
+		t.Fatalf("expected path [0 2 5 4], got %v", path)
+	}
+	if path := Path(prev, 6); path != nil {
+		t.Fatalf("expected no path, got %v", path)
+	}
+}
+
+// bellmanFord is a reference implementation of the shortest distances.
+func bellmanFord(adj [][]Edge[float64], source int) []float64 {
+	dist := make([]float64, len(adj))
+	reached := make([]bool, len(adj))
+	reached[source] = true
+	for range adj {
+		for u, edges := range adj {
+			for _, e := range edges {
+				if reached[u] && (!reached[e.To] || dist[u]+e.Weight < dist[e.To]) {
+					dist[e.To] = dist[u] + e.Weight
+					reached[e.To] = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+func TestDijkstraRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 200
+		adj := make([][]Edge[float64], n)
+		for m := 0; m < 2000; m++ {
+			u := r.Intn(n)
+			adj[u] = append(adj[u], Edge[float64]{r.Intn(n), float64(r.Intn(100))})
+		}
+		dist, prev := Dijkstra(adj, 0)
+		expected := bellmanFord(adj, 0)
+		for v := range adj {
+			if dist[v] != expected[v] {
+				t.Fatalf("vertex %d: expected distance %v, got %v", v, expected[v], dist[v])
+			}
+			if v != 0 && prev[v] >= 0 {
+				// the last edge of the path must be tight
+				tight := false
+				for _, e := range adj[prev[v]] {
+					tight = tight || e.To == v && dist[prev[v]]+e.Weight == dist[v]
+				}
+				if !tight {
+					t.Fatalf("vertex %d: predecessor %d is not on a shortest path", v, prev[v])
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	n := 10000
+	adj := make([][]Edge[int], n)
+	for m := 0; m < 100000; m++ {
+		u := r.Intn(n)
+		adj[u] = append(adj[u], Edge[int]{r.Intn(n), r.Intn(1000)})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Dijkstra(adj, 0)
+	}
+}
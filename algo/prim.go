@@ -0,0 +1,54 @@
+package algo
+
+import (
+	"github.com/ksw2000/go-fibheap"
+)
+
+// PrimMST computes a minimum spanning forest of the undirected graph adj, in
+// which every edge must be listed in the adjacency lists of both of its
+// vertices. It returns the parent of every vertex in the forest, and the total
+// weight of the forest. Each tree of the forest is rooted at its smallest
+// vertex, which is its own parent.
+func PrimMST[W Weight](adj [][]Edge[W]) (parent []int, total W) {
+	n := len(adj)
+	parent = make([]int, n)
+	for v := range parent {
+		parent[v] = -1
+	}
+
+	// queued[v] is the element of v while v is in the heap, keyed by the
+	// weight of the lightest edge connecting v to the tree.
+	queued := make([]*fibheap.Element[W, int], n)
+	done := make([]bool, n)
+	h := &fibheap.Heap[W, int]{}
+	for root := range adj {
+		if done[root] {
+			continue
+		}
+		parent[root] = root
+		queued[root] = h.Insert(0, root)
+		for h.Size() > 0 {
+			x := h.ExtractMin()
+			u := x.Value
+			queued[u] = nil
+			done[u] = true
+			total += x.Key()
+			for _, e := range adj[u] {
+				v := e.To
+				if done[v] {
+					continue
+				}
+				switch {
+				case queued[v] == nil:
+					queued[v] = h.Insert(e.Weight, v)
+				case e.Weight < queued[v].Key():
+					h.Decreasing(queued[v], e.Weight)
+				default:
+					continue
+				}
+				parent[v] = u
+			}
+		}
+	}
+	return parent, total
+}
@@ -0,0 +1,92 @@
+package algo
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func undirected(n int, edges [][3]int) [][]Edge[int] {
+	adj := make([][]Edge[int], n)
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], Edge[int]{e[1], e[2]})
+		adj[e[1]] = append(adj[e[1]], Edge[int]{e[0], e[2]})
+	}
+	return adj
+}
+
+func TestPrimMST(t *testing.T) {
+	adj := undirected(7, [][3]int{
+		{0, 1, 4}, {0, 2, 3}, {1, 2, 1}, {1, 3, 2}, {2, 3, 4}, {3, 4, 2},
+		{5, 6, 7},
+	})
+	parent, total := PrimMST(adj)
+	if total != 15 {
+		t.Fatalf("expected total weight 15, got %d", total)
+	}
+	if expected := []int{0, 2, 0, 1, 3, 5, 5}; !slices.Equal(parent, expected) {
+		t.Fatalf("expected parents %v, got %v", expected, parent)
+	}
+}
+
+// kruskal is a reference implementation of the weight of a minimum spanning
+// forest.
+func kruskal(n int, edges [][3]int) int {
+	sort.Slice(edges, func(i, j int) bool { return edges[i][2] < edges[j][2] })
+	set := make([]int, n)
+	for i := range set {
+		set[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if set[x] != x {
+			set[x] = find(set[x])
+		}
+		return set[x]
+	}
+	total := 0
+	for _, e := range edges {
+		if a, b := find(e[0]), find(e[1]); a != b {
+			set[a] = b
+			total += e[2]
+		}
+	}
+	return total
+}
+
+func TestPrimMSTRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		n := 300
+		edges := make([][3]int, 1000)
+		for j := range edges {
+			edges[j] = [3]int{r.Intn(n), r.Intn(n), r.Intn(100)}
+		}
+		adj := undirected(n, edges)
+		parent, total := PrimMST(adj)
+		if expected := kruskal(n, edges); total != expected {
+			t.Fatalf("expected total weight %d, got %d", expected, total)
+		}
+		// the parents must form a forest whose edges add up to the total
+		sum := 0
+		for v, p := range parent {
+			if p == v {
+				continue
+			}
+			w := -1
+			for _, e := range adj[v] {
+				if e.To == p && (w < 0 || e.Weight < w) {
+					w = e.Weight
+				}
+			}
+			if w < 0 {
+				t.Fatalf("vertex %d: no edge to its parent %d", v, p)
+			}
+			sum += w
+		}
+		if sum != total {
+			t.Fatalf("the edges to the parents weigh %d, expected %d", sum, total)
+		}
+	}
+}
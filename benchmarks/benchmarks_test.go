@@ -0,0 +1,236 @@
+package benchmarks
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+	"github.com/ksw2000/go-fibheap/binaryheap"
+	"github.com/ksw2000/go-fibheap/binomialheap"
+	"github.com/ksw2000/go-fibheap/pairingheap"
+	"github.com/ksw2000/go-fibheap/slabheap"
+	"github.com/ksw2000/go-fibheap/strictfibheap"
+)
+
+var sizes = []int{1 << 8, 1 << 12, 1 << 16}
+
+// impl holds the benchmarks of one heap implementation. A nil benchmark is not
+// supported by the implementation.
+type impl struct {
+	name                                   string
+	insert, extract, decrease, meld, mixed func(b *testing.B, n int)
+}
+
+// queue returns the benchmarks of a heap implementing fibheap.PriorityQueue.
+func queue[E fibheap.Handle[int, int]](name string, newQ func() fibheap.PriorityQueue[int, int, E]) impl {
+	return impl{
+		name:     name,
+		insert:   func(b *testing.B, n int) { benchInsert(b, n, newQ) },
+		extract:  func(b *testing.B, n int) { benchExtract(b, n, newQ) },
+		decrease: func(b *testing.B, n int) { benchDecrease(b, n, newQ) },
+		mixed:    func(b *testing.B, n int) { benchMixed(b, n, newQ) },
+	}
+}
+
+// meldable returns the benchmarks of a heap implementing fibheap.MeldableHeap.
+func meldable[E fibheap.Handle[int, int], H fibheap.MeldableHeap[int, int, E, H]](name string, newH func() H) impl {
+	i := queue(name, func() fibheap.PriorityQueue[int, int, E] { return newH() })
+	i.meld = func(b *testing.B, n int) { benchMeld(b, n, newH) }
+	return i
+}
+
+var impls = []impl{
+	meldable("fibheap", func() *fibheap.Heap[int, int] { return &fibheap.Heap[int, int]{} }),
+	meldable("strictfibheap", func() *strictfibheap.Heap[int, int] { return &strictfibheap.Heap[int, int]{} }),
+	queue("slabheap", func() fibheap.PriorityQueue[int, int, *slabheap.Element[int, int]] { return &slabheap.Heap[int, int]{} }),
+	meldable("binomialheap", func() *binomialheap.Heap[int, int] { return &binomialheap.Heap[int, int]{} }),
+	meldable("pairingheap", func() *pairingheap.Heap[int, int] { return &pairingheap.Heap[int, int]{} }),
+	meldable("binaryheap", func() *binaryheap.Heap[int, int] { return &binaryheap.Heap[int, int]{} }),
+	meldable("simple", func() *simpleHeap { return &simpleHeap{} }),
+	{name: "container/heap", insert: benchIntInsert, extract: benchIntExtract},
+}
+
+// run runs the benchmark picked from every implementation for every size.
+func run(b *testing.B, pick func(impl) func(b *testing.B, n int)) {
+	for _, i := range impls {
+		bench := pick(i)
+		if bench == nil {
+			continue
+		}
+		for _, n := range sizes {
+			b.Run(fmt.Sprintf("%s/n=%d", i.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				bench(b, n)
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/float64(n), "ns/element")
+			})
+		}
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	run(b, func(i impl) func(*testing.B, int) { return i.insert })
+}
+
+func BenchmarkExtractMin(b *testing.B) {
+	run(b, func(i impl) func(*testing.B, int) { return i.extract })
+}
+
+func BenchmarkDecreaseKey(b *testing.B) {
+	run(b, func(i impl) func(*testing.B, int) { return i.decrease })
+}
+
+func BenchmarkMeld(b *testing.B) {
+	run(b, func(i impl) func(*testing.B, int) { return i.meld })
+}
+
+func BenchmarkMixed(b *testing.B) {
+	run(b, func(i impl) func(*testing.B, int) { return i.mixed })
+}
+
+// keys returns n distinct keys in a random order.
+func keys(n int) []int {
+	return rand.New(rand.NewSource(int64(n))).Perm(n)
+}
+
+// fill inserts keys into a new heap, and returns the heap and the inserted
+// elements.
+func fill[E fibheap.Handle[int, int]](newQ func() fibheap.PriorityQueue[int, int, E], keys []int) (fibheap.PriorityQueue[int, int, E], []E) {
+	q := newQ()
+	elements := make([]E, len(keys))
+	for i, k := range keys {
+		elements[i] = q.Insert(k, i)
+	}
+	return q, elements
+}
+
+// benchInsert inserts n elements into an empty heap.
+func benchInsert[E fibheap.Handle[int, int]](b *testing.B, n int, newQ func() fibheap.PriorityQueue[int, int, E]) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fill(newQ, keys)
+	}
+}
+
+// benchExtract extracts every element of a heap of n elements.
+func benchExtract[E fibheap.Handle[int, int]](b *testing.B, n int, newQ func() fibheap.PriorityQueue[int, int, E]) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		q, _ := fill(newQ, keys)
+		b.StartTimer()
+		for q.Size() > 0 {
+			q.ExtractMin()
+		}
+	}
+}
+
+// benchDecrease decreases the key of every element of a heap of n elements,
+// whose trees were built by extracting one element.
+func benchDecrease[E fibheap.Handle[int, int]](b *testing.B, n int, newQ func() fibheap.PriorityQueue[int, int, E]) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		q, elements := fill(newQ, keys)
+		m := q.ExtractMin()
+		b.StartTimer()
+		for _, e := range elements {
+			if e != m {
+				q.Decreasing(e, e.Key()-n)
+			}
+		}
+	}
+}
+
+// benchMeld melds two heaps of n/2 elements and extracts the minimum once, so
+// that lazily melded heaps pay for their consolidation.
+func benchMeld[E fibheap.Handle[int, int], H fibheap.MeldableHeap[int, int, E, H]](b *testing.B, n int, newH func() H) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		h, g := newH(), newH()
+		for j, k := range keys {
+			if j%2 == 0 {
+				h.Insert(k, j)
+			} else {
+				g.Insert(k, j)
+			}
+		}
+		b.StartTimer()
+		h.Meld(g)
+		h.ExtractMin()
+	}
+}
+
+// benchMixed runs a workload resembling a shortest path search on a heap of n
+// elements: every step extracts the minimum, inserts two larger keys, and
+// decreases the key of a random element.
+func benchMixed[E fibheap.Handle[int, int]](b *testing.B, n int, newQ func() fibheap.PriorityQueue[int, int, E]) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		r := rand.New(rand.NewSource(1))
+		q, live := fill(newQ, keys)
+		pos := make(map[E]int, len(live))
+		for j, e := range live {
+			pos[e] = j
+		}
+		b.StartTimer()
+		for step := 0; step < n; step++ {
+			m := q.ExtractMin()
+			base := m.Key()
+			j := pos[m]
+			delete(pos, m)
+			last := live[len(live)-1]
+			live[j] = last
+			live = live[:len(live)-1]
+			if last != m {
+				pos[last] = j
+			}
+			for range 2 {
+				e := q.Insert(base+1+r.Intn(n), step)
+				pos[e] = len(live)
+				live = append(live, e)
+			}
+			e := live[r.Intn(len(live))]
+			if k := base + 1 + r.Intn(n); k < e.Key() {
+				q.Decreasing(e, k)
+			}
+		}
+	}
+}
+
+// benchIntInsert pushes n ints into an empty container/heap.
+func benchIntInsert(b *testing.B, n int) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := &intHeap{}
+		for _, k := range keys {
+			heap.Push(h, k)
+		}
+	}
+}
+
+// benchIntExtract pops every int of a container/heap of n ints.
+func benchIntExtract(b *testing.B, n int) {
+	keys := keys(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		h := &intHeap{}
+		for _, k := range keys {
+			heap.Push(h, k)
+		}
+		b.StartTimer()
+		for h.Len() > 0 {
+			heap.Pop(h)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package benchmarks compares the heaps of this module with container/heap and
+// a plain binary heap written against a slice. It holds no code besides the
+// benchmarks, which measure inserting, extracting the minimum, decreasing keys,
+// melding and a mixed workload for several heap sizes:
+//
+//	go test -run NONE -bench . ./benchmarks
+//
+// Every benchmark reports the time per heap operation as ns/element, besides
+// the time per batch of operations reported as ns/op.
+package benchmarks
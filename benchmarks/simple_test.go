@@ -0,0 +1,144 @@
+package benchmarks
+
+import (
+	"container/heap"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// item is an element of simpleHeap.
+type item struct {
+	index int
+	key   int
+	value int
+}
+
+func (e *item) Key() int {
+	return e.key
+}
+
+func (e *item) Pair() fibheap.Pair[int, int] {
+	return fibheap.Pair[int, int]{Key: e.key, Value: e.value}
+}
+
+// simpleHeap is a binary heap of ints written against a slice, without the
+// interface calls of container/heap.
+type simpleHeap struct {
+	list []*item
+}
+
+func (h *simpleHeap) Size() int {
+	return len(h.list)
+}
+
+func (h *simpleHeap) Insert(key, value int) *item {
+	e := &item{index: len(h.list), key: key, value: value}
+	h.list = append(h.list, e)
+	h.up(e.index)
+	return e
+}
+
+func (h *simpleHeap) Min() *item {
+	if len(h.list) == 0 {
+		return nil
+	}
+	return h.list[0]
+}
+
+func (h *simpleHeap) ExtractMin() *item {
+	if len(h.list) == 0 {
+		return nil
+	}
+	e := h.list[0]
+	h.remove(0)
+	return e
+}
+
+func (h *simpleHeap) Decreasing(x *item, key int) {
+	if key < x.key {
+		x.key = key
+		h.up(x.index)
+	}
+}
+
+func (h *simpleHeap) Delete(x *item) {
+	h.remove(x.index)
+}
+
+func (h *simpleHeap) Meld(g *simpleHeap) {
+	for _, e := range g.list {
+		e.index = len(h.list)
+		h.list = append(h.list, e)
+	}
+	g.list = nil
+	for i := len(h.list)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+func (h *simpleHeap) remove(i int) {
+	n := len(h.list) - 1
+	h.swap(i, n)
+	h.list[n] = nil
+	h.list = h.list[:n]
+	if i < n {
+		h.down(i)
+		h.up(i)
+	}
+}
+
+func (h *simpleHeap) swap(i, j int) {
+	h.list[i], h.list[j] = h.list[j], h.list[i]
+	h.list[i].index = i
+	h.list[j].index = j
+}
+
+func (h *simpleHeap) up(i int) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if h.list[p].key <= h.list[i].key {
+			return
+		}
+		h.swap(i, p)
+		i = p
+	}
+}
+
+func (h *simpleHeap) down(i int) {
+	for {
+		c := 2*i + 1
+		if c >= len(h.list) {
+			return
+		}
+		if r := c + 1; r < len(h.list) && h.list[r].key < h.list[c].key {
+			c = r
+		}
+		if h.list[i].key <= h.list[c].key {
+			return
+		}
+		h.swap(i, c)
+		i = c
+	}
+}
+
+// intHeap is the plain int heap of the container/heap documentation, used as
+// the baseline of the benchmarks without handles.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *intHeap) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *intHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+var _ heap.Interface = (*intHeap)(nil)
@@ -0,0 +1,155 @@
+// Package bestfit implements a best-fit allocator of contiguous slots, such as
+// bytes of a memory arena or entries of a resource pool. Free blocks are grouped
+// by size, and the sizes are kept in Fibonacci heaps, one per power of two, so
+// allocating finds the smallest sufficient block without scanning every free
+// block.
+//
+// Every size of a larger power of two fits a request, so the best fit is the
+// minimum of the first nonempty heap above the one of the request. Only within
+// the heap of the request, sizes smaller than the request are set aside and
+// reinserted. Alloc thus takes amortized running time O(k + log n), where n is
+// the number of distinct free block sizes and k is the number of them which are
+// smaller than the request but in the same power of two.
+package bestfit
+
+import (
+	"math/bits"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Allocator represents the best-fit allocator. Adjacent free blocks are merged
+// when a block is freed.
+type Allocator struct {
+	// classes holds the sizes of the free blocks with the offsets of the
+	// blocks of each size, in the heap indexed by the bit length of the size.
+	classes [bits.UintSize]fibheap.Heap[int, []int]
+	class   map[int]*fibheap.Element[int, []int]
+	// starts maps the offset of a free block to its size and ends maps the end
+	// of a free block to its offset.
+	starts    map[int]int
+	ends      map[int]int
+	available int
+}
+
+// New returns an allocator managing the slots [0, size).
+func New(size int) *Allocator {
+	a := &Allocator{
+		class:  make(map[int]*fibheap.Element[int, []int]),
+		starts: make(map[int]int),
+		ends:   make(map[int]int),
+	}
+	if size > 0 {
+		a.Free(0, size)
+	}
+	return a
+}
+
+// Available returns the number of free slots of the allocator a.
+func (a *Allocator) Available() int {
+	return a.available
+}
+
+// Blocks returns the number of free blocks of the allocator a.
+func (a *Allocator) Blocks() int {
+	return len(a.starts)
+}
+
+// Alloc allocates size contiguous slots from the smallest free block which is
+// large enough, and returns the offset of the first slot. The remainder of the
+// block is kept as a free block. The boolean is false if no free block is large
+// enough. Alloc panics if size is not positive.
+func (a *Allocator) Alloc(size int) (offset int, ok bool) {
+	if size <= 0 {
+		panic("bestfit: Alloc expects a positive size")
+	}
+
+	// Set the size classes of the same power of two that are too small aside
+	// so that the minimum of the heap is the best fit, and put them back when
+	// done.
+	first := &a.classes[bits.Len(uint(size))]
+	small := first.ExtractUntil(size - 1)
+	defer func() {
+		for _, e := range small {
+			a.class[e.Key()] = first.Insert(e.Key(), e.Value)
+		}
+	}()
+
+	c := first
+	best := c.Min()
+	for i := bits.Len(uint(size)) + 1; best == nil && i < len(a.classes); i++ {
+		c = &a.classes[i]
+		best = c.Min()
+	}
+	if best == nil {
+		return 0, false
+	}
+	n := best.Key()
+	offset = best.Value[len(best.Value)-1]
+	best.Value = best.Value[:len(best.Value)-1]
+	c.ExtractMinIf(func(_ int, offsets []int) bool {
+		return len(offsets) == 0
+	})
+	if len(best.Value) == 0 {
+		delete(a.class, n)
+	}
+	delete(a.starts, offset)
+	delete(a.ends, offset+n)
+
+	if n > size {
+		a.add(offset+size, n-size)
+	}
+	a.available -= size
+	return offset, true
+}
+
+// Free returns the slots [offset, offset+size) to the allocator a, merging them
+// with the adjacent free blocks. The slots must have been allocated by Alloc.
+// Free panics if size is not positive.
+func (a *Allocator) Free(offset, size int) {
+	if size <= 0 {
+		panic("bestfit: Free expects a positive size")
+	}
+	a.available += size
+
+	if n, ok := a.starts[offset+size]; ok {
+		a.remove(offset+size, n)
+		size += n
+	}
+	if prev, ok := a.ends[offset]; ok {
+		n := a.starts[prev]
+		a.remove(prev, n)
+		offset, size = prev, size+n
+	}
+	a.add(offset, size)
+}
+
+// add adds the free block [offset, offset+size).
+func (a *Allocator) add(offset, size int) {
+	a.starts[offset] = size
+	a.ends[offset+size] = offset
+	if e, ok := a.class[size]; ok {
+		e.Value = append(e.Value, offset)
+		return
+	}
+	a.class[size] = a.classes[bits.Len(uint(size))].Insert(size, []int{offset})
+}
+
+// remove removes the free block [offset, offset+size).
+func (a *Allocator) remove(offset, size int) {
+	delete(a.starts, offset)
+	delete(a.ends, offset+size)
+	e := a.class[size]
+	for i, o := range e.Value {
+		if o == offset {
+			last := len(e.Value) - 1
+			e.Value[i] = e.Value[last]
+			e.Value = e.Value[:last]
+			break
+		}
+	}
+	if len(e.Value) == 0 {
+		delete(a.class, size)
+		a.classes[bits.Len(uint(size))].Delete(e)
+	}
+}
@@ -0,0 +1,107 @@
+package bestfit
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAllocatorBestFit(t *testing.T) {
+	a := New(100)
+	offsets := make([]int, 10)
+	for i := range offsets {
+		offset, ok := a.Alloc(10)
+		if !ok {
+			t.Fatalf("Alloc %d should succeed", i)
+		}
+		offsets[i] = offset
+	}
+	if _, ok := a.Alloc(1); ok {
+		t.Fatal("Alloc on a full allocator should fail")
+	}
+
+	// free blocks of sizes 10, 20 and 30
+	a.Free(offsets[0], 10)
+	a.Free(offsets[2], 10)
+	a.Free(offsets[3], 10)
+	a.Free(offsets[5], 10)
+	a.Free(offsets[6], 10)
+	a.Free(offsets[7], 10)
+	if a.Blocks() != 3 || a.Available() != 60 {
+		t.Fatalf("expected 3 blocks with 60 slots, got %d blocks with %d slots", a.Blocks(), a.Available())
+	}
+
+	if offset, ok := a.Alloc(15); !ok || offset != offsets[2] {
+		t.Fatalf("expected the block of size 20 at %d, got %d", offsets[2], offset)
+	}
+	if offset, ok := a.Alloc(10); !ok || offset != offsets[0] {
+		t.Fatalf("expected the block of size 10 at %d, got %d", offsets[0], offset)
+	}
+	if offset, ok := a.Alloc(25); !ok || offset != offsets[5] {
+		t.Fatalf("expected the block of size 30 at %d, got %d", offsets[5], offset)
+	}
+	if _, ok := a.Alloc(6); ok {
+		t.Fatal("Alloc should fail without a sufficient block")
+	}
+	if a.Available() != 10 || a.Blocks() != 2 {
+		t.Fatalf("expected 2 blocks with 10 slots, got %d blocks with %d slots", a.Blocks(), a.Available())
+	}
+}
+
+func TestAllocatorMerge(t *testing.T) {
+	a := New(64)
+	var offsets []int
+	for {
+		offset, ok := a.Alloc(8)
+		if !ok {
+			break
+		}
+		offsets = append(offsets, offset)
+	}
+	if len(offsets) != 8 {
+		t.Fatalf("expected 8 blocks, got %d", len(offsets))
+	}
+	for _, i := range []int{1, 5, 3, 7, 0, 2, 6, 4} {
+		a.Free(offsets[i], 8)
+	}
+	if a.Blocks() != 1 || a.Available() != 64 {
+		t.Fatalf("expected a single block of 64 slots, got %d blocks with %d slots", a.Blocks(), a.Available())
+	}
+	if offset, ok := a.Alloc(64); !ok || offset != 0 {
+		t.Fatal("Alloc should return the whole merged block")
+	}
+}
+
+func TestAllocatorRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a := New(1 << 12)
+	used := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		if r.Intn(2) == 0 && len(used) > 0 {
+			for offset, size := range used {
+				a.Free(offset, size)
+				delete(used, offset)
+				break
+			}
+			continue
+		}
+		size := 1 + r.Intn(64)
+		// the best fit is the smallest free block of at least size slots
+		best := 0
+		for _, n := range a.starts {
+			if n >= size && (best == 0 || n < best) {
+				best = n
+			}
+		}
+		offset, ok := a.Alloc(size)
+		if ok != (best != 0) {
+			t.Fatalf("Alloc(%d) reported %v with a best fit of %d", size, ok, best)
+		}
+		if !ok {
+			continue
+		}
+		if n := a.starts[offset+size] + size; best != size && n != best {
+			t.Fatalf("Alloc(%d) split a block of %d slots instead of %d", size, n, best)
+		}
+		used[offset] = size
+	}
+}
@@ -0,0 +1,212 @@
+// Package binaryheap adapts container/heap to the API of the heaps of package
+// fibheap. A binary heap stored in a slice has small constant factors and no
+// per-element links, but melding copies the smaller heap:
+// fetching the minimum is Θ(1),
+// inserting, extracting the minimum, decreasing a key and deleting are
+// O(log n),
+// and melding is O(m log(n+m)).
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package binaryheap
+
+import (
+	"cmp"
+	"container/heap"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Element is an element of a binary heap.
+type Element[K any, V any] struct {
+	// index is the position of the element in the heap, or -1 once it was
+	// removed.
+	index int
+	key   K
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// elements implements heap.Interface.
+type elements[K any, V any, O fibheap.Order[K]] struct {
+	order O
+	list  []*Element[K, V]
+}
+
+func (s *elements[K, V, O]) Len() int {
+	return len(s.list)
+}
+
+func (s *elements[K, V, O]) Less(i, j int) bool {
+	return s.order.Less(s.list[i].key, s.list[j].key)
+}
+
+func (s *elements[K, V, O]) Swap(i, j int) {
+	s.list[i], s.list[j] = s.list[j], s.list[i]
+	s.list[i].index = i
+	s.list[j].index = j
+}
+
+func (s *elements[K, V, O]) Push(x any) {
+	e := x.(*Element[K, V])
+	e.index = len(s.list)
+	s.list = append(s.list, e)
+}
+
+func (s *elements[K, V, O]) Pop() any {
+	n := len(s.list) - 1
+	e := s.list[n]
+	s.list[n] = nil
+	s.list = s.list[:n]
+	e.index = -1
+	return e
+}
+
+// Heap represents the binary heap. The keys of a Heap are of an ordered type,
+// and its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	s elements[K, V, O]
+}
+
+var _ fibheap.MeldableHeap[int, any, *Element[int, any], *Heap[int, any]] = (*Heap[int, any])(nil)
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("binaryheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{s: elements[K, V, fibheap.Func[K]]{order: less}}
+}
+
+// Size returns the number of elements in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.s.Len()
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with running time O(log n)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	if len(h.s.list) == 0 {
+		h.mustOrder()
+	}
+	e := &Element[K, V]{key: key, Value: value}
+	heap.Push(&h.s, e)
+	return e
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1)
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	if len(h.s.list) == 0 {
+		return nil
+	}
+	return h.s.list[0]
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with running
+// time O(log n)
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	if len(h.s.list) == 0 {
+		return nil
+	}
+	return heap.Pop(&h.s).(*Element[K, V])
+}
+
+// Decreasing decreases the key of the element x with running time O(log n). If
+// the new key is larger or equal than the key of x, Decreasing does nothing.
+// Decreasing panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	if !h.s.order.Less(key, x.key) {
+		return
+	}
+	x.key = key
+	heap.Fix(&h.s, x.index)
+}
+
+// Delete removes the element x from the heap h with running time O(log n).
+// Delete panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	h.mustContain(x, "Delete")
+	heap.Remove(&h.s, x.index)
+}
+
+// Meld moves every element of the heap g into the heap h, leaving g empty. The
+// elements of the smaller heap are pushed into the larger one, whose slice the
+// heap h keeps. The heap g must order keys in the same way as the heap h.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("binaryheap: Meld expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("binaryheap: Meld expects two different heaps")
+	}
+	order := h.s.order
+	if !valid[K](order) {
+		order = g.s.order
+	}
+	if len(h.s.list) < len(g.s.list) {
+		h.s, g.s = g.s, h.s
+	}
+	h.s.order = order
+	for _, e := range g.s.list {
+		heap.Push(&h.s, e)
+	}
+	g.s = elements[K, V, O]{order: order}
+}
+
+// Union unions the two binary heaps h and g, and returns the new binary heap.
+// The heap h and g will be reset after unioning.
+func (h *HeapOf[K, V, O]) Union(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("binaryheap: Union expects non-nil heap h and g")
+	}
+	m := &HeapOf[K, V, O]{s: elements[K, V, O]{order: h.s.order}}
+	m.Meld(h)
+	if g != h {
+		m.Meld(g)
+	}
+	return m
+}
+
+// mustContain panics with a message naming the method if the element x does not
+// belong to the heap h.
+func (h *HeapOf[K, V, O]) mustContain(x *Element[K, V], method string) {
+	if x.index < 0 || x.index >= len(h.s.list) || h.s.list[x.index] != x {
+		panic("binaryheap: " + method + " expects an element of the heap")
+	}
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !valid[K](h.s.order) {
+		panic("binaryheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
+
+// valid reports whether the ordering o can compare keys, which the zero Func
+// cannot.
+func valid[K any, O fibheap.Order[K]](o O) bool {
+	f, ok := any(o).(fibheap.Func[K])
+	return !ok || f != nil
+}
@@ -0,0 +1,51 @@
+package binaryheap
+
+import (
+	"testing"
+
+	"github.com/ksw2000/go-fibheap/internal/testsuite"
+)
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if h.Min() != nil || h.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if h.Size() != 6 || h.Min().Key() != 1 {
+		t.Fatalf("expected 6 elements and minimum 1, got %d and %d", h.Size(), h.Min().Key())
+	}
+	for _, expected := range []int{1, 2, 3, 5, 8, 9} {
+		if x := h.ExtractMin(); x.Key() != expected {
+			t.Fatalf("expected %d, got %d", expected, x.Key())
+		}
+	}
+}
+
+func TestHeapMisuse(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	y := g.Insert(2, nil)
+	h.Insert(3, nil)
+	h.ExtractMin()
+	for name, fn := range map[string]func(){
+		"Decreasing extracted": func() { h.Decreasing(x, 0) },
+		"Delete foreign":       func() { h.Delete(y) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestHeapConformance(t *testing.T) {
+	testsuite.ConformanceMeld(t, func() *Heap[int, int] { return &Heap[int, int]{} }, nil)
+}
@@ -0,0 +1,291 @@
+// Package binomialheap implements a binomial heap with the same API as the
+// Fibonacci heap of package fibheap. A binomial heap is a collection of
+// binomial trees, at most one of each degree. Unlike the Fibonacci heap, its
+// bounds are worst-case rather than amortized:
+// fetching the minimum is Θ(1),
+// inserting, extracting the minimum, decreasing a key, deleting and melding
+// are O(log n).
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package binomialheap
+
+import (
+	"cmp"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Element is an element of a binomial heap. Elements keep their identity while
+// the heap moves them between the nodes of its trees, so they remain valid
+// handles until they are extracted or deleted.
+type Element[K any, V any] struct {
+	n   *node[K, V]
+	key K
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// node is a node of a binomial tree. The roots of the heap and the children of
+// a node are linked by sibling in increasing and decreasing degree
+// respectively.
+type node[K any, V any] struct {
+	e       *Element[K, V]
+	p       *node[K, V]
+	child   *node[K, V]
+	sibling *node[K, V]
+	degree  int
+}
+
+// Heap represents the binomial heap. The keys of a Heap are of an ordered type,
+// and its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order    O
+	head     *node[K, V]
+	min      *node[K, V]
+	elements int
+}
+
+var _ fibheap.MeldableHeap[int, any, *Element[int, any], *Heap[int, any]] = (*Heap[int, any])(nil)
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("binomialheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less}
+}
+
+// Size returns the number of elements in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.elements
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with running time O(log n)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	if h.elements == 0 {
+		h.mustOrder()
+	}
+	e := &Element[K, V]{key: key, Value: value}
+	e.n = &node[K, V]{e: e}
+	h.elements++
+	h.union(e.n)
+	return e
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1)
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	if h.min == nil {
+		return nil
+	}
+	return h.min.e
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with running
+// time O(log n)
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	if h.min == nil {
+		return nil
+	}
+	e := h.min.e
+	h.removeRoot(h.min)
+	e.n = nil
+	return e
+}
+
+// Decreasing decreases the key of the element x with running time O(log n). If
+// the new key is larger or equal than the key of x, Decreasing does nothing.
+// Decreasing panics if x was extracted or deleted.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	if x.n == nil {
+		panic("binomialheap: Decreasing expects an element of the heap")
+	}
+	if !h.order.Less(key, x.key) {
+		return
+	}
+	x.key = key
+	n := x.n
+	for n.p != nil && h.order.Less(key, n.p.e.key) {
+		n = h.swap(n)
+	}
+	if n.p == nil && h.order.Less(key, h.min.e.key) {
+		h.min = n
+	}
+}
+
+// Delete removes the element x from the heap h with running time O(log n). The
+// element x acts as negative infinity: it is moved up to the root of its tree
+// and then removed. Delete panics if x was extracted or deleted.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	if x.n == nil {
+		panic("binomialheap: Delete expects an element of the heap")
+	}
+	n := x.n
+	for n.p != nil {
+		n = h.swap(n)
+	}
+	h.removeRoot(n)
+	x.n = nil
+}
+
+// Meld moves every element of the heap g into the heap h with running time
+// O(log n), leaving g empty. The heap g must order keys in the same way as the
+// heap h.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("binomialheap: Meld expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("binomialheap: Meld expects two different heaps")
+	}
+	if !valid[K](h.order) {
+		h.order = g.order
+	}
+	h.elements += g.elements
+	h.union(g.head)
+	g.head, g.min, g.elements = nil, nil, 0
+}
+
+// Union unions the two binomial heaps h and g, and returns the new binomial
+// heap with running time O(log n). The heap h and g will be reset after
+// unioning.
+func (h *HeapOf[K, V, O]) Union(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("binomialheap: Union expects non-nil heap h and g")
+	}
+	m := &HeapOf[K, V, O]{order: h.order}
+	m.Meld(h)
+	if g != h {
+		m.Meld(g)
+	}
+	return m
+}
+
+// swap exchanges the elements of the node n and its parent, and returns the
+// parent.
+func (h *HeapOf[K, V, O]) swap(n *node[K, V]) *node[K, V] {
+	p := n.p
+	n.e, p.e = p.e, n.e
+	n.e.n, p.e.n = n, p
+	return p
+}
+
+// removeRoot removes the root x from the heap h and melds its children back.
+func (h *HeapOf[K, V, O]) removeRoot(x *node[K, V]) {
+	if x == h.head {
+		h.head = x.sibling
+	} else {
+		prev := h.head
+		for prev.sibling != x {
+			prev = prev.sibling
+		}
+		prev.sibling = x.sibling
+	}
+	// the children are in decreasing degree, reverse them into a root list
+	var list *node[K, V]
+	for c := x.child; c != nil; {
+		next := c.sibling
+		c.p = nil
+		c.sibling = list
+		list = c
+		c = next
+	}
+	h.elements--
+	h.union(list)
+}
+
+// union melds the root list list into the root list of the heap h, linking
+// trees of equal degree, and updates the minimum.
+func (h *HeapOf[K, V, O]) union(list *node[K, V]) {
+	head := merge(h.head, list)
+	var prev *node[K, V]
+	for x := head; x != nil && x.sibling != nil; {
+		next := x.sibling
+		switch {
+		case x.degree != next.degree || (next.sibling != nil && next.sibling.degree == x.degree):
+			prev, x = x, next
+		case !h.order.Less(next.e.key, x.e.key):
+			x.sibling = next.sibling
+			link(next, x)
+		default:
+			if prev == nil {
+				head = next
+			} else {
+				prev.sibling = next
+			}
+			link(x, next)
+			x = next
+		}
+	}
+	h.head = head
+	h.min = head
+	for x := head; x != nil; x = x.sibling {
+		if h.order.Less(x.e.key, h.min.e.key) {
+			h.min = x
+		}
+	}
+}
+
+// merge merges the root lists a and b in increasing degree.
+func merge[K any, V any](a, b *node[K, V]) *node[K, V] {
+	var head node[K, V]
+	tail := &head
+	for a != nil && b != nil {
+		if a.degree <= b.degree {
+			tail.sibling, a = a, a.sibling
+		} else {
+			tail.sibling, b = b, b.sibling
+		}
+		tail = tail.sibling
+	}
+	if a != nil {
+		tail.sibling = a
+	} else {
+		tail.sibling = b
+	}
+	return head.sibling
+}
+
+// link makes the root y a child of the root z.
+func link[K any, V any](y, z *node[K, V]) {
+	y.p = z
+	y.sibling = z.child
+	z.child = y
+	z.degree++
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !valid[K](h.order) {
+		panic("binomialheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
+
+// valid reports whether the ordering o can compare keys, which the zero Func
+// cannot.
+func valid[K any, O fibheap.Order[K]](o O) bool {
+	f, ok := any(o).(fibheap.Func[K])
+	return !ok || f != nil
+}
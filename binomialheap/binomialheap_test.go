@@ -0,0 +1,84 @@
+package binomialheap
+
+import (
+	"testing"
+
+	"github.com/ksw2000/go-fibheap/internal/testsuite"
+)
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if h.Min() != nil || h.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if h.Size() != 6 || h.Min().Key() != 1 {
+		t.Fatalf("expected 6 elements and minimum 1, got %d and %d", h.Size(), h.Min().Key())
+	}
+	for _, expected := range []int{1, 2, 3, 5, 8, 9} {
+		if x := h.ExtractMin(); x.Key() != expected {
+			t.Fatalf("expected %d, got %d", expected, x.Key())
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+}
+
+func TestHeapDecreasingDelete(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i+100, i)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[73], 0)
+	h.Decreasing(elements[50], 300)
+	if x := h.Min(); x != elements[73] || x.Value != 73 {
+		t.Fatalf("expected element 73 as the minimum, got %v", x.Value)
+	}
+	h.Delete(elements[73])
+	h.Delete(elements[99])
+	for i := 1; i < 99; i++ {
+		if i == 73 {
+			continue
+		}
+		if x := h.ExtractMin(); x != elements[i] {
+			t.Fatalf("expected element %d, got %d", i, x.Value)
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	h.Delete(elements[0])
+}
+
+func TestHeapUnion(t *testing.T) {
+	h := NewHeapFunc[int, any](func(a, b int) bool { return a > b })
+	g := NewHeapFunc[int, any](func(a, b int) bool { return a > b })
+	for i := 0; i < 10; i++ {
+		h.Insert(i, nil)
+		g.Insert(i+10, nil)
+	}
+	k := h.Union(g)
+	if h.Size() != 0 || g.Size() != 0 {
+		t.Fatal("h and g should be clear after Union")
+	}
+	for i := 19; i >= 0; i-- {
+		if x := k.ExtractMin(); x.Key() != i {
+			t.Fatalf("expected %d, got %d", i, x.Key())
+		}
+	}
+}
+
+func TestHeapConformance(t *testing.T) {
+	testsuite.ConformanceMeld(t, func() *Heap[int, int] { return &Heap[int, int]{} }, nil)
+}
@@ -0,0 +1,138 @@
+package fibheap
+
+import "cmp"
+
+// BoundedHeap retains at most a fixed number of elements, the ones with the
+// smallest keys, such as the top-k results of a stream ranked by key. Once the
+// heap is at capacity, inserting a key smaller than the largest key evicts the
+// element with the largest key, and inserting any other key evicts the
+// inserted pair. Internally, the elements are kept in a MinMaxHeap, so that
+// the element to evict and the best element are both found in Θ(1). The keys
+// of a BoundedHeap are of an ordered type, and the heap is created by
+// NewBoundedHeap. The zero value has no capacity and panics on Insert.
+type BoundedHeap[K cmp.Ordered, V any] = BoundedHeapOf[K, V, Ordered[K]]
+
+// BoundedHeapFunc is a BoundedHeap whose keys are ordered by a comparison
+// function. It is created by NewBoundedHeapFunc.
+type BoundedHeapFunc[K any, V any] = BoundedHeapOf[K, V, Func[K]]
+
+// BoundedHeapOf is a BoundedHeap whose keys are ordered by O. It is used
+// through its aliases BoundedHeap and BoundedHeapFunc.
+type BoundedHeapOf[K any, V any, O Order[K]] struct {
+	heap     MinMaxHeapOf[K, V, O]
+	capacity int
+
+	// Evicted, if not nil, is called with the key and the value of every
+	// element evicted by Insert, including the inserted pair itself when it
+	// is evicted at once.
+	Evicted func(key K, value V)
+}
+
+// NewBoundedHeap returns an empty heap retaining at most capacity elements.
+// NewBoundedHeap panics if capacity is not positive.
+func NewBoundedHeap[K cmp.Ordered, V any](capacity int) *BoundedHeap[K, V] {
+	if capacity <= 0 {
+		panic("fibheap: NewBoundedHeap expects a positive capacity")
+	}
+	return &BoundedHeap[K, V]{capacity: capacity}
+}
+
+// NewBoundedHeapFunc is like NewBoundedHeap, but orders keys with less.
+func NewBoundedHeapFunc[K any, V any](capacity int, less func(a, b K) bool) *BoundedHeapFunc[K, V] {
+	if capacity <= 0 {
+		panic("fibheap: NewBoundedHeapFunc expects a positive capacity")
+	}
+	return &BoundedHeapFunc[K, V]{heap: *NewMinMaxHeapFunc[K, V](less), capacity: capacity}
+}
+
+// Cap returns the capacity of the heap b.
+func (b *BoundedHeapOf[K, V, O]) Cap() int {
+	return b.capacity
+}
+
+// Size returns the number of elements in the heap b
+func (b *BoundedHeapOf[K, V, O]) Size() int {
+	return b.heap.Size()
+}
+
+// Contains reports whether the element x belongs to the heap b, that is, it was
+// neither evicted nor deleted.
+func (b *BoundedHeapOf[K, V, O]) Contains(x *MinMaxElement[K, V]) bool {
+	return b.heap.Contains(x)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap b with amortized
+// running time O(log n), and returns the inserted element, or nil if the pair
+// was evicted at once because the heap is at capacity and key is not smaller
+// than its largest key. Insert panics if the heap b was not created by
+// NewBoundedHeap or NewBoundedHeapFunc.
+func (b *BoundedHeapOf[K, V, O]) Insert(key K, value V) *MinMaxElement[K, V] {
+	if b.capacity <= 0 {
+		panic("fibheap: a BoundedHeap must be created by NewBoundedHeap or NewBoundedHeapFunc")
+	}
+	if b.heap.Size() < b.capacity {
+		return b.heap.Insert(key, value)
+	}
+	if w := b.heap.Max(); !b.heap.min.order.Less(key, w.Key()) {
+		if b.Evicted != nil {
+			b.Evicted(key, value)
+		}
+		return nil
+	}
+	x := b.heap.Insert(key, value)
+	w := b.heap.ExtractMax()
+	if b.Evicted != nil {
+		b.Evicted(w.Key(), w.Value)
+	}
+	return x
+}
+
+// Min fetches the element with the smallest key, the best one retained, with
+// running time Θ(1). It returns nil if the heap b is empty.
+func (b *BoundedHeapOf[K, V, O]) Min() *MinMaxElement[K, V] {
+	return b.heap.Min()
+}
+
+// ExtractMin fetches and removes the element with the smallest key from the
+// heap b with amortized running time O(log n). It returns nil if the heap b is
+// empty.
+func (b *BoundedHeapOf[K, V, O]) ExtractMin() *MinMaxElement[K, V] {
+	return b.heap.ExtractMin()
+}
+
+// Worst fetches the element with the largest key, which is the next one to be
+// evicted, with running time Θ(1).
+func (b *BoundedHeapOf[K, V, O]) Worst() *MinMaxElement[K, V] {
+	return b.heap.Max()
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// O(log n). If the new key is larger or equal than the key of x, Decreasing
+// does nothing.
+func (b *BoundedHeapOf[K, V, O]) Decreasing(x *MinMaxElement[K, V], key K) {
+	b.heap.Decreasing(x, key)
+}
+
+// Increasing increases the key of the element x with amortized running time
+// O(log n). If the new key is smaller or equal than the key of x, Increasing
+// does nothing.
+func (b *BoundedHeapOf[K, V, O]) Increasing(x *MinMaxElement[K, V], key K) {
+	b.heap.Increasing(x, key)
+}
+
+// Delete removes the element x from the heap b with amortized running time
+// O(log n).
+func (b *BoundedHeapOf[K, V, O]) Delete(x *MinMaxElement[K, V]) {
+	b.heap.Delete(x)
+}
+
+// Drain fetches and removes every element from the heap b, and returns them in
+// ascending order with amortized running time O(n log n), leaving the heap
+// empty.
+func (b *BoundedHeapOf[K, V, O]) Drain() []*MinMaxElement[K, V] {
+	list := make([]*MinMaxElement[K, V], 0, b.heap.Size())
+	for x := b.heap.ExtractMin(); x != nil; x = b.heap.ExtractMin() {
+		list = append(list, x)
+	}
+	return list
+}
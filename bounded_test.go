@@ -0,0 +1,81 @@
+package fibheap
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestBoundedHeap(t *testing.T) {
+	b := NewBoundedHeap[int, string](3)
+	var evicted []int
+	b.Evicted = func(key int, value string) {
+		if value != strconv.Itoa(key) {
+			t.Fatalf("evicted key %d with value %q", key, value)
+		}
+		evicted = append(evicted, key)
+	}
+	for _, k := range []int{5, 9, 2, 7, 1, 9, 3} {
+		b.Insert(k, strconv.Itoa(k))
+	}
+	assert(t, b.Size(), 3)
+	assert(t, b.Worst().Key(), 3)
+	if expected := []int{9, 7, 9, 5}; !slices.Equal(evicted, expected) {
+		t.Fatalf("expected evicted keys %v, got %v", expected, evicted)
+	}
+	if x := b.Insert(3, "3"); x != nil {
+		t.Fatalf("expected an equal key to be evicted, got %v", x.Key())
+	}
+	assert(t, b.Min().Key(), 1)
+	assert(t, b.ExtractMin().Key(), 1)
+	for i, x := range b.Drain() {
+		assert(t, x.Key(), []int{2, 3}[i])
+	}
+	assert(t, b.Size(), 0)
+	if b.Min() != nil || b.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+}
+
+func TestBoundedHeapFunc(t *testing.T) {
+	// retain the largest keys
+	b := NewBoundedHeapFunc[int, any](10, func(a, b int) bool { return a > b })
+	r := rand.New(rand.NewSource(1))
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = r.Intn(1 << 20)
+		b.Insert(keys[i], nil)
+	}
+	slices.Sort(keys)
+	slices.Reverse(keys)
+	for i, x := range b.Drain() {
+		assert(t, x.Key(), keys[i])
+	}
+}
+
+func TestBoundedHeapUpdate(t *testing.T) {
+	b := NewBoundedHeap[int, any](2)
+	x := b.Insert(1, nil)
+	y := b.Insert(2, nil)
+	b.Increasing(x, 10)
+	assert(t, b.Worst().Key(), 10)
+	b.Insert(5, nil)
+	if b.Contains(x) {
+		t.Fatal("expected the increased element to be evicted")
+	}
+	b.Decreasing(y, 0)
+	b.Delete(y)
+	assert(t, b.Size(), 1)
+	assert(t, b.Worst().Key(), 5)
+}
+
+func TestBoundedHeapZero(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	var b BoundedHeap[int, any]
+	b.Insert(0, nil)
+}
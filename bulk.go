@@ -0,0 +1,161 @@
+package fibheap
+
+import (
+	"cmp"
+)
+
+// Pair is a key-value pair.
+type Pair[K any, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// NewFromSlice returns a heap holding the key-value pairs of pairs, and the
+// elements created for them in the same order. See InsertAll.
+func NewFromSlice[K cmp.Ordered, V any](pairs []Pair[K, V]) (*Heap[K, V], []*Element[K, V]) {
+	h := &Heap[K, V]{}
+	return h, h.InsertAll(pairs)
+}
+
+// InsertAll inserts the key-value pairs of pairs to the heap h with running
+// time Θ(n), and returns the inserted elements in the same order. It is faster
+// than inserting the pairs one by one: the elements are allocated at once,
+// linked into a list which is spliced into the root list, and the minimum is
+// updated in a single pass. Since the elements share one allocation, the memory
+// of all of them is retained while any of them is referenced.
+func (h *HeapOf[K, V, O]) InsertAll(pairs []Pair[K, V]) []*Element[K, V] {
+	h.mustNotNil("InsertAll")
+	if len(pairs) == 0 {
+		return nil
+	}
+	slab := h.insertBatch(pairs)
+	list := make([]*Element[K, V], len(slab))
+	for i := range slab {
+		list[i] = &slab[i]
+	}
+	return list
+}
+
+// InsertBatch is like InsertAll, but does not return the inserted elements,
+// which saves allocating a slice of n pointers. It suits bulk loads of heaps
+// whose elements are only ever extracted, such as the initial events of a
+// simulation, where it costs less than half as much as inserting the pairs one
+// by one. The elements can still be reached through Min, All or ByID.
+func (h *HeapOf[K, V, O]) InsertBatch(pairs []Pair[K, V]) {
+	h.mustNotNil("InsertBatch")
+	if len(pairs) != 0 {
+		h.insertBatch(pairs)
+	}
+}
+
+// insertBatch implements InsertAll and InsertBatch for a non-empty batch, and
+// returns the slab of the inserted elements. The slab is linked into a circular
+// list whose minimum is found while linking it, so the list is spliced into the
+// root list with a single comparison against the minimum of h.
+func (h *HeapOf[K, V, O]) insertBatch(pairs []Pair[K, V]) []Element[K, V] {
+	if h.min == nil {
+		h.mustOrder()
+	}
+	n := len(pairs)
+	slab := make([]Element[K, V], n)
+	owner := h.own()
+	min := &slab[0]
+	for i := range slab {
+		e := &slab[i]
+		e.key = pairs[i].Key
+		e.Value = pairs[i].Value
+		e.owner = owner
+		h.identify(e)
+		if h.stable {
+			h.sequence(e)
+		}
+		e.l = &slab[(i+n-1)%n]
+		e.r = &slab[(i+1)%n]
+		// within the batch, a later element is never before an earlier
+		// one with the same key
+		if h.order.Less(e.key, min.key) {
+			min = e
+		}
+	}
+
+	if h.min != nil {
+		first, last := &slab[0], &slab[n-1]
+		r := h.min.r
+		h.min.r = first
+		first.l = h.min
+		last.r = r
+		r.l = last
+		if !h.before(min, h.min) {
+			min = h.min
+		}
+	}
+	h.min = min
+	h.elements += n
+	h.roots += n
+	h.eagerConsolidate()
+	if h.tracer != nil {
+		op := traceOp[K, V]{Op: opInsertAll, IDs: make([]uint64, n), Pairs: pairs}
+		for i := range slab {
+			op.IDs[i] = slab[i].id
+		}
+		h.record(op)
+	}
+	if h.observer != nil {
+		for range n {
+			h.observer.Inserted()
+		}
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+	return slab
+}
+
+// KeyUpdate is a new key for an element, as given to DecreaseAll.
+type KeyUpdate[K any, V any] struct {
+	Element *Element[K, V]
+	Key     K
+}
+
+// DecreaseAll decreases the keys of many elements at once, such as the vertices
+// whose distance shrinks when a vertex is settled in Dijkstra's algorithm. Every
+// update is applied as Decreasing would, and is skipped if its key is not
+// smaller than the key of the element. The minimum is updated and the
+// callbacks are notified once after all the updates, instead of once per
+// update. An element may be updated several times, in the order of updates.
+// DecreaseAll panics, before applying any update, if an element does not belong
+// to the heap h.
+func (h *HeapOf[K, V, O]) DecreaseAll(updates []KeyUpdate[K, V]) {
+	for _, u := range updates {
+		h.mustContain(u.Element, "DecreaseAll")
+	}
+	if h.tracer != nil && len(updates) != 0 {
+		op := traceOp[K, V]{Op: opDecreaseAll, IDs: make([]uint64, len(updates)), Keys: make([]K, len(updates))}
+		for i, u := range updates {
+			op.IDs[i], op.Keys[i] = u.Element.id, u.Key
+		}
+		h.record(op)
+	}
+	min := h.min
+	for _, u := range updates {
+		x := u.Element
+		if !h.order.Less(u.Key, x.key) {
+			continue
+		}
+		x.key = u.Key
+		if x.flags&(suspended|pinned) != 0 {
+			continue
+		}
+		if p := x.p; p != nil && h.before(x, p) {
+			h.cut(x, p)
+			h.cascadingCut(p)
+		}
+		if h.before(x, min) {
+			min = x
+		}
+	}
+	h.min = min
+	if len(updates) != 0 && h.watches != nil {
+		h.notify()
+	}
+}
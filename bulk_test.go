@@ -0,0 +1,172 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewFromSlice(t *testing.T) {
+	pairs := make([]Pair[int, int], 100)
+	for i := range pairs {
+		pairs[i] = Pair[int, int]{Key: (i * 37) % 100, Value: i}
+	}
+	h, elements := NewFromSlice(pairs)
+	assert(t, h.Size(), 100)
+	assert(t, len(elements), 100)
+	for i, e := range elements {
+		assert(t, e.Value, i)
+	}
+
+	h.Decreasing(elements[99], -1)
+	h.Delete(elements[0])
+	assert(t, h.ExtractMin().Value, 99)
+	for i := 1; i < 100; i++ {
+		if i == (99*37)%100 {
+			continue
+		}
+		assert(t, h.ExtractMin().Key(), i)
+	}
+	if h.ExtractMin() != nil {
+		t.Fatal("heap should be empty")
+	}
+}
+
+func TestHeapInsertAll(t *testing.T) {
+	h := &Heap[int, any]{}
+	h.SetStable(true)
+	h.Insert(5, "first")
+	h.InsertAll([]Pair[int, any]{{10, nil}, {5, "second"}, {0, nil}})
+	h.InsertAll([]Pair[int, any]{{5, "third"}})
+	if h.InsertAll(nil) != nil {
+		t.Fatal("InsertAll(nil) should return nil")
+	}
+	assert(t, h.Size(), 5)
+	assert(t, h.ExtractMin().Key(), 0)
+	for _, expected := range []string{"first", "second", "third"} {
+		if x := h.ExtractMin(); x.Value != expected {
+			t.Fatalf("expected %s, got %v", expected, x.Value)
+		}
+	}
+	assert(t, h.ExtractMin().Key(), 10)
+}
+
+func TestHeapInsertBatch(t *testing.T) {
+	h := &Heap[int, any]{}
+	h.SetStable(true)
+	h.Insert(5, "first")
+	h.InsertBatch([]Pair[int, any]{{10, nil}, {5, "second"}, {7, nil}})
+	h.InsertBatch([]Pair[int, any]{{5, "third"}, {0, nil}})
+	h.InsertBatch(nil)
+	assert(t, h.Size(), 6)
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.ExtractMin().Key(), 0)
+	for _, expected := range []string{"first", "second", "third"} {
+		if x := h.ExtractMin(); x.Value != expected {
+			t.Fatalf("expected %s, got %v", expected, x.Value)
+		}
+	}
+	assert(t, h.ExtractMin().Key(), 7)
+	assert(t, h.ExtractMin().Key(), 10)
+
+	// the minimum of a batch is not the first of its pairs
+	h.InsertBatch([]Pair[int, any]{{3, "a"}, {2, "b"}, {2, "c"}, {4, "d"}})
+	if x := h.Min(); x.Value != "b" {
+		t.Fatalf("expected b, got %v", x.Value)
+	}
+}
+
+func benchmarkPairs(n int) []Pair[int, int] {
+	pairs := make([]Pair[int, int], n)
+	for i := range pairs {
+		pairs[i] = Pair[int, int]{Key: (i * 7919) % n, Value: i}
+	}
+	return pairs
+}
+
+func BenchmarkHeapInsert(b *testing.B) {
+	pairs := benchmarkPairs(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := &Heap[int, int]{}
+		for _, p := range pairs {
+			h.Insert(p.Key, p.Value)
+		}
+	}
+}
+
+func BenchmarkHeapInsertAll(b *testing.B) {
+	pairs := benchmarkPairs(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := &Heap[int, int]{}
+		h.InsertAll(pairs)
+	}
+}
+
+func BenchmarkHeapInsertBatch(b *testing.B) {
+	pairs := benchmarkPairs(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := &Heap[int, int]{}
+		h.InsertBatch(pairs)
+	}
+}
+
+func TestHeapDecreaseAll(t *testing.T) {
+	h := &Heap[int, int]{}
+	r := rand.New(rand.NewSource(1))
+	elements := make([]*Element[int, int], 1000)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(1000), i)
+	}
+	h.ExtractMin()
+	var below []int
+	h.OnMinBelow(-1000, func(min *Element[int, int]) { below = append(below, min.Key()) })
+	h.Pin(elements[0])
+	for round := 0; round < 20; round++ {
+		var updates []KeyUpdate[int, int]
+		for i := 0; i < 50; i++ {
+			x := elements[1+r.Intn(len(elements)-1)]
+			if h.Contains(x) {
+				updates = append(updates, KeyUpdate[int, int]{Element: x, Key: x.Key() - r.Intn(100) + 10})
+			}
+		}
+		expected := map[*Element[int, int]]int{}
+		for _, u := range updates {
+			if _, ok := expected[u.Element]; !ok {
+				expected[u.Element] = u.Element.Key()
+			}
+			expected[u.Element] = min(expected[u.Element], u.Key)
+		}
+		h.DecreaseAll(updates)
+		for x, k := range expected {
+			assert(t, x.Key(), k)
+		}
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+		h.ExtractMin()
+	}
+	h.DecreaseAll([]KeyUpdate[int, int]{{Element: elements[0], Key: -2000}})
+	if h.Min().Key() == -2000 {
+		t.Fatal("a pinned element should not become the minimum")
+	}
+	h.Unpin(elements[0])
+	if len(below) != 1 || below[0] != -2000 {
+		t.Fatalf("expected a single notification, got %v", below)
+	}
+
+	x := h.Min()
+	defer func() {
+		if recover() == nil {
+			t.Error("DecreaseAll should panic on an element of another heap")
+		}
+		if x.Key() == -5000 {
+			t.Error("DecreaseAll should not apply any update before panicking")
+		}
+	}()
+	g := &Heap[int, int]{}
+	h.DecreaseAll([]KeyUpdate[int, int]{{Element: x, Key: -5000}, {Element: g.Insert(0, 0), Key: -1}})
+}
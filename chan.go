@@ -0,0 +1,60 @@
+package fibheap
+
+import (
+	"cmp"
+)
+
+// NewPriorityChan returns the send and the receive ends of a priority channel
+// of keys of an ordered type. See NewPriorityChanFunc.
+func NewPriorityChan[K cmp.Ordered, V any](buffer int) (chan<- Pair[K, V], <-chan Pair[K, V]) {
+	return NewPriorityChanFunc[K, V](buffer, Ordered[K]{}.Less)
+}
+
+// NewPriorityChanFunc returns the send and the receive ends of a priority
+// channel which orders keys with less. Pairs sent to the channel are held in a
+// heap by a goroutine, and every receive yields the pair with the minimum key
+// among the pairs held at that time. At most buffer pairs are held: once the
+// buffer is full, sends block until a pair is received. A non-positive buffer
+// holds any number of pairs.
+//
+// Closing the send end closes the receive end after the held pairs have been
+// received, in ascending order. The goroutine exits then, so the receive end
+// must be drained after the send end is closed.
+func NewPriorityChanFunc[K any, V any](buffer int, less func(a, b K) bool) (chan<- Pair[K, V], <-chan Pair[K, V]) {
+	if less == nil {
+		panic("fibheap: NewPriorityChanFunc expects a non-nil less function")
+	}
+	in := make(chan Pair[K, V])
+	out := make(chan Pair[K, V])
+	go priorityChan(NewHeapFunc[K, V](less), buffer, in, out)
+	return in, out
+}
+
+// priorityChan moves the pairs received from in to out through the heap h, until
+// in is closed and h is empty.
+func priorityChan[K any, V any, O Order[K]](h *HeapOf[K, V, O], buffer int, in <-chan Pair[K, V], out chan<- Pair[K, V]) {
+	defer close(out)
+	for in != nil || h.min != nil {
+		// a nil channel disables its case
+		var recv <-chan Pair[K, V]
+		if buffer <= 0 || h.elements < buffer {
+			recv = in
+		}
+		var send chan<- Pair[K, V]
+		var min Pair[K, V]
+		if h.min != nil {
+			send = out
+			min = h.min.Pair()
+		}
+		select {
+		case p, ok := <-recv:
+			if !ok {
+				in = nil
+				continue
+			}
+			h.Insert(p.Key, p.Value)
+		case send <- min:
+			h.Recycle(h.ExtractMin())
+		}
+	}
+}
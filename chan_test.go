@@ -0,0 +1,48 @@
+package fibheap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriorityChan(t *testing.T) {
+	in, out := NewPriorityChan[int, string](0)
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		in <- Pair[int, string]{Key: k, Value: "v"}
+	}
+	close(in)
+	var keys []int
+	for p := range out {
+		keys = append(keys, p.Key)
+	}
+	expected := []int{1, 2, 3, 5, 8, 9}
+	assert(t, len(keys), len(expected))
+	for i := range expected {
+		assert(t, keys[i], expected[i])
+	}
+}
+
+func TestPriorityChanBuffer(t *testing.T) {
+	in, out := NewPriorityChanFunc[int, int](3, func(a, b int) bool {
+		return a > b
+	})
+	for i := 0; i < 3; i++ {
+		in <- Pair[int, int]{Key: i, Value: i}
+	}
+	select {
+	case in <- Pair[int, int]{Key: 3}:
+		t.Fatal("expected a full priority channel to block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert(t, (<-out).Key, 2)
+	in <- Pair[int, int]{Key: 3}
+	assert(t, (<-out).Key, 3)
+	in <- Pair[int, int]{Key: -1}
+	close(in)
+	for _, expected := range []int{1, 0, -1} {
+		assert(t, (<-out).Key, expected)
+	}
+	if _, ok := <-out; ok {
+		t.Fatal("expected the receive end to be closed")
+	}
+}
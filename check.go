@@ -0,0 +1,136 @@
+package fibheap
+
+import (
+	"fmt"
+)
+
+// Check validates the structure of the heap h with running time O(n), and
+// returns an error describing the first violated invariant, if any. It checks
+// the heap order, the minimum pointer, the parent, child and sibling pointers,
+// the degrees, the marks, which roots and held elements never carry, and their
+// count, the element count, the counts of ExtractRandom, the ownership of the
+// elements and the bookkeeping of pinned and suspended elements. Check is meant
+// for tests and debugging: a heap only used through its methods always passes
+// it.
+func (h *HeapOf[K, V, O]) Check() error {
+	if h == nil {
+		// a nil heap is empty
+		h = &HeapOf[K, V, O]{}
+	}
+	if h.min != nil && h.min.p != nil {
+		return fmt.Errorf("fibheap: minimum element %v has a parent", h.min.key)
+	}
+	if h.min != nil && h.min.flags&tombstone != 0 {
+		return fmt.Errorf("fibheap: minimum element %v is deleted", h.min.key)
+	}
+	count, tombstones := 0, 0
+	roots, err := h.checkList(h.min, nil, &count, &tombstones)
+	if err != nil {
+		return err
+	}
+	if roots != h.roots {
+		return fmt.Errorf("fibheap: found %d roots, but expected %d", roots, h.roots)
+	}
+	if count+len(h.pinned) != h.elements {
+		return fmt.Errorf("fibheap: found %d elements and %d pinned elements, but Size is %d", count, len(h.pinned), h.elements)
+	}
+	if tombstones != h.tombstones {
+		return fmt.Errorf("fibheap: found %d tombstones, but expected %d", tombstones, h.tombstones)
+	}
+	marked := 0
+	walk(h.min, 0, func(e *Element[K, V], _ int) {
+		if e.getMark() {
+			marked++
+		}
+	})
+	if marked != h.marked {
+		return fmt.Errorf("fibheap: found %d marked elements, but expected %d", marked, h.marked)
+	}
+	for e := range h.pinned {
+		if err := h.checkHeld(e, pinned); err != nil {
+			return err
+		}
+	}
+	for e := range h.suspended {
+		if err := h.checkHeld(e, suspended); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkList checks the circular list starting at x, whose elements are children
+// of parent, and their descendants. It returns the length of the list, and adds
+// the number of checked elements and tombstones to count and tombstones.
+func (h *HeapOf[K, V, O]) checkList(x, parent *Element[K, V], count, tombstones *int) (int, error) {
+	if x == nil {
+		return 0, nil
+	}
+	n := 0
+	for e := x; ; {
+		n++
+		if e.flags == tombstone {
+			*tombstones++
+		} else {
+			*count++
+		}
+		if *count+*tombstones > h.elements+h.tombstones {
+			return n, fmt.Errorf("fibheap: found more than %d elements", h.elements+h.tombstones)
+		}
+		if e.l == nil || e.r == nil || e.r.l != e || e.l.r != e {
+			return n, fmt.Errorf("fibheap: broken sibling pointers at element %v", e.key)
+		}
+		if e.p != parent {
+			return n, fmt.Errorf("fibheap: element %v has a wrong parent", e.key)
+		}
+		if e.flags != tombstone && !h.Contains(e) {
+			return n, fmt.Errorf("fibheap: element %v in a tree belongs to another heap", e.key)
+		}
+		if e.flags == tombstone && e.owner != nil {
+			return n, fmt.Errorf("fibheap: deleted element %v still belongs to a heap", e.key)
+		}
+		if e.flags&^tombstone != 0 {
+			return n, fmt.Errorf("fibheap: element %v in a tree is pinned or suspended", e.key)
+		}
+		if parent != nil && h.before(e, parent) {
+			return n, fmt.Errorf("fibheap: element %v is ordered before its parent %v", e.key, parent.key)
+		}
+		if parent == nil && e.getMark() {
+			return n, fmt.Errorf("fibheap: root %v is marked", e.key)
+		}
+		if parent == nil && h.before(e, h.min) {
+			return n, fmt.Errorf("fibheap: root %v is ordered before the minimum %v", e.key, h.min.key)
+		}
+		before := *count + *tombstones
+		degree, err := h.checkList(e.children, e, count, tombstones)
+		if err != nil {
+			return n, err
+		}
+		if degree != e.getDegree() {
+			return n, fmt.Errorf("fibheap: element %v has %d children, but degree %d", e.key, degree, e.getDegree())
+		}
+		if below := *count + *tombstones - before; h.sized && below != e.below {
+			return n, fmt.Errorf("fibheap: element %v has %d elements below it, but counted %d", e.key, below, e.below)
+		}
+		if e = e.r; e == x {
+			return n, nil
+		}
+	}
+}
+
+// checkHeld checks the element e held aside with flag.
+func (h *HeapOf[K, V, O]) checkHeld(e *Element[K, V], flag uint8) error {
+	if !h.Contains(e) {
+		return fmt.Errorf("fibheap: held element %v belongs to another heap", e.key)
+	}
+	if e.flags != flag {
+		return fmt.Errorf("fibheap: held element %v has flags %b instead of %b", e.key, e.flags, flag)
+	}
+	if e.getMark() {
+		return fmt.Errorf("fibheap: held element %v is marked", e.key)
+	}
+	if e.p != nil || e.l != nil || e.r != nil || e.children != nil || e.degree != 0 {
+		return fmt.Errorf("fibheap: held element %v is linked", e.key)
+	}
+	return nil
+}
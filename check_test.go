@@ -0,0 +1,93 @@
+package fibheap
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestHeapCheck(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, any]{}
+	var elements []*Element[int, any]
+	for i := 0; i < 2000; i++ {
+		switch op := r.Intn(10); {
+		case op < 4 || len(elements) == 0:
+			elements = append(elements, h.Insert(r.Intn(1000), nil))
+		case op < 6:
+			h.Decreasing(elements[r.Intn(len(elements))], r.Intn(1000)-500)
+		case op < 7:
+			h.Increasing(elements[r.Intn(len(elements))], r.Intn(1000)+500)
+		case op < 8:
+			i := r.Intn(len(elements))
+			h.Delete(elements[i])
+			elements = append(elements[:i], elements[i+1:]...)
+		default:
+			if x := h.ExtractMin(); x != nil {
+				for i, e := range elements {
+					if e == x {
+						elements = append(elements[:i], elements[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+		if err := h.Check(); err != nil {
+			t.Fatalf("operation %d: %v", i, err)
+		}
+	}
+}
+
+func TestHeapCheckCorrupted(t *testing.T) {
+	corrupt := map[string]func(h *Heap[int, any], elements []*Element[int, any]){
+		"ordered before its parent": func(h *Heap[int, any], elements []*Element[int, any]) {
+			elements[99].key = -1
+		},
+		"ordered before the minimum": func(h *Heap[int, any], elements []*Element[int, any]) {
+			h.min.r.key = -1
+		},
+		"marked": func(h *Heap[int, any], elements []*Element[int, any]) {
+			h.min.r.setMark()
+		},
+		"degree": func(h *Heap[int, any], elements []*Element[int, any]) {
+			parentOf(h).increaseDegree()
+		},
+		"Size": func(h *Heap[int, any], elements []*Element[int, any]) {
+			h.elements++
+		},
+		"sibling": func(h *Heap[int, any], elements []*Element[int, any]) {
+			parentOf(h).children.l = nil
+		},
+		"parent": func(h *Heap[int, any], elements []*Element[int, any]) {
+			parentOf(h).children.p = nil
+		},
+		"pinned": func(h *Heap[int, any], elements []*Element[int, any]) {
+			h.min.flags = pinned
+		},
+	}
+	for name, fn := range corrupt {
+		h := &Heap[int, any]{}
+		elements := make([]*Element[int, any], 100)
+		for i := range elements {
+			elements[i] = h.Insert(i, nil)
+		}
+		h.ExtractMin()
+		h.Insert(1000, nil)
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+		fn(h, elements)
+		if err := h.Check(); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected an error about %q, got %v", name, err)
+		}
+	}
+}
+
+// parentOf returns a root of h that has children.
+func parentOf(h *Heap[int, any]) *Element[int, any] {
+	e := h.min
+	for e.children == nil {
+		e = e.r
+	}
+	return e
+}
@@ -0,0 +1,68 @@
+package fibheap
+
+// Clear removes every element from the heap h in Θ(1), including pinned and
+// suspended elements, so that the heap can be reused for another batch without
+// draining it or allocating a new one. The ordering, the options, the observer
+// and the watches of the heap are kept. The removed elements are not visited:
+// they belong to no heap afterwards, and may be passed to Recycle or
+// Element.Reset. If the heap has an observer, Extracted is called once per
+// removed element, so that the observer sees every element leave the heap.
+func (h *HeapOf[K, V, O]) Clear() {
+	if h == nil {
+		return
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opClear})
+	}
+	if h.observer != nil {
+		for range h.elements + len(h.suspended) {
+			h.observer.Extracted()
+		}
+	}
+	list := h.members()
+	if h.owner != nil {
+		h.owner.cleared = true
+		h.owner = nil
+	}
+	h.min = nil
+	h.elements, h.tombstones, h.roots, h.marked = 0, 0, 0, 0
+	h.suspended, h.pinned, h.ids = nil, nil, nil
+	h.sized = false
+	moved(h.onMove, list, nil)
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// ClearRecycle is like Clear, but hands every removed element to the free list
+// of the heap h as Recycle, in O(n) time, so that later inserts reuse them.
+// Handles to the removed elements must not be used afterwards.
+func (h *HeapOf[K, V, O]) ClearRecycle() {
+	if h == nil {
+		return
+	}
+	list := make([]*Element[K, V], 0, h.elements+len(h.suspended))
+	h.each(func(e *Element[K, V]) bool {
+		list = append(list, e)
+		return true
+	})
+	for e := range h.suspended {
+		list = append(list, e)
+	}
+	h.Clear()
+	for _, e := range list {
+		h.Recycle(e)
+	}
+}
+
+// Reset clears the element x, which belongs to no heap, dropping its key, its
+// value and its links to other elements, so that the structures it refers to
+// can be garbage collected while x itself is kept for reuse. Reset panics if x
+// still belongs to a heap, is a tombstone of a lazily deleted element, or has
+// been recycled.
+func (x *Element[K, V]) Reset() {
+	if x == nil || !x.detached() || x.flags&(tombstone|recycled) != 0 {
+		panic("fibheap: Reset expects an element removed from a heap")
+	}
+	*x = Element[K, V]{}
+}
@@ -0,0 +1,88 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapClear(t *testing.T) {
+	h := &Heap[int, *int]{}
+	h.SetLazyDelete(true)
+	rec := &recorder{}
+	h.SetObserver(rec)
+	var below int
+	h.OnSizeBelow(1, func(int) { below++ })
+	elements := make([]*Element[int, *int], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, new(int))
+	}
+	h.ExtractMin()
+	h.Delete(elements[5])
+	h.Pin(elements[1])
+	h.Suspend(elements[2])
+	g := &Heap[int, *int]{}
+	y := g.Insert(100, nil)
+	h.Meld(g)
+
+	h.Clear()
+	assert(t, h.Size(), 0)
+	assert(t, h.Suspended(), 0)
+	assert(t, h.Tombstones(), 0)
+	assert(t, below, 1)
+	// the extracted, the deleted and the 9 cleared elements
+	assert(t, rec.extracts, 11)
+	if h.Min() != nil || h.Contains(elements[3]) || h.Contains(y) {
+		t.Fatal("the cleared heap should be empty")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the heap and its removed elements are reusable
+	x := h.Insert(7, nil)
+	assert(t, h.Min().Key(), 7)
+	if !h.Contains(x) || h.Contains(elements[3]) {
+		t.Fatal("only the new element should belong to the heap")
+	}
+	elements[3].Reset()
+	if elements[3].Value != nil || elements[3].Key() != 0 {
+		t.Fatal("Reset should clear the element")
+	}
+	h.Recycle(elements[4])
+	if h.Insert(8, nil) != elements[4] {
+		t.Fatal("expected Insert to reuse the recycled element")
+	}
+}
+
+func TestHeapClearRecycle(t *testing.T) {
+	h := &Heap[int, any]{}
+	elements := map[*Element[int, any]]bool{}
+	for i := 0; i < 10; i++ {
+		elements[h.Insert(i, nil)] = true
+	}
+	h.ExtractMin()
+	h.Suspend(h.Min())
+	h.ClearRecycle()
+	assert(t, h.Size(), 0)
+	for i := 0; i < 9; i++ {
+		if !elements[h.Insert(i, nil)] {
+			t.Fatal("expected Insert to reuse the recycled elements")
+		}
+	}
+	if elements[h.Insert(9, nil)] {
+		t.Fatal("expected the free list to be empty")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestElementResetMisuse(t *testing.T) {
+	h := &Heap[int, any]{}
+	x := h.Insert(0, nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("Reset should panic on an element of a heap")
+		}
+	}()
+	x.Reset()
+}
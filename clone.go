@@ -0,0 +1,100 @@
+package fibheap
+
+// Clone returns a deep copy of the heap h with running time O(n). The copy has
+// the same tree structure as h, including suspended and pinned elements, so
+// that operations on it behave exactly as they would on h. Values are copied by
+// assignment, and callbacks registered on h are not copied.
+func (h *HeapOf[K, V, O]) Clone() *HeapOf[K, V, O] {
+	if h == nil {
+		return nil
+	}
+	return h.clone(nil)
+}
+
+// CloneMap is like Clone, but also returns a map from the elements of the heap
+// h to their copies, so that handles held on h can be translated to the copy.
+func (h *HeapOf[K, V, O]) CloneMap() (*HeapOf[K, V, O], map[*Element[K, V]]*Element[K, V]) {
+	if h == nil {
+		return nil, nil
+	}
+	m := make(map[*Element[K, V]]*Element[K, V], h.elements+len(h.suspended))
+	return h.clone(m), m
+}
+
+func (h *HeapOf[K, V, O]) clone(m map[*Element[K, V]]*Element[K, V]) *HeapOf[K, V, O] {
+	c := &HeapOf[K, V, O]{
+		order:      h.order,
+		stable:     h.stable,
+		lazy:       h.lazy,
+		seq:        h.seq,
+		elements:   h.elements,
+		tombstones: h.tombstones,
+		roots:      h.roots,
+		threshold:  h.threshold,
+		marked:     h.marked,
+		sized:      h.sized,
+		owner:      &owner{},
+	}
+	c.min = cloneList(h.min, nil, c.owner, m)
+	if h.suspended != nil {
+		c.suspended = make(map[*Element[K, V]]struct{}, len(h.suspended))
+		for e := range h.suspended {
+			c.suspended[cloneElement(e, nil, c.owner, m)] = struct{}{}
+		}
+	}
+	if h.pinned != nil {
+		c.pinned = make(map[*Element[K, V]]struct{}, len(h.pinned))
+		for e := range h.pinned {
+			c.pinned[cloneElement(e, nil, c.owner, m)] = struct{}{}
+		}
+	}
+	return c
+}
+
+// cloneList copies the circular list starting at x and their descendants, and
+// returns the copy of x. The copies are children of parent and belong to o.
+func cloneList[K any, V any](x, parent *Element[K, V], o *owner, m map[*Element[K, V]]*Element[K, V]) *Element[K, V] {
+	if x == nil {
+		return nil
+	}
+	var first, prev *Element[K, V]
+	for e := x; ; {
+		c := cloneElement(e, parent, o, m)
+		c.children = cloneList(e.children, c, o, m)
+		if first == nil {
+			first = c
+		} else {
+			prev.r = c
+			c.l = prev
+		}
+		prev = c
+		if e = e.r; e == x {
+			break
+		}
+	}
+	prev.r = first
+	first.l = prev
+	return first
+}
+
+// cloneElement copies the element e without its links, except for the parent,
+// and makes the copy belong to o unless e is a tombstone.
+func cloneElement[K any, V any](e, parent *Element[K, V], o *owner, m map[*Element[K, V]]*Element[K, V]) *Element[K, V] {
+	c := &Element[K, V]{
+		p:      parent,
+		degree: e.degree,
+		below:  e.below,
+		flags:  e.flags,
+		seq:    e.seq,
+		id:     e.id,
+		key:    e.key,
+		Value:  e.Value,
+	}
+	if e.flags&tombstone == 0 {
+		c.owner = o
+	}
+	if m != nil {
+		m[e] = c
+	}
+	return c
+}
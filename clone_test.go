@@ -0,0 +1,62 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapClone(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[70], -1)
+
+	c := h.Clone()
+	assert(t, c.Size(), 99)
+	assert(t, c.Min().Key(), -1)
+	if c.Min() == h.Min() {
+		t.Fatal("Clone should copy the elements")
+	}
+
+	// draining the copy leaves the original intact
+	prev := -2
+	for x := c.ExtractMin(); x != nil; x = c.ExtractMin() {
+		if x.Key() <= prev {
+			t.Fatalf("key %d extracted after %d", x.Key(), prev)
+		}
+		prev = x.Key()
+	}
+	assert(t, h.Size(), 99)
+	assert(t, h.ExtractMin().Value, 70)
+}
+
+func TestHeapCloneMap(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 50)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+	h.Pin(elements[10])
+	h.Suspend(elements[20])
+
+	c, m := h.CloneMap()
+	assert(t, len(m), 49)
+	assert(t, c.Pinned(), 1)
+	assert(t, c.Suspended(), 1)
+
+	// handles translated through the map act on the copy
+	c.Decreasing(m[elements[40]], -1)
+	c.Unpin(m[elements[10]])
+	c.Resume(m[elements[20]])
+	assert(t, c.ExtractMin().Value, 40)
+	assert(t, c.ExtractMin().Value, 1)
+	assert(t, h.Min().Key(), 1)
+	assert(t, h.Pinned(), 1)
+
+	list := c.Drain()
+	assert(t, len(list), 47)
+	assert(t, h.Size(), 48)
+}
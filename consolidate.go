@@ -0,0 +1,44 @@
+package fibheap
+
+// SetConsolidateThreshold sets the length of the root list above which Insert,
+// InsertAll, InsertBatch and the melding methods consolidate the heap h at
+// once, instead of leaving the work to the next ExtractMin. A threshold of
+// zero, the default, consolidates only on ExtractMin.
+//
+// Inserting is Θ(1) because the inserted elements wait in the root list, so a
+// heap which is rarely extracted from builds up a long root list, and the next
+// ExtractMin pays for all of it at once. A threshold spreads this work over
+// the inserts instead, which keep the root list at most threshold long, so
+// that ExtractMin only consolidates these roots and those cut by Decreasing
+// since the last insert. The price is a lower throughput, since consolidating
+// often visits the same trees again; BenchmarkHeapConsolidateThreshold
+// measures both sides of the trade-off.
+func (h *HeapOf[K, V, O]) SetConsolidateThreshold(roots int) {
+	h.mustNotNil("SetConsolidateThreshold")
+	if roots < 0 {
+		panic("fibheap: SetConsolidateThreshold expects a non-negative threshold")
+	}
+	h.threshold = roots
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opThreshold, Int: roots})
+	}
+	h.eagerConsolidate()
+}
+
+// ConsolidateThreshold returns the length of the root list above which the
+// heap h is consolidated on insert and meld, or zero if it is only consolidated
+// on ExtractMin.
+func (h *HeapOf[K, V, O]) ConsolidateThreshold() int {
+	if h == nil {
+		return 0
+	}
+	return h.threshold
+}
+
+// eagerConsolidate consolidates the heap h if its root list is longer than its
+// threshold.
+func (h *HeapOf[K, V, O]) eagerConsolidate() {
+	if h.threshold > 0 && h.roots > h.threshold {
+		h.consolidate()
+	}
+}
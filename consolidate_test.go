@@ -0,0 +1,98 @@
+package fibheap
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestHeapConsolidateThreshold(t *testing.T) {
+	h := &Heap[int, int]{}
+	for i := 0; i < 1000; i++ {
+		h.Insert(i, i)
+	}
+	assert(t, h.Stats().Roots, 1000)
+	// setting a threshold consolidates the heap at once
+	h.SetConsolidateThreshold(64)
+	assert(t, h.ConsolidateThreshold(), 64)
+	if roots := h.Stats().Roots; roots > 64 {
+		t.Fatalf("expected at most 64 roots, got %d", roots)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	var elements []*Element[int, int]
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(10); {
+		case op < 7 || len(elements) == 0:
+			elements = append(elements, h.Insert(r.Intn(10000), i))
+		case op < 8:
+			g := &Heap[int, int]{}
+			g.InsertAll(make([]Pair[int, int], r.Intn(100)))
+			h.Meld(g)
+		case op < 9:
+			if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+				h.Decreasing(x, x.Key()-r.Intn(100))
+			}
+		default:
+			h.ExtractMin()
+		}
+		// cuts may lengthen the root list between consolidations
+		if op := i % 100; op == 0 {
+			if err := h.Check(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	h.SetConsolidateThreshold(0)
+	before := h.Stats().Roots
+	h.Insert(0, 0)
+	assert(t, h.Stats().Roots, before+1)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("SetConsolidateThreshold should panic on a negative threshold")
+		}
+	}()
+	h.SetConsolidateThreshold(-1)
+}
+
+func TestHeapConsolidateThresholdInsert(t *testing.T) {
+	h := &Heap[int, any]{}
+	h.SetConsolidateThreshold(10)
+	for i := 0; i < 1000; i++ {
+		h.Insert(i, nil)
+		if roots := h.Stats().Roots; roots > 10 {
+			t.Fatalf("expected at most 10 roots after %d inserts, got %d", i+1, roots)
+		}
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		assert(t, h.ExtractMin().Key(), i)
+	}
+}
+
+// BenchmarkHeapConsolidateThreshold inserts batches of keys between rare
+// extractions, and reports the longest ExtractMin as max-ns/extract besides the
+// throughput.
+func BenchmarkHeapConsolidateThreshold(b *testing.B) {
+	for _, threshold := range []int{0, 64, 1024} {
+		b.Run(fmt.Sprint("threshold=", threshold), func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			h := &Heap[int, int]{}
+			h.SetConsolidateThreshold(threshold)
+			var longest time.Duration
+			for i := 0; i < b.N; i++ {
+				h.Insert(r.Int(), i)
+				if i%10000 == 9999 {
+					start := time.Now()
+					h.ExtractMin()
+					longest = max(longest, time.Since(start))
+				}
+			}
+			b.ReportMetric(float64(longest.Nanoseconds()), "max-ns/extract")
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package fibheap
+
+// MergeDedup moves every element of the heap g into the heap h as Meld does,
+// except that the elements of h and g sharing an ID are kept once, such as the
+// copies of an element held by several shards of a queue, which were decoded
+// from the same encoding or cloned. For every pair of such elements, keep is
+// called with the element of h and the element of g, and returns the one to
+// keep; the other one is deleted from its heap first, as by DeleteWhere. The
+// IDs are matched in O(n + m) time for heaps of n and m elements, including
+// suspended ones. MergeDedup panics, leaving h and g unchanged, if keep returns
+// neither of its arguments, and as Meld does. The function keep must not use
+// the heaps h and g.
+func (h *HeapOf[K, V, O]) MergeDedup(g *HeapOf[K, V, O], keep func(a, b *Element[K, V]) *Element[K, V]) {
+	if h == nil || g == nil {
+		panic("fibheap: MergeDedup expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("fibheap: MergeDedup expects two different heaps")
+	}
+	ids := make(map[uint64]*Element[K, V], g.elements+len(g.suspended))
+	g.each(func(e *Element[K, V]) bool {
+		ids[e.id] = e
+		return true
+	})
+	for e := range g.suspended {
+		ids[e.id] = e
+	}
+	var fromH, fromG map[*Element[K, V]]bool
+	resolve := func(a *Element[K, V]) bool {
+		b := ids[a.id]
+		if b == nil {
+			return true
+		}
+		switch keep(a, b) {
+		case a:
+			if fromG == nil {
+				fromG = make(map[*Element[K, V]]bool)
+			}
+			fromG[b] = true
+		case b:
+			if fromH == nil {
+				fromH = make(map[*Element[K, V]]bool)
+			}
+			fromH[a] = true
+		default:
+			panic("fibheap: MergeDedup expects keep to return one of its arguments")
+		}
+		return true
+	}
+	h.each(resolve)
+	for e := range h.suspended {
+		resolve(e)
+	}
+
+	if fromH != nil {
+		h.deleteWhere(func(e *Element[K, V]) bool { return fromH[e] })
+	}
+	if fromG != nil {
+		g.deleteWhere(func(e *Element[K, V]) bool { return fromG[e] })
+	}
+	h.Meld(g)
+}
@@ -0,0 +1,89 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapMergeDedup(t *testing.T) {
+	h := &Heap[int, string]{}
+	elements := make([]*Element[int, string], 20)
+	for i := range elements {
+		elements[i] = h.Insert(i*10, "h")
+	}
+	h.ExtractMin()
+	h.Suspend(elements[19])
+	// the shard g holds copies of the elements of h, some of them updated
+	g, copies := h.CloneMap()
+	for i, x := range elements[1:] {
+		c := copies[x]
+		switch i % 3 {
+		case 0:
+			c.Value = "g"
+			if i%2 == 0 {
+				g.Decreasing(c, c.Key()-5)
+			}
+		case 1:
+			g.Delete(c)
+		}
+	}
+	g.Insert(1000, "new")
+
+	calls := 0
+	h.MergeDedup(g, func(a, b *Element[int, string]) *Element[int, string] {
+		calls++
+		if a.ID() != b.ID() || a.Value != "h" {
+			t.Fatal("keep should be called with the element of h and of g")
+		}
+		if b.Value == "g" {
+			return b
+		}
+		return a
+	})
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, g.Size(), 0)
+	assert(t, calls, 13)
+	assert(t, h.Size()+h.Suspended(), 20)
+	seen := map[uint64]bool{}
+	for x := range h.Elements() {
+		if seen[x.ID()] {
+			t.Fatalf("the ID %d is held twice", x.ID())
+		}
+		seen[x.ID()] = true
+	}
+	if x := h.ByID(elements[19].ID()); x == nil || x.Value != "g" || h.Suspended() != 1 {
+		t.Fatal("the kept copy of the suspended element should stay suspended")
+	}
+	if h.Contains(elements[1]) || !h.Contains(elements[2]) {
+		t.Fatal("the elements of h should be kept or deleted as keep returned")
+	}
+	assert(t, h.Min().Key(), 5)
+}
+
+func TestHeapMergeDedupMisuse(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.Insert(1, 1)
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Heap[int, int]{}
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	mustPanic(t, "MergeDedup", "fibheap: MergeDedup expects keep", func() {
+		h.MergeDedup(g, func(a, b *Element[int, int]) *Element[int, int] { return nil })
+	})
+	assert(t, h.Size(), 1)
+	assert(t, g.Size(), 1)
+	mustPanic(t, "MergeDedup", "fibheap: MergeDedup expects two", func() { h.MergeDedup(h, nil) })
+	mustPanic(t, "MergeDedup", "fibheap: MergeDedup expects non-nil", func() { h.MergeDedup(nil, nil) })
+
+	decoded := g.Min()
+	h.MergeDedup(g, func(a, b *Element[int, int]) *Element[int, int] { return b })
+	assert(t, h.Size(), 1)
+	if h.Min() != decoded {
+		t.Fatal("the heap should hold the decoded element")
+	}
+}
@@ -0,0 +1,35 @@
+package fibheap
+
+import (
+	"iter"
+	"sort"
+)
+
+// Descend returns an iterator over the elements of the heap h from the largest
+// key to the smallest one. Since the heap only keeps track of its minimum, the
+// iteration takes a snapshot of the heap and sorts it when it starts, in
+// O(n log n) time. The heap is not modified, and modifying it during the
+// iteration does not affect the yielded elements.
+func (h *HeapOf[K, V, O]) Descend() iter.Seq[*Element[K, V]] {
+	return func(yield func(*Element[K, V]) bool) {
+		for _, e := range h.descending() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// descending returns the elements of the heap h from the largest key to the
+// smallest one.
+func (h *HeapOf[K, V, O]) descending() []*Element[K, V] {
+	list := make([]*Element[K, V], 0, h.Size())
+	h.each(func(e *Element[K, V]) bool {
+		list = append(list, e)
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return h.before(list[j], list[i])
+	})
+	return list
+}
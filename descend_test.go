@@ -0,0 +1,39 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapDescend(t *testing.T) {
+	h := &Heap[int, any]{}
+	elements := make([]*Element[int, any], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, nil)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[50], -1)
+
+	var keys []int
+	for e := range h.Descend() {
+		keys = append(keys, e.Key())
+	}
+	assert(t, len(keys), 99)
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] <= keys[i] {
+			t.Fatalf("keys are not descending: %v", keys)
+		}
+	}
+	assert(t, keys[0], 99)
+	assert(t, keys[98], -1)
+	assert(t, h.Size(), 99)
+	assert(t, h.Min().Key(), -1)
+
+	count := 0
+	for e := range h.Descend() {
+		if e.Key() < 90 {
+			break
+		}
+		count++
+	}
+	assert(t, count, 10)
+}
@@ -0,0 +1,72 @@
+package fibheap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dot writes the structure of the heap h to w in the Graphviz DOT language.
+// Every element is drawn with its key and its degree, the trees are drawn with
+// their roots at the same rank, the minimum element is drawn bold and marked
+// elements are filled. Lazily deleted elements are drawn with diagonals. Pinned
+// and suspended elements are drawn dashed and dotted, apart from the trees.
+func (h *HeapOf[K, V, O]) Dot(w io.Writer) error {
+	if h == nil {
+		// a nil heap is empty
+		h = &HeapOf[K, V, O]{}
+	}
+	var b strings.Builder
+	ids := make(map[*Element[K, V]]int)
+	node := func(e *Element[K, V], style string) {
+		id := len(ids)
+		ids[e] = id
+		fmt.Fprintf(&b, "\tn%d [label=\"%s\\nd=%d\"", id, dotEscape(fmt.Sprint(e.key)), e.getDegree())
+		if e.getMark() {
+			style = strings.TrimPrefix(style+",filled", ",")
+		}
+		if style != "" {
+			fmt.Fprintf(&b, " style=\"%s\"", style)
+		}
+		b.WriteString("];\n")
+	}
+
+	b.WriteString("digraph fibheap {\n\tnode [shape=circle];\n")
+	var roots []int
+	walk(h.min, 0, func(e *Element[K, V], depth int) {
+		style := ""
+		if e == h.min {
+			style = "bold"
+		} else if e.flags&tombstone != 0 {
+			style = "diagonals"
+		}
+		node(e, style)
+		if depth == 0 {
+			roots = append(roots, ids[e])
+		} else {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", ids[e.p], ids[e])
+		}
+	})
+	if len(roots) > 1 {
+		b.WriteString("\t{ rank=same;")
+		for _, id := range roots {
+			fmt.Fprintf(&b, " n%d;", id)
+		}
+		b.WriteString(" }\n")
+	}
+	for e := range h.pinned {
+		node(e, "dashed")
+	}
+	for e := range h.suspended {
+		node(e, "dotted")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotEscape escapes s for use in a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,71 @@
+package fibheap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHeapDot(t *testing.T) {
+	h := &Heap[string, any]{}
+	var b strings.Builder
+	if err := h.Dot(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "digraph fibheap {\n\tnode [shape=circle];\n}\n"
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+
+	h.Insert(`"a"`, nil)
+	h.Insert("b", nil)
+	h.Insert("c", nil)
+	h.Insert("d", nil)
+	h.Insert("e", nil)
+	h.ExtractMin()
+	h.Pin(h.Insert("f", nil))
+
+	b.Reset()
+	if err := h.Dot(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected = `digraph fibheap {
+	node [shape=circle];
+	n0 [label="b\nd=2" style="bold"];
+	n1 [label="c\nd=0"];
+	n0 -> n1;
+	n2 [label="d\nd=1"];
+	n0 -> n2;
+	n3 [label="e\nd=0"];
+	n2 -> n3;
+	n4 [label="f\nd=0" style="dashed"];
+}
+`
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, b.String())
+	}
+
+	h.Insert(`"a"`, nil)
+	b.Reset()
+	h.Dot(&b)
+	if !strings.Contains(b.String(), `label="\"a\"\nd=0" style="bold"`) {
+		t.Fatalf("the minimum key should be escaped and bold:\n%s", b.String())
+	}
+	if !strings.Contains(b.String(), "{ rank=same; n0; n1; }") {
+		t.Fatalf("the roots should be at the same rank:\n%s", b.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestHeapDotError(t *testing.T) {
+	h := &Heap[int, any]{}
+	h.Insert(1, nil)
+	if err := h.Dot(failingWriter{}); err == nil {
+		t.Fatal("Dot should return the error of the writer")
+	}
+}
@@ -0,0 +1,230 @@
+package fibheap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// errCorrupted is returned when decoding a heap from malformed data.
+var errCorrupted = errors.New("fibheap: corrupted heap encoding")
+
+// encodedElement is the encoded form of an element. The children of an
+// element follow it in the encoding.
+type encodedElement[K any, V any] struct {
+	Key   K
+	Value V
+	// Degree holds the degree and the mark as Element.degree does.
+	Degree uint32
+	Seq    uint64
+	// ID is zero in encodings of earlier versions, which did not keep IDs.
+	ID uint64
+	// Deleted is set for tombstones left by lazy deletion.
+	Deleted bool
+}
+
+// encodedHeap is the encoded form of a heap. Trees are encoded in pre-order,
+// starting from the tree of the minimum element.
+type encodedHeap[K any, V any] struct {
+	Stable    bool
+	Lazy      bool
+	Seq       uint64
+	Trees     []encodedElement[K, V]
+	Pinned    []encodedElement[K, V]
+	Suspended []encodedElement[K, V]
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The heap h
+// is encoded with encoding/gob, including its tree structure, so that a decoded
+// heap behaves exactly as h. Keys and values must be encodable by encoding/gob.
+func (h *HeapOf[K, V, O]) MarshalBinary() ([]byte, error) {
+	if h == nil {
+		// a nil heap is empty
+		h = &HeapOf[K, V, O]{}
+	}
+	enc := encodedHeap[K, V]{
+		Stable: h.stable,
+		Lazy:   h.lazy,
+		Seq:    h.seq,
+		Trees:  make([]encodedElement[K, V], 0, h.elements-len(h.pinned)+h.tombstones),
+	}
+	walk(h.min, 0, func(e *Element[K, V], _ int) {
+		enc.Trees = append(enc.Trees, encodeElement(e))
+	})
+	for e := range h.pinned {
+		enc.Pinned = append(enc.Pinned, encodeElement(e))
+	}
+	for e := range h.suspended {
+		enc.Suspended = append(enc.Suspended, encodeElement(e))
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(enc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It
+// replaces the contents of the heap h with the heap encoded by MarshalBinary.
+// The heap h keeps its comparison function and callbacks, and the encoded heap
+// must have been ordered by the same comparison function. The decoded heap is
+// validated by Check, and data violating its invariants, such as the heap
+// order, is rejected with an error, leaving h unchanged.
+func (h *HeapOf[K, V, O]) UnmarshalBinary(data []byte) error {
+	h.mustNotNil("UnmarshalBinary")
+	var enc encodedHeap[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&enc); err != nil {
+		return err
+	}
+
+	// elements of earlier encodings get new IDs
+	maxID, maxSeq := uint64(0), enc.Seq
+	for _, list := range [][]encodedElement[K, V]{enc.Trees, enc.Pinned, enc.Suspended} {
+		for i := range list {
+			if list[i].ID == 0 {
+				list[i].ID = h.newID()
+			}
+			maxID = max(maxID, list[i].ID)
+			maxSeq = max(maxSeq, list[i].Seq)
+		}
+	}
+
+	var min *Element[K, V]
+	o := &owner{}
+	trees := enc.Trees
+	roots := 0
+	for len(trees) > 0 {
+		roots++
+		var root *Element[K, V]
+		var err error
+		if root, trees, err = decodeTree(trees, nil, o); err != nil {
+			return err
+		}
+		min = appendLast(min, root)
+	}
+	if min != nil {
+		h.mustOrder()
+	}
+
+	tombstones := 0
+	for _, e := range enc.Trees {
+		if e.Deleted {
+			tombstones++
+		}
+	}
+	marked := 0
+	walk(min, 0, func(e *Element[K, V], _ int) {
+		if e.getMark() {
+			marked++
+		}
+	})
+
+	// the decoded heap is checked before it replaces the contents of h, so
+	// that h is left unchanged by malformed data
+	g := HeapOf[K, V, O]{
+		order:      h.order,
+		stable:     enc.Stable,
+		lazy:       enc.Lazy,
+		seq:        enc.Seq,
+		min:        min,
+		elements:   len(enc.Trees) - tombstones + len(enc.Pinned),
+		tombstones: tombstones,
+		roots:      roots,
+		marked:     marked,
+		pinned:     decodeHeld(enc.Pinned, pinned, o),
+		suspended:  decodeHeld(enc.Suspended, suspended, o),
+		owner:      o,
+	}
+	if g.Check() != nil {
+		return errCorrupted
+	}
+
+	// the elements held so far belong to no heap any more, as after Clear
+	if h.owner != nil {
+		h.owner.cleared = true
+	}
+	h.stable = g.stable
+	h.lazy = g.lazy
+	h.seq = g.seq
+	h.min = g.min
+	h.elements = g.elements
+	h.tombstones = g.tombstones
+	h.roots = g.roots
+	h.marked = g.marked
+	h.pinned = g.pinned
+	h.suspended = g.suspended
+	h.owner = g.owner
+	h.ids, h.sized = nil, false
+	h.reserveIDs(maxID)
+	// elements inserted from now on come after the decoded ones
+	raise(&lastSeq, maxSeq)
+	if h.tracer != nil {
+		h.recordHeap(opSnapshot, h)
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+	return nil
+}
+
+func encodeElement[K any, V any](e *Element[K, V]) encodedElement[K, V] {
+	return encodedElement[K, V]{Key: e.key, Value: e.Value, Degree: e.degree, Seq: e.seq, ID: e.id, Deleted: e.flags&tombstone != 0}
+}
+
+func decodeElement[K any, V any](enc encodedElement[K, V], parent *Element[K, V], o *owner) *Element[K, V] {
+	e := &Element[K, V]{p: parent, key: enc.Key, Value: enc.Value, degree: enc.Degree, seq: enc.Seq, id: enc.ID, owner: o}
+	if enc.Deleted {
+		e.flags, e.owner = tombstone, nil
+	}
+	return e
+}
+
+// decodeTree decodes the tree at the beginning of list, whose root is a child
+// of parent, and returns its root and the rest of list. The decoded elements
+// belong to o.
+func decodeTree[K any, V any](list []encodedElement[K, V], parent *Element[K, V], o *owner) (*Element[K, V], []encodedElement[K, V], error) {
+	if len(list) == 0 {
+		return nil, nil, errCorrupted
+	}
+	x := decodeElement(list[0], parent, o)
+	if parent == nil {
+		// roots may be marked in encodings of earlier versions
+		x.clearMark()
+	}
+	list = list[1:]
+	for i := x.getDegree(); i > 0; i-- {
+		var c *Element[K, V]
+		var err error
+		if c, list, err = decodeTree(list, x, o); err != nil {
+			return nil, nil, err
+		}
+		x.children = appendLast(x.children, c)
+	}
+	return x, list, nil
+}
+
+// appendLast appends the element x at the end of the circular list starting at
+// first, so that decoding keeps the order of the encoded siblings, and returns
+// the first element of the list.
+func appendLast[K any, V any](first, x *Element[K, V]) *Element[K, V] {
+	if first == nil {
+		return first.append(x)
+	}
+	first.l.append(x)
+	return first
+}
+
+// decodeHeld decodes the elements held aside with flag, which belong to o.
+func decodeHeld[K any, V any](list []encodedElement[K, V], flag uint8, o *owner) map[*Element[K, V]]struct{} {
+	if len(list) == 0 {
+		return nil
+	}
+	m := make(map[*Element[K, V]]struct{}, len(list))
+	for _, enc := range list {
+		e := decodeElement(enc, nil, o)
+		e.flags = flag
+		m[e] = struct{}{}
+	}
+	return m
+}
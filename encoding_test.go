@@ -0,0 +1,147 @@
+package fibheap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Heap[int, any])(nil)
+	_ encoding.BinaryUnmarshaler = (*Heap[int, any])(nil)
+)
+
+func TestHeapMarshalBinary(t *testing.T) {
+	h := &Heap[int, string]{}
+	elements := make([]*Element[int, string], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, string(rune('A'+i%26)))
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[90], -1)
+	h.Decreasing(elements[91], -2)
+	h.Pin(elements[50])
+	h.Suspend(elements[60])
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Heap[int, string]{}
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, g.Size(), h.Size())
+	assert(t, g.Pinned(), 1)
+	assert(t, g.Suspended(), 1)
+
+	// the tree structure is preserved
+	hs, gs := NewSampler(h, 1).Sample(), NewSampler(g, 1).Sample()
+	assert(t, gs.Roots, hs.Roots)
+	assert(t, gs.Marked, hs.Marked)
+	assert(t, gs.MaxDegree, hs.MaxDegree)
+
+	for x := h.ExtractMin(); x != nil; x = h.ExtractMin() {
+		y := g.ExtractMin()
+		if y == nil || x.Key() != y.Key() || x.Value != y.Value {
+			t.Fatalf("expected %d %s, got %v", x.Key(), x.Value, y)
+		}
+	}
+	if g.ExtractMin() != nil {
+		t.Fatal("decoded heap has extra elements")
+	}
+}
+
+func TestHeapGob(t *testing.T) {
+	type queue struct {
+		Name string
+		Heap *Heap[string, int]
+	}
+	h := &Heap[string, int]{}
+	h.SetStable(true)
+	h.Insert("b", 1)
+	h.Insert("a", 2)
+	h.Insert("b", 3)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(queue{"jobs", h}); err != nil {
+		t.Fatal(err)
+	}
+	var q queue
+	if err := gob.NewDecoder(&buf).Decode(&q); err != nil {
+		t.Fatal(err)
+	}
+	if !q.Heap.Stable() {
+		t.Fatal("decoded heap should be stable")
+	}
+	for _, expected := range []int{2, 1, 3} {
+		assert(t, q.Heap.ExtractMin().Value, expected)
+	}
+}
+
+func TestHeapUnmarshalBinaryCorrupted(t *testing.T) {
+	h := &Heap[int, any]{}
+	if err := h.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Fatal("UnmarshalBinary should fail on garbage")
+	}
+
+	var buf bytes.Buffer
+	gob.NewEncoder(&buf).Encode(encodedHeap[int, any]{
+		Trees: []encodedElement[int, any]{{Key: 1, Degree: 4}},
+	})
+	if err := h.UnmarshalBinary(buf.Bytes()); err != errCorrupted {
+		t.Fatalf("expected errCorrupted, got %v", err)
+	}
+}
+
+func TestHeapUnmarshalBinaryInvalid(t *testing.T) {
+	for name, enc := range map[string]encodedHeap[int, any]{
+		"heap order": {Trees: []encodedElement[int, any]{{Key: 2, Degree: 2}, {Key: 1}}},
+		"minimum":    {Trees: []encodedElement[int, any]{{Key: 2}, {Key: 1}}},
+		"deleted":    {Trees: []encodedElement[int, any]{{Key: 1, Deleted: true}}},
+	} {
+		h := &Heap[int, any]{}
+		h.Insert(5, nil)
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(enc); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.UnmarshalBinary(buf.Bytes()); err != errCorrupted {
+			t.Fatalf("%s: expected errCorrupted, got %v", name, err)
+		}
+		if h.Size() != 1 || h.Min().Key() != 5 {
+			t.Fatalf("%s: UnmarshalBinary should leave the heap unchanged", name)
+		}
+		if err := h.Check(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestHeapUnmarshalBinaryDetaches(t *testing.T) {
+	g := &Heap[int, int]{}
+	g.Insert(1, 1)
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Heap[int, int]{}
+	x := h.Insert(2, 2)
+	y := h.Insert(3, 3)
+	h.Suspend(y)
+	if err := h.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if h.Contains(x) || h.Contains(y) {
+		t.Fatal("expected the replaced elements to leave the heap")
+	}
+	// the replaced elements can be reused as extracted ones
+	h.Recycle(x)
+	y.Reset()
+	assert(t, h.Size(), 1)
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,147 @@
+package fibheap
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEmptyHeap is returned when extracting from an empty heap.
+	ErrEmptyHeap = errors.New("fibheap: heap is empty")
+	// ErrElementNotInHeap is returned when an element was already extracted or
+	// deleted, or belongs to another heap.
+	ErrElementNotInHeap = errors.New("fibheap: element is not in the heap")
+	// ErrElementHeld is returned when an operation does not apply to a
+	// suspended or pinned element.
+	ErrElementHeld = errors.New("fibheap: element is suspended or pinned")
+	// ErrElementNotPinned is returned when unpinning an element which is not
+	// pinned.
+	ErrElementNotPinned = errors.New("fibheap: element is not pinned")
+	// ErrElementNotSuspended is returned when resuming an element which is not
+	// suspended.
+	ErrElementNotSuspended = errors.New("fibheap: element is not suspended")
+	// ErrKeyNotSmaller is returned when a key expected to be smaller is not.
+	ErrKeyNotSmaller = errors.New("fibheap: key is not smaller")
+	// ErrKeyNotLarger is returned when a key expected to be larger is not.
+	ErrKeyNotLarger = errors.New("fibheap: key is not larger")
+)
+
+// TryExtractMin is like ExtractMin, but returns ErrEmptyHeap if there is no
+// element to extract, including when h is nil.
+func (h *HeapOf[K, V, O]) TryExtractMin() (*Element[K, V], error) {
+	if h == nil || h.min == nil {
+		return nil, ErrEmptyHeap
+	}
+	return h.ExtractMin(), nil
+}
+
+// TryDecreasing is like Decreasing, but leaves the heap h unchanged and returns
+// ErrElementNotInHeap if x does not belong to h, or ErrKeyNotSmaller if key is
+// not smaller than the key of x.
+func (h *HeapOf[K, V, O]) TryDecreasing(x *Element[K, V], key K) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if !h.order.Less(key, x.key) {
+		return ErrKeyNotSmaller
+	}
+	h.Decreasing(x, key)
+	return nil
+}
+
+// TryIncreasing is like Increasing, but leaves the heap h unchanged and returns
+// ErrElementNotInHeap if x does not belong to h, or ErrKeyNotLarger if key is
+// not larger than the key of x.
+func (h *HeapOf[K, V, O]) TryIncreasing(x *Element[K, V], key K) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if !h.order.Less(x.key, key) {
+		return ErrKeyNotLarger
+	}
+	h.Increasing(x, key)
+	return nil
+}
+
+// TryRemove is like Remove, but leaves the heap h unchanged and returns an error
+// instead of panicking: ErrElementNotInHeap if x does not belong to h,
+// ErrElementHeld if x is suspended or pinned, and ErrKeyNotSmaller if
+// minimumKey is not smaller than every key in h.
+func (h *HeapOf[K, V, O]) TryRemove(x *Element[K, V], minimumKey K) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if x.flags&(suspended|pinned) != 0 {
+		return ErrElementHeld
+	}
+	if x != h.min && !h.order.Less(minimumKey, h.min.key) {
+		return ErrKeyNotSmaller
+	}
+	h.Remove(x, minimumKey)
+	return nil
+}
+
+// TryDelete is like Delete, but returns ErrElementNotInHeap instead of
+// panicking if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) TryDelete(x *Element[K, V]) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	h.Delete(x)
+	return nil
+}
+
+// TryPin is like Pin, but leaves the heap h unchanged and returns an error
+// instead of panicking: ErrElementNotInHeap if x does not belong to h, and
+// ErrElementHeld if x is already suspended or pinned.
+func (h *HeapOf[K, V, O]) TryPin(x *Element[K, V]) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if x.flags&(suspended|pinned) != 0 {
+		return ErrElementHeld
+	}
+	h.Pin(x)
+	return nil
+}
+
+// TryUnpin is like Unpin, but leaves the heap h unchanged and returns an error
+// instead of panicking: ErrElementNotInHeap if x does not belong to h, and
+// ErrElementNotPinned if x is not pinned.
+func (h *HeapOf[K, V, O]) TryUnpin(x *Element[K, V]) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if x.flags&pinned == 0 {
+		return ErrElementNotPinned
+	}
+	h.Unpin(x)
+	return nil
+}
+
+// TrySuspend is like Suspend, but leaves the heap h unchanged and returns an
+// error instead of panicking: ErrElementNotInHeap if x does not belong to h,
+// and ErrElementHeld if x is already suspended or pinned.
+func (h *HeapOf[K, V, O]) TrySuspend(x *Element[K, V]) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if x.flags&(suspended|pinned) != 0 {
+		return ErrElementHeld
+	}
+	h.Suspend(x)
+	return nil
+}
+
+// TryResume is like Resume, but leaves the heap h unchanged and returns an error
+// instead of panicking: ErrElementNotInHeap if x does not belong to h, and
+// ErrElementNotSuspended if x is not suspended.
+func (h *HeapOf[K, V, O]) TryResume(x *Element[K, V]) error {
+	if !h.Contains(x) {
+		return ErrElementNotInHeap
+	}
+	if x.flags&suspended == 0 {
+		return ErrElementNotSuspended
+	}
+	h.Resume(x)
+	return nil
+}
@@ -0,0 +1,92 @@
+package fibheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func checkErr(t *testing.T, err, expected error) {
+	t.Helper()
+	if !errors.Is(err, expected) {
+		t.Errorf("got error %v, expected %v", err, expected)
+	}
+}
+
+func TestHeapTry(t *testing.T) {
+	h := &Heap[int, any]{}
+	_, err := h.TryExtractMin()
+	checkErr(t, err, ErrEmptyHeap)
+
+	g := &Heap[int, any]{}
+	foreign := g.Insert(0, nil)
+	x := h.Insert(10, nil)
+	y := h.Insert(20, nil)
+	z := h.Insert(30, nil)
+
+	checkErr(t, h.TryDecreasing(foreign, -1), ErrElementNotInHeap)
+	checkErr(t, h.TryDecreasing(y, 20), ErrKeyNotSmaller)
+	checkErr(t, h.TryDecreasing(y, 5), nil)
+	assert(t, h.Min().Key(), 5)
+
+	checkErr(t, h.TryIncreasing(foreign, 1), ErrElementNotInHeap)
+	checkErr(t, h.TryIncreasing(y, 5), ErrKeyNotLarger)
+	checkErr(t, h.TryIncreasing(y, 40), nil)
+	assert(t, h.Min().Key(), 10)
+
+	checkErr(t, h.TryRemove(z, 10), ErrKeyNotSmaller)
+	h.Pin(z)
+	checkErr(t, h.TryRemove(z, 0), ErrElementHeld)
+	h.Unpin(z)
+	checkErr(t, h.TryRemove(z, 0), nil)
+	checkErr(t, h.TryRemove(z, 0), ErrElementNotInHeap)
+	// the minimum may be removed with any key
+	checkErr(t, h.TryRemove(x, 10), nil)
+	assert(t, h.Size(), 1)
+
+	checkErr(t, h.TryDelete(foreign), ErrElementNotInHeap)
+	checkErr(t, h.TryDelete(y), nil)
+	checkErr(t, h.TryDelete(y), ErrElementNotInHeap)
+	assert(t, h.Size(), 0)
+
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, g.Size(), 1)
+}
+
+func TestHeapTryHeld(t *testing.T) {
+	var nilHeap *Heap[int, any]
+	_, err := nilHeap.TryExtractMin()
+	checkErr(t, err, ErrEmptyHeap)
+	checkErr(t, nilHeap.TryDelete(&Element[int, any]{}), ErrElementNotInHeap)
+
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	foreign := g.Insert(0, nil)
+	x := h.Insert(10, nil)
+	y := h.Insert(20, nil)
+
+	checkErr(t, h.TryPin(foreign), ErrElementNotInHeap)
+	checkErr(t, h.TryUnpin(x), ErrElementNotPinned)
+	checkErr(t, h.TryPin(x), nil)
+	checkErr(t, h.TryPin(x), ErrElementHeld)
+	checkErr(t, h.TrySuspend(x), ErrElementHeld)
+	checkErr(t, h.TryResume(x), ErrElementNotSuspended)
+	assert(t, h.Min().Key(), 20)
+	checkErr(t, h.TryUnpin(x), nil)
+	assert(t, h.Min().Key(), 10)
+
+	checkErr(t, h.TrySuspend(foreign), ErrElementNotInHeap)
+	checkErr(t, h.TryResume(y), ErrElementNotSuspended)
+	checkErr(t, h.TrySuspend(y), nil)
+	checkErr(t, h.TrySuspend(y), ErrElementHeld)
+	checkErr(t, h.TryPin(y), ErrElementHeld)
+	checkErr(t, h.TryUnpin(y), ErrElementNotPinned)
+	assert(t, h.Size(), 1)
+	checkErr(t, h.TryResume(y), nil)
+	assert(t, h.Size(), 2)
+
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
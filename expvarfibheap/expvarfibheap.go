@@ -0,0 +1,82 @@
+// Package expvarfibheap exports the operations of fibonacci heaps with expvar.
+// An Observer set on a heap by SetObserver counts its operations and the time
+// spent consolidating, and publishes them as a map of variables, served as JSON
+// at /debug/vars by the expvar handler.
+package expvarfibheap
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Observer is a fibheap.Observer which records the operations of the heaps it
+// is set on into expvar variables. It is safe for concurrent use, so a single
+// observer may be shared by several heaps.
+type Observer struct {
+	vars           expvar.Map
+	inserts        expvar.Int
+	extracts       expvar.Int
+	cuts           expvar.Int
+	consolidations expvar.Int
+	links          expvar.Int
+	consolidating  expvar.Float
+}
+
+var _ fibheap.Observer = (*Observer)(nil)
+
+// New returns an observer whose variables are not published yet.
+func New() *Observer {
+	o := &Observer{}
+	o.vars.Init()
+	o.vars.Set("inserts", &o.inserts)
+	o.vars.Set("extracts", &o.extracts)
+	o.vars.Set("cuts", &o.cuts)
+	o.vars.Set("consolidations", &o.consolidations)
+	o.vars.Set("links", &o.links)
+	o.vars.Set("consolidate_seconds", &o.consolidating)
+	return o
+}
+
+// Publish returns a new observer whose variables are published under name. Like
+// expvar.Publish, Publish panics if name is already in use.
+func Publish(name string) *Observer {
+	o := New()
+	expvar.Publish(name, o.Vars())
+	return o
+}
+
+// Vars returns the variables of the observer o, which can be published or
+// nested into another map:
+//   - inserts: number of inserted elements
+//   - extracts: number of elements extracted or deleted
+//   - cuts: number of elements cut from their parent
+//   - consolidations: number of consolidations of the root list
+//   - links: number of trees linked by consolidations
+//   - consolidate_seconds: total time spent consolidating
+func (o *Observer) Vars() *expvar.Map {
+	return &o.vars
+}
+
+// Inserted implements fibheap.Observer.
+func (o *Observer) Inserted() {
+	o.inserts.Add(1)
+}
+
+// Extracted implements fibheap.Observer.
+func (o *Observer) Extracted() {
+	o.extracts.Add(1)
+}
+
+// Consolidated implements fibheap.Observer.
+func (o *Observer) Consolidated(roots, links int, elapsed time.Duration) {
+	o.consolidations.Add(1)
+	o.links.Add(int64(links))
+	o.consolidating.Add(elapsed.Seconds())
+}
+
+// Cut implements fibheap.Observer.
+func (o *Observer) Cut() {
+	o.cuts.Add(1)
+}
@@ -0,0 +1,43 @@
+package expvarfibheap
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+func TestObserver(t *testing.T) {
+	o := Publish("fibheap_test")
+	if expvar.Get("fibheap_test") != o.Vars() {
+		t.Fatal("expected the variables to be published")
+	}
+	h := &fibheap.Heap[int, string]{}
+	h.SetObserver(o)
+	elements := make([]*fibheap.Element[int, string], 16)
+	for i := range elements {
+		elements[i] = h.Insert(i, "")
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[15], -1)
+	h.Delete(elements[7])
+
+	var vars map[string]float64
+	if err := json.Unmarshal([]byte(o.Vars().String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[string]float64{
+		"inserts":        16,
+		"extracts":       2,
+		"cuts":           2,
+		"consolidations": 2,
+	} {
+		if vars[name] != expected {
+			t.Errorf("expected %s to be %v, got %v", name, expected, vars[name])
+		}
+	}
+	if vars["links"] == 0 {
+		t.Errorf("expected links to be recorded, got %v", vars)
+	}
+}
@@ -0,0 +1,103 @@
+// Package flushbuf implements a write-coalescing flush buffer. Pending writes
+// are keyed by their target (an offset, a page number, a partition, ...) and
+// flushed in key order, so that the writer sees mostly sequential targets.
+// Writes to a target that is already pending are coalesced into a single entry.
+package flushbuf
+
+import (
+	"cmp"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Entry is a pending write.
+type Entry[K cmp.Ordered, V any] struct {
+	Key   K
+	Value V
+}
+
+// Buffer represents the write-coalescing flush buffer. The zero value is an
+// empty buffer in which a later write to a pending key replaces the earlier one.
+type Buffer[K cmp.Ordered, V any] struct {
+	pending fibheap.Heap[K, V]
+	index   map[K]*fibheap.Element[K, V]
+	merge   func(old, new V) V
+}
+
+// New returns an empty buffer which coalesces a write to a pending key by
+// storing merge(old, new) as the pending value.
+func New[K cmp.Ordered, V any](merge func(old, new V) V) *Buffer[K, V] {
+	return &Buffer[K, V]{merge: merge}
+}
+
+// Len returns the number of pending entries in the buffer b.
+func (b *Buffer[K, V]) Len() int {
+	return b.pending.Size()
+}
+
+// Write adds a pending write of value to key. If key is already pending, the
+// write is coalesced into the pending entry. Write panics if key is a NaN,
+// which is equal to no key and thus could be neither coalesced nor flushed from
+// the index.
+func (b *Buffer[K, V]) Write(key K, value V) {
+	if key != key {
+		panic("flushbuf: Write expects a key which is not NaN")
+	}
+	if e, ok := b.index[key]; ok {
+		if b.merge != nil {
+			value = b.merge(e.Value, value)
+		}
+		e.Value = value
+		return
+	}
+	if b.index == nil {
+		b.index = make(map[K]*fibheap.Element[K, V])
+	}
+	b.index[key] = b.pending.Insert(key, value)
+}
+
+// Get returns the pending value of key. The boolean is false if key is not
+// pending.
+func (b *Buffer[K, V]) Get(key K) (V, bool) {
+	if e, ok := b.index[key]; ok {
+		return e.Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Flush removes and returns at most n pending entries with the smallest keys, in
+// key order. A negative n flushes every pending entry.
+func (b *Buffer[K, V]) Flush(n int) []Entry[K, V] {
+	if n < 0 || n > b.Len() {
+		n = b.Len()
+	}
+	if n == 0 {
+		return nil
+	}
+	entries := make([]Entry[K, V], n)
+	for i := range entries {
+		entries[i] = b.take(b.pending.ExtractMin())
+	}
+	return entries
+}
+
+// FlushUntil removes and returns, in key order, every pending entry whose key is
+// less than or equal to key.
+func (b *Buffer[K, V]) FlushUntil(key K) []Entry[K, V] {
+	list := b.pending.ExtractUntil(key)
+	if len(list) == 0 {
+		return nil
+	}
+	entries := make([]Entry[K, V], len(list))
+	for i, e := range list {
+		entries[i] = b.take(e)
+	}
+	return entries
+}
+
+// take removes the extracted element e from the index.
+func (b *Buffer[K, V]) take(e *fibheap.Element[K, V]) Entry[K, V] {
+	delete(b.index, e.Key())
+	return Entry[K, V]{Key: e.Key(), Value: e.Value}
+}
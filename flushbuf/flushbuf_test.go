@@ -0,0 +1,88 @@
+package flushbuf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBufferCoalesce(t *testing.T) {
+	b := &Buffer[int, string]{}
+	b.Write(3, "c")
+	b.Write(1, "a")
+	b.Write(3, "C")
+	b.Write(2, "b")
+	if b.Len() != 3 {
+		t.Fatalf("expected 3 pending entries, got %d", b.Len())
+	}
+	if v, ok := b.Get(3); !ok || v != "C" {
+		t.Fatalf("expected coalesced value C, got %q", v)
+	}
+
+	entries := b.Flush(-1)
+	expected := []Entry[int, string]{{1, "a"}, {2, "b"}, {3, "C"}}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, entries)
+		}
+	}
+	if _, ok := b.Get(3); ok {
+		t.Fatal("flushed key should not be pending")
+	}
+}
+
+func TestBufferMerge(t *testing.T) {
+	b := New[int](func(old, new []byte) []byte {
+		return append(old, new...)
+	})
+	b.Write(7, []byte("hello "))
+	b.Write(7, []byte("world"))
+	entries := b.Flush(1)
+	if len(entries) != 1 || string(entries[0].Value) != "hello world" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+}
+
+func TestBufferFlushBatches(t *testing.T) {
+	b := &Buffer[int, int]{}
+	for i := 99; i >= 0; i-- {
+		b.Write(i, i)
+	}
+	for batch := 0; batch < 10; batch++ {
+		entries := b.Flush(10)
+		if len(entries) != 10 {
+			t.Fatalf("expected a batch of 10, got %d", len(entries))
+		}
+		for i, e := range entries {
+			if e.Key != batch*10+i {
+				t.Fatalf("expected key %d, got %d", batch*10+i, e.Key)
+			}
+		}
+	}
+	if entries := b.Flush(10); entries != nil {
+		t.Fatal("empty buffer should flush nothing")
+	}
+
+	for i := 0; i < 10; i++ {
+		b.Write(i, i)
+	}
+	if entries := b.FlushUntil(4); len(entries) != 5 || b.Len() != 5 {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	b.Write(0, 0)
+	if b.Len() != 6 {
+		t.Fatal("flushed key should be writable again")
+	}
+}
+
+func TestBufferNaN(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Write should panic on a NaN key")
+		}
+	}()
+	b := &Buffer[float64, string]{}
+	b.Write(math.NaN(), "")
+}
@@ -0,0 +1,89 @@
+package fibheap
+
+import (
+	"slices"
+	"testing"
+)
+
+// FuzzHeap replays the operations encoded in data against a heap and a sorted
+// slice, and asserts that both agree after every operation.
+func FuzzHeap(f *testing.F) {
+	f.Add([]byte{0, 5, 0, 3, 0, 5, 1, 0, 2, 0, 1, 3, 0, 4, 2, 1})
+	f.Add([]byte{4, 3, 7, 0, 1, 0, 2, 4, 2, 6, 1, 0, 2, 1, 5, 3, 0, 1, 1, 1})
+	f.Add([]byte{0, 9, 0, 9, 0, 9, 4, 2, 9, 1, 2, 0, 0, 3, 1, 1, 1, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := &Heap[int, int]{}
+		var elements []*Element[int, int]
+		// model holds the keys of the elements in h in ascending order.
+		var model []int
+		next := func() int {
+			if len(data) == 0 {
+				return 0
+			}
+			b := data[0]
+			data = data[1:]
+			return int(b)
+		}
+		insert := func(h *Heap[int, int], key int) {
+			elements = append(elements, h.Insert(key, len(elements)))
+			i, _ := slices.BinarySearch(model, key)
+			model = slices.Insert(model, i, key)
+		}
+		remove := func(key int) {
+			i, found := slices.BinarySearch(model, key)
+			if !found {
+				t.Fatalf("key %d is not in the model", key)
+			}
+			model = slices.Delete(model, i, i+1)
+		}
+		// pick returns the index of a live element chosen by the next byte.
+		pick := func() int {
+			i := next() % len(elements)
+			for elements[i] == nil {
+				i = (i + 1) % len(elements)
+			}
+			return i
+		}
+		for len(data) > 0 {
+			switch op := next() % 5; {
+			case op == 0 || len(model) == 0:
+				insert(h, next()%16)
+			case op == 1:
+				x := h.ExtractMin()
+				if x.Key() != model[0] {
+					t.Fatalf("ExtractMin returned %d, expected %d", x.Key(), model[0])
+				}
+				model = model[1:]
+				elements[x.Value] = nil
+			case op == 2:
+				i := pick()
+				x := elements[i]
+				key := x.Key() - next()%8
+				remove(x.Key())
+				h.Decreasing(x, key)
+				j, _ := slices.BinarySearch(model, key)
+				model = slices.Insert(model, j, key)
+			case op == 3:
+				i := pick()
+				remove(elements[i].Key())
+				h.Delete(elements[i])
+				elements[i] = nil
+			default:
+				g := &Heap[int, int]{}
+				for n := next() % 4; n > 0; n-- {
+					insert(g, next()%16)
+				}
+				h = h.Union(g)
+			}
+			if err := h.Check(); err != nil {
+				t.Fatal(err)
+			}
+			if h.Size() != len(model) {
+				t.Fatalf("Size is %d, expected %d", h.Size(), len(model))
+			}
+			if min := h.Min(); len(model) > 0 && min.Key() != model[0] {
+				t.Fatalf("Min is %d, expected %d", min.Key(), model[0])
+			}
+		}
+	})
+}
@@ -1,5 +1,3 @@
 module github.com/ksw2000/go-fibheap
 
-go 1.20
-
-require golang.org/x/exp v0.0.0-20240525044651-4c93da0ed11d
+go 1.24
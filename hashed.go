@@ -0,0 +1,130 @@
+package fibheap
+
+import "cmp"
+
+// HashedHeap is a heap whose elements are identified by IDs of a comparable
+// type instead of element handles, such as the vertices of a graph in
+// Dijkstra's algorithm. It keeps a map from every ID to its element, so that
+// the key of an ID is decreased, fetched or deleted without keeping track of
+// the handles returned by Insert. An ID is held at most once in the heap. The
+// keys of a HashedHeap are of an ordered type, and the zero value is an empty
+// heap.
+type HashedHeap[ID comparable, K cmp.Ordered, V any] = HashedHeapOf[ID, K, V, Ordered[K]]
+
+// HashedHeapFunc is a HashedHeap whose keys are ordered by a comparison
+// function. It must be created by NewHashedHeapFunc.
+type HashedHeapFunc[ID comparable, K any, V any] = HashedHeapOf[ID, K, V, Func[K]]
+
+// HashedHeapOf is a HashedHeap whose keys are ordered by O. It is used through
+// its aliases HashedHeap and HashedHeapFunc.
+type HashedHeapOf[ID comparable, K any, V any, O Order[K]] struct {
+	heap HeapOf[K, hashed[ID, V], O]
+	ids  map[ID]*Element[K, hashed[ID, V]]
+}
+
+// hashed is the value of an element of a HashedHeap, which remembers its ID so
+// that the ID is removed from the map when the element is extracted.
+type hashed[ID comparable, V any] struct {
+	id    ID
+	value V
+}
+
+// NewHashedHeapFunc returns an empty heap identifying elements by IDs, which
+// orders keys with less.
+func NewHashedHeapFunc[ID comparable, K any, V any](less func(a, b K) bool) *HashedHeapFunc[ID, K, V] {
+	if less == nil {
+		panic("fibheap: NewHashedHeapFunc expects a non-nil less function")
+	}
+	h := &HashedHeapFunc[ID, K, V]{}
+	h.heap.order = less
+	return h
+}
+
+// Size returns the number of IDs in the heap h
+func (h *HashedHeapOf[ID, K, V, O]) Size() int {
+	return len(h.ids)
+}
+
+// Contains reports whether the heap h holds the ID id.
+func (h *HashedHeapOf[ID, K, V, O]) Contains(id ID) bool {
+	_, ok := h.ids[id]
+	return ok
+}
+
+// Insert inserts the ID id with the key-value pair (key, value) to the heap h
+// with amortized running time Θ(1), and reports whether it was inserted. If
+// the heap already holds id, it is left unchanged and Insert returns false.
+func (h *HashedHeapOf[ID, K, V, O]) Insert(id ID, key K, value V) bool {
+	if _, ok := h.ids[id]; ok {
+		return false
+	}
+	if h.ids == nil {
+		h.ids = make(map[ID]*Element[K, hashed[ID, V]])
+	}
+	h.ids[id] = h.heap.Insert(key, hashed[ID, V]{id: id, value: value})
+	return true
+}
+
+// Min fetches the ID with the minimum key from the heap h with running time
+// Θ(1). The boolean ok is false if the heap is empty.
+func (h *HashedHeapOf[ID, K, V, O]) Min() (id ID, key K, value V, ok bool) {
+	x := h.heap.Min()
+	if x == nil {
+		return id, key, value, false
+	}
+	return x.Value.id, x.key, x.Value.value, true
+}
+
+// ExtractMin fetches and removes the ID with the minimum key from the heap h
+// with amortized running time O(log n). The boolean ok is false if the heap is
+// empty.
+func (h *HashedHeapOf[ID, K, V, O]) ExtractMin() (id ID, key K, value V, ok bool) {
+	x := h.heap.ExtractMin()
+	if x == nil {
+		return id, key, value, false
+	}
+	delete(h.ids, x.Value.id)
+	return x.Value.id, x.key, x.Value.value, true
+}
+
+// GetByID returns the key and the value of the ID id. The boolean ok is false
+// if the heap h does not hold id.
+func (h *HashedHeapOf[ID, K, V, O]) GetByID(id ID) (key K, value V, ok bool) {
+	x, ok := h.ids[id]
+	if !ok {
+		return key, value, false
+	}
+	return x.key, x.Value.value, true
+}
+
+// DecreaseByID decreases the key of the ID id with amortized running time
+// Θ(1), and reports whether the heap h holds id. If the new key is larger or
+// equal than the key of id, the key is left unchanged.
+func (h *HashedHeapOf[ID, K, V, O]) DecreaseByID(id ID, key K) bool {
+	x, ok := h.ids[id]
+	if ok {
+		h.heap.Decreasing(x, key)
+	}
+	return ok
+}
+
+// UpdateByID changes the key of the ID id to key, decreasing or increasing it
+// as Heap.Update does, and reports whether the heap h holds id.
+func (h *HashedHeapOf[ID, K, V, O]) UpdateByID(id ID, key K) bool {
+	x, ok := h.ids[id]
+	if ok {
+		h.heap.Update(x, key)
+	}
+	return ok
+}
+
+// DeleteByID removes the ID id from the heap h with amortized running time
+// O(log n), and reports whether the heap held id.
+func (h *HashedHeapOf[ID, K, V, O]) DeleteByID(id ID) bool {
+	x, ok := h.ids[id]
+	if ok {
+		h.heap.Delete(x)
+		delete(h.ids, id)
+	}
+	return ok
+}
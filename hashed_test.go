@@ -0,0 +1,90 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHashedHeap(t *testing.T) {
+	h := &HashedHeap[string, int, string]{}
+	if _, _, _, ok := h.ExtractMin(); ok {
+		t.Fatal("ExtractMin on empty heap should fail")
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if !h.Insert(id, 10*(i+1), id+id) {
+			t.Fatalf("Insert should insert %q", id)
+		}
+	}
+	if h.Insert("a", 0, "") {
+		t.Fatal("Insert should not insert an ID twice")
+	}
+	assert(t, h.Size(), 4)
+	if !h.DecreaseByID("c", 5) || h.DecreaseByID("z", 0) {
+		t.Fatal("DecreaseByID should report whether the ID is held")
+	}
+	if id, key, value, ok := h.Min(); !ok || id != "c" || key != 5 || value != "cc" {
+		t.Fatalf("expected the minimum c, got %q", id)
+	}
+	if !h.UpdateByID("c", 100) || h.UpdateByID("z", 0) {
+		t.Fatal("UpdateByID should report whether the ID is held")
+	}
+	if key, value, ok := h.GetByID("c"); !ok || key != 100 || value != "cc" {
+		t.Fatalf("expected c with key 100, got %d", key)
+	}
+	if !h.DeleteByID("a") || h.DeleteByID("a") || h.Contains("a") {
+		t.Fatal("DeleteByID should delete a once")
+	}
+	for _, expected := range []string{"b", "d", "c"} {
+		if id, _, _, ok := h.ExtractMin(); !ok || id != expected {
+			t.Fatalf("expected %q, got %q", expected, id)
+		}
+	}
+	if _, _, ok := h.GetByID("b"); ok || h.Size() != 0 {
+		t.Fatal("extracted IDs should be forgotten")
+	}
+	// an extracted ID may be inserted again
+	if !h.Insert("b", 1, "") {
+		t.Fatal("Insert should insert an extracted ID")
+	}
+}
+
+func TestHashedHeapFunc(t *testing.T) {
+	h := NewHashedHeapFunc[int, int, any](func(a, b int) bool { return a > b })
+	r := rand.New(rand.NewSource(1))
+	keys := map[int]int{}
+	for i := 0; i < 1000; i++ {
+		id := r.Intn(100)
+		switch key := r.Intn(1000); r.Intn(3) {
+		case 0:
+			if h.Insert(id, key, nil) {
+				keys[id] = key
+			}
+		case 1:
+			if h.UpdateByID(id, key) {
+				keys[id] = key
+			}
+		case 2:
+			h.DeleteByID(id)
+			delete(keys, id)
+		}
+		assert(t, h.Size(), len(keys))
+	}
+	last := 1 << 20
+	for h.Size() > 0 {
+		id, key, _, _ := h.ExtractMin()
+		if key > last || keys[id] != key {
+			t.Fatalf("unexpected key %d of %d after %d", key, id, last)
+		}
+		last = key
+	}
+}
+
+func TestHashedHeapFuncZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Insert should panic on the zero value of HashedHeapFunc")
+		}
+	}()
+	var h HashedHeapFunc[int, int, any]
+	h.Insert(1, 1, nil)
+}
@@ -8,27 +8,64 @@
 // decreasing a key is Θ(1),
 // and merging two heaps is Θ(1).
 //
-// In our implementation, we do not additionally track the key value of each
-// element. Therefore, users should be aware that they should not insert
-// elements with the same key into the Fibonacci heap.
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc. In particular, NaN keys of
+// floating-point types are valid: a NaN is smaller than any other key, so NaN
+// keys are extracted first, in the order slices.Sort puts them.
+//
+// Several elements may share the same key. By default, the order in which
+// elements with equal keys are extracted is unspecified; a heap made stable by
+// SetStable extracts them in insertion order.
+//
+// A nil *Heap reads as an empty heap: Size, Min, Contains and the other
+// queries report no element, and the extracting methods such as ExtractMin and
+// Drain return nil. Methods adding elements or changing options panic on a nil
+// heap. Methods taking an element panic if it does not belong to the heap,
+// including a nil element, while their Try variants return an error instead.
 package fibheap
 
 import (
-	"golang.org/x/exp/constraints"
+	"cmp"
+	"sync/atomic"
+	"time"
 )
 
-type Element[K constraints.Ordered, V any] struct {
+// Element is an element of a heap, holding a key and a value. It is returned by
+// Insert and serves as a handle to the element in the other methods of the
+// heap. A nil *Element stands for no element, as returned by Min on an empty
+// heap. An element extracted or deleted from its heap holds no reference to the
+// other elements, so it is safe to retain without retaining the heap.
+type Element[K any, V any] struct {
 	p        *Element[K, V]
 	r        *Element[K, V]
 	l        *Element[K, V]
 	children *Element[K, V]
+	// number of elements below this one in its tree, tombstones included,
+	// which is only kept up to date by a heap used by ExtractRandom
+	below int
 	// store mark in the LSB
 	degree uint32
-	key    K
+	flags  uint8
+	owner  *owner
+	// insertion sequence number, used to break ties in stable heaps
+	seq uint64
+	id  uint64
+	key K
 	// The value stored with this element.
 	Value V
 }
 
+const (
+	// suspended is set when the element is held by Heap.Suspend.
+	suspended uint8 = 1 << iota
+	// pinned is set when the element is held by Heap.Pin.
+	pinned
+	// tombstone is set when the element was lazily deleted.
+	tombstone
+	// recycled is set when the element waits in the free list of a heap.
+	recycled
+)
+
 func (e *Element[K, V]) getDegree() int {
 	return int(e.degree >> 1)
 }
@@ -75,45 +112,206 @@ func (n *Element[K, V]) append(m *Element[K, V]) *Element[K, V] {
 	return n
 }
 
-// Heap represents the fibonacci heap.
-type Heap[K constraints.Ordered, V any] struct {
-	elements int
-	min      *Element[K, V]
+// Heap represents the fibonacci heap of keys of an ordered type, that is, a
+// type satisfying cmp.Ordered. The zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, Ordered[K]]
+
+// HeapFunc represents a fibonacci heap whose keys are ordered by a comparison
+// function. It must be created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, Func[K]]
+
+// HeapOf represents a fibonacci heap whose keys are ordered by O. It is used
+// through its aliases Heap and HeapFunc.
+type HeapOf[K any, V any, O Order[K]] struct {
+	order      O
+	stable     bool
+	lazy       bool
+	seq        uint64
+	elements   int
+	tombstones int
+	min        *Element[K, V]
+	suspended  map[*Element[K, V]]struct{}
+	pinned     map[*Element[K, V]]struct{}
+	owner      *owner
+	watches    []*watch[K, V, O]
+	observer   Observer
+	free       *Element[K, V]
+	// degrees is the table of roots by degree used by consolidate.
+	degrees []*Element[K, V]
+	// roots is the length of the root list, and threshold the length above
+	// which Insert and Meld consolidate it, or zero.
+	roots     int
+	threshold int
+	// marked is the number of marked elements in the trees.
+	marked int
+	// lastID and endID delimit the block of element IDs taken by the heap,
+	// and ids indexes its elements by ID once ByID has been called.
+	lastID, endID uint64
+	ids           map[uint64]*Element[K, V]
+	// sized is set once ExtractRandom has counted the elements below every
+	// element, which the heap then keeps up to date.
+	sized bool
+	// onMove is called with the elements leaving the heap, see SetOnMove.
+	onMove func(x *Element[K, V], to *HeapOf[K, V, O])
+	// tracer records the operations of the heap, see StartTrace.
+	tracer *tracer
+	// overBudget is called with the work of the consolidations exceeding
+	// budget, see SetWorkBudget.
+	budget     int
+	overBudget func(work int)
+}
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering. The < operator does not define one on floating-point keys
+// holding NaN, which compare neither smaller nor larger than any key; cmp.Less
+// does, and may be used instead.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("fibheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less}
+}
+
+// mustNotNil panics with a message naming the method if the heap h is nil. A nil
+// heap reads as an empty heap, but cannot be modified.
+func (h *HeapOf[K, V, O]) mustNotNil(method string) {
+	if h == nil {
+		panic("fibheap: " + method + " on a nil heap")
+	}
+}
+
+// mustOrder panics if the heap h lacks the comparison function of its ordering,
+// which happens to the zero value of HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !validOrder[K](h.order) {
+		panic("fibheap: a heap ordered by a function must be created by its constructor, such as NewHeapFunc")
+	}
+}
+
+// SetStable sets whether the heap h breaks ties between elements with equal
+// keys by insertion order, so that they are extracted first in, first out.
+// Stable heaps compare keys more often. SetStable panics if the heap h is not
+// empty.
+func (h *HeapOf[K, V, O]) SetStable(stable bool) {
+	h.mustNotNil("SetStable")
+	if h.min != nil || len(h.suspended) != 0 || len(h.pinned) != 0 {
+		panic("fibheap: SetStable expects an empty heap")
+	}
+	h.stable = stable
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opStable, Int: boolInt(stable)})
+	}
+}
+
+// Stable reports whether the heap h breaks ties by insertion order.
+func (h *HeapOf[K, V, O]) Stable() bool {
+	return h != nil && h.stable
 }
 
 // Size returns the number of elements in the heap h
-func (h *Heap[K, V]) Size() int {
+func (h *HeapOf[K, V, O]) Size() int {
+	if h == nil {
+		return 0
+	}
 	return h.elements
 }
 
+// before reports whether the element a is ordered before the element b, that
+// is, a has a smaller key, or an equal key and an earlier insertion in a stable
+// heap.
+func (h *HeapOf[K, V, O]) before(a, b *Element[K, V]) bool {
+	if h.order.Less(a.key, b.key) {
+		return true
+	}
+	return h.stable && a.seq < b.seq && !h.order.Less(b.key, a.key)
+}
+
+// lastSeq is the last insertion sequence number handed out to an element of a
+// stable heap. It is shared by all heaps, so that the elements of melded heaps
+// keep distinct sequence numbers.
+var lastSeq atomic.Uint64
+
+// sequence gives the element x, inserted into the stable heap h, a sequence
+// number larger than those of the elements inserted before.
+func (h *HeapOf[K, V, O]) sequence(x *Element[K, V]) {
+	h.seq = lastSeq.Add(1)
+	x.seq = h.seq
+}
+
 // Insert inserts the key-value pair (key, value) to the heap h and returns the
 // inserted element with amortized running time Θ(1)
-func (h *Heap[K, V]) Insert(key K, value V) *Element[K, V] {
-	n := &Element[K, V]{key: key, Value: value}
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	h.mustNotNil("Insert")
+	if h.min == nil {
+		h.mustOrder()
+	}
+	n := h.alloc(key, value)
+	h.insert(n)
+	return n
+}
+
+// insert adds the new element n, which has its ID and owner set, to the root
+// list of the heap h and notifies the callbacks.
+func (h *HeapOf[K, V, O]) insert(n *Element[K, V]) {
+	if h.stable {
+		h.sequence(n)
+	}
 	h.elements++
+	h.roots++
 	h.min = h.min.append(n)
-	if n.key < h.min.key {
+	if h.before(n, h.min) {
 		h.min = n
 	}
-	return n
+	h.eagerConsolidate()
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opInsert, ID: n.id, Key: n.key, Value: n.Value})
+	}
+	if h.observer != nil {
+		h.observer.Inserted()
+	}
+	if h.watches != nil {
+		h.notify()
+	}
 }
 
 // Min fetches the minimum key from the heap h with running time Θ(1)
-func (h *Heap[K, V]) Min() *Element[K, V] {
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	if h == nil {
+		return nil
+	}
 	return h.min
 }
 
 // ExtractMin() fetches and removes the minimum key from the heap h with
 // amortized running time O(log n)
-func (h *Heap[K, V]) ExtractMin() *Element[K, V] {
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
 	if h == nil || h.min == nil {
 		return nil
 	}
+	z := h.extractMin()
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opExtractMin, ID: z.id})
+	}
+	h.extracted(z)
+	if h.watches != nil {
+		h.notify()
+	}
+	return z
+}
+
+// extractMin removes the minimum element from the non-empty heap h. It does not
+// report the element to the observer, since park also uses it to take out
+// elements which stay in the heap.
+func (h *HeapOf[K, V, O]) extractMin() *Element[K, V] {
 
 	if h.min.children != nil {
+		// the children become roots, which are never marked
 		h.min.children.p = nil
+		h.unmark(h.min.children)
 		for c := h.min.children.r; c != h.min.children; c = c.r {
 			c.p = nil
+			h.unmark(c)
 		}
 		l := h.min.children.l
 		r := h.min.r
@@ -121,9 +319,13 @@ func (h *Heap[K, V]) ExtractMin() *Element[K, V] {
 		h.min.children.l = h.min
 		l.r = r
 		r.l = l
+		h.roots += h.min.getDegree()
 	}
 
 	z := h.min
+	z.owner = nil
+	h.elements--
+	h.roots--
 	if h.min.r == h.min.l && h.min.r == h.min {
 		h.min = nil
 	} else {
@@ -133,9 +335,86 @@ func (h *Heap[K, V]) ExtractMin() *Element[K, V] {
 		h.consolidate()
 	}
 
+	// detach z, so that retaining it does not retain the rest of the heap
+	z.p, z.l, z.r, z.children = nil, nil, nil, nil
+	z.degree, z.below = 0, 0
 	return z
 }
 
+// ExtractMinIf fetches and removes the minimum key from the heap h only if pred
+// reports true for the key and the value of the minimum element. Otherwise, the
+// heap is left unchanged and nil is returned.
+func (h *HeapOf[K, V, O]) ExtractMinIf(pred func(K, V) bool) *Element[K, V] {
+	if h == nil || h.min == nil || !pred(h.min.key, h.min.Value) {
+		return nil
+	}
+	return h.ExtractMin()
+}
+
+// ExtractUntil fetches and removes, in ascending order, every element whose key
+// is less than or equal to key. It returns nil if the minimum key of the heap h
+// is already larger than key.
+func (h *HeapOf[K, V, O]) ExtractUntil(key K) []*Element[K, V] {
+	if h == nil {
+		return nil
+	}
+	var list []*Element[K, V]
+	for h.min != nil && !h.order.Less(key, h.min.key) {
+		list = append(list, h.ExtractMin())
+	}
+	return list
+}
+
+// ExtractWhile fetches and removes, in ascending order, the minimum element of
+// the heap h as long as pred reports true for its key and value. It returns nil
+// if pred reports false for the minimum element or the heap is empty.
+func (h *HeapOf[K, V, O]) ExtractWhile(pred func(K, V) bool) []*Element[K, V] {
+	if h == nil {
+		return nil
+	}
+	var list []*Element[K, V]
+	for h.min != nil && pred(h.min.key, h.min.Value) {
+		list = append(list, h.ExtractMin())
+	}
+	return list
+}
+
+// PopN fetches and removes the n smallest elements of the heap h in ascending
+// order. If the heap holds fewer than n elements, all of them are returned. It
+// returns nil if n <= 0 or the heap is empty.
+func (h *HeapOf[K, V, O]) PopN(n int) []*Element[K, V] {
+	if n <= 0 || h == nil || h.min == nil {
+		return nil
+	}
+	list := make([]*Element[K, V], 0, min(n, h.Size()))
+	for ; n > 0 && h.min != nil; n-- {
+		list = append(list, h.ExtractMin())
+	}
+	return list
+}
+
+// Drain fetches and removes every element from the heap h, and returns them in
+// ascending order with amortized running time O(n log n), leaving the heap
+// empty. Pinned and suspended elements are left in the heap.
+func (h *HeapOf[K, V, O]) Drain() []*Element[K, V] {
+	if h == nil || h.min == nil {
+		return nil
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opDrain})
+	}
+	list := make([]*Element[K, V], 0, h.elements-len(h.pinned))
+	for h.min != nil {
+		x := h.extractMin()
+		list = append(list, x)
+		h.extracted(x)
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+	return list
+}
+
 // d returns math.Floor(math.Log2(n))
 func d(a int) int {
 	i := 0
@@ -146,21 +425,43 @@ func d(a int) int {
 	return i
 }
 
-func (h *Heap[K, V]) consolidate() {
-	a := make([]*Element[K, V], d(h.elements)+1)
+func (h *HeapOf[K, V, O]) consolidate() {
+	var start time.Time
+	if h.observer != nil {
+		start = time.Now()
+	}
+	if h.tombstones > 0 {
+		if h.purge(); h.min == nil {
+			return
+		}
+	}
+	// a[d] is the root of degree d found so far. The table is kept on the heap
+	// between consolidations, and is left cleared.
+	a := h.degrees
+	if n := d(h.elements) + 1; len(a) < n {
+		a = make([]*Element[K, V], n)
+	}
+	roots, links := 0, 0
 	end := h.min.l
+	var x *Element[K, V]
 	for w := h.min; ; {
 		next := w.r
-		x := w
+		x = w
 		d := x.getDegree()
-		for a[d] != nil {
+		roots++
+		for ; d < len(a) && a[d] != nil; d++ {
 			y := a[d]
-			if y.key < x.key {
+			if h.before(y, x) {
 				x, y = y, x
 			}
 			h.link(y, x)
+			links++
 			a[d] = nil
-			d++
+		}
+		// the maximum degree is bounded by log_φ(n), which may exceed the
+		// initial length of a
+		for d >= len(a) {
+			a = append(a, nil)
 		}
 		a[d] = x
 		if w == end {
@@ -168,29 +469,30 @@ func (h *Heap[K, V]) consolidate() {
 		}
 		w = next
 	}
-	h.min = nil
-	for _, node := range a {
-		if node == nil {
-			continue
-		}
-		node.l.r = node.r
-		node.r.l = node.l
-		node.l = node
-		node.r = node
-
-		if h.min == nil {
-			h.min = node
-			continue
+	h.degrees = a
+	// linking removed the children from the root list, which is left with the
+	// roots of the table, starting at the last linked root x
+	h.min = x
+	for w := x; ; {
+		a[w.getDegree()] = nil
+		if h.before(w, h.min) {
+			h.min = w
 		}
-		h.min = h.min.append(node)
-		if node.key < h.min.key {
-			h.min = node
+		if w = w.r; w == x {
+			break
 		}
 	}
+	h.roots = roots - links
+	if h.observer != nil {
+		h.observer.Consolidated(roots, links, time.Since(start))
+	}
+	if h.overBudget != nil && roots+links > h.budget {
+		h.overBudget(roots + links)
+	}
 }
 
 // link removes y from the root list, and makes y a children of x.
-func (h *Heap[K, V]) link(y, x *Element[K, V]) {
+func (h *HeapOf[K, V, O]) link(y, x *Element[K, V]) {
 	// remove y form the root list
 	y.l.r = y.r
 	y.r.l = y.l
@@ -198,41 +500,211 @@ func (h *Heap[K, V]) link(y, x *Element[K, V]) {
 	x.children = x.children.append(y)
 
 	x.increaseDegree()
+	x.below += y.below + 1
 	y.p = x
-	y.clearMark()
+	h.unmark(y)
+}
+
+// walk calls fn for every element in the circular list starting at x and their
+// descendants in depth-first order, where depth is the depth of x.
+func walk[K any, V any](x *Element[K, V], depth int, fn func(e *Element[K, V], depth int)) {
+	if x == nil {
+		return
+	}
+	for e := x; ; {
+		fn(e, depth)
+		walk(e.children, depth+1, fn)
+		if e = e.r; e == x {
+			break
+		}
+	}
 }
 
 // Decreasing decreases the key of element with the minimum key with amortized
 // running time Θ(1). If the new key k is larger or equal than the key of x,
-// Decreasing does nothing.
-func (h *Heap[K, V]) Decreasing(x *Element[K, V], key K) {
-	if key >= x.key {
+// Decreasing does nothing. The key of a suspended or pinned element is decreased
+// in place and takes effect when the element is resumed or unpinned. Decreasing
+// panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	if !h.order.Less(key, x.key) {
 		return
 	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opDecrease, ID: x.id, Key: key})
+	}
+	h.decrease(x, key)
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// decrease decreases the key of the element x to the smaller key.
+func (h *HeapOf[K, V, O]) decrease(x *Element[K, V], key K) {
 	x.key = key
+	if x.flags&(suspended|pinned) != 0 {
+		return
+	}
 	p := x.p
-	if p != nil && x.key < p.key {
+	if p != nil && h.before(x, p) {
 		h.cut(x, p)
 		h.cascadingCut(p)
 	}
-	if x.key < h.min.key {
+	if h.before(x, h.min) {
 		h.min = x
 	}
 }
 
+// Increasing increases the key of the element x with amortized running time
+// O(log n). The element x is cut from its parent and its children are moved to
+// the root list, so that the heap order holds with the larger key. If the new
+// key is smaller or equal than the key of x, Increasing does nothing. Increasing
+// panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Increasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Increasing")
+	if !h.order.Less(x.key, key) {
+		return
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opIncrease, ID: x.id, Key: key})
+	}
+	x.key = key
+	if x.flags&(suspended|pinned) != 0 {
+		return
+	}
+	if p := x.p; p != nil {
+		h.cut(x, p)
+		h.cascadingCut(p)
+	}
+	h.unchild(x)
+	if x == h.min {
+		h.consolidate()
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// unchild moves the children of the root x to the root list of the heap h.
+func (h *HeapOf[K, V, O]) unchild(x *Element[K, V]) {
+	c := x.children
+	if c == nil {
+		return
+	}
+	for e := c; ; {
+		e.p = nil
+		h.unmark(e)
+		if e = e.r; e == c {
+			break
+		}
+	}
+	l := c.l
+	r := x.r
+	x.r = c
+	c.l = x
+	l.r = r
+	r.l = l
+	h.roots += x.getDegree()
+	x.children = nil
+	x.degree, x.below = 0, 0
+}
+
+// Update changes the key of the element x to key, decreasing or increasing it
+// as needed, and returns x. Decreasing takes amortized running time Θ(1) and
+// increasing takes amortized running time O(log n), as Decreasing and
+// Increasing. The element x is updated in place, so that the returned handle is
+// always x itself. Update panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Update(x *Element[K, V], key K) *Element[K, V] {
+	h.mustContain(x, "Update")
+	switch {
+	case h.order.Less(key, x.key):
+		h.Decreasing(x, key)
+	case h.order.Less(x.key, key):
+		h.Increasing(x, key)
+	}
+	return x
+}
+
 // Remove removes the element x by given a key minimumKey which is smaller than
-// any key in the heap h.
-func (h *Heap[K, V]) Remove(x *Element[K, V], minimumKey K) {
-	h.Decreasing(x, minimumKey)
+// any key in the heap h. Remove panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Remove(x *Element[K, V], minimumKey K) {
+	h.mustContain(x, "Remove")
+	if h.order.Less(minimumKey, x.key) {
+		if h.tracer != nil {
+			h.record(traceOp[K, V]{Op: opDecrease, ID: x.id, Key: minimumKey})
+		}
+		h.decrease(x, minimumKey)
+	}
 	if n := h.Min(); n != x {
 		panic("fibheap: Remove will remove unexpected element")
 	}
 	h.ExtractMin()
 }
 
+// Delete removes the element x from the heap h with amortized running time
+// O(log n). Unlike Remove, Delete does not require a key smaller than any key in
+// the heap: the element x acts as negative infinity, so it is cut from its
+// parent and then extracted as the minimum. Deleting a suspended or pinned
+// element releases it. In a heap set by SetLazyDelete, Delete only marks x as
+// deleted with running time Θ(1), unless x is the minimum. Delete panics if x
+// does not belong to the heap h, for example because it was already extracted.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	h.mustContain(x, "Delete")
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opDelete, ID: x.id})
+	}
+	switch {
+	case x.flags&suspended != 0:
+		delete(h.suspended, x)
+		x.flags &^= suspended
+		x.owner = nil
+		h.extracted(x)
+		return
+	case x.flags&pinned != 0:
+		delete(h.pinned, x)
+		x.flags &^= pinned
+		x.owner = nil
+		h.elements--
+		h.extracted(x)
+	case h.lazy && x != h.min:
+		h.bury(x)
+	default:
+		h.delete(x)
+		h.extracted(x)
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// delete removes the element x from the heap h. The element x is cut from its
+// parent as if its key were decreased to negative infinity, and then extracted
+// as the minimum.
+func (h *HeapOf[K, V, O]) delete(x *Element[K, V]) {
+	if p := x.p; p != nil {
+		h.cut(x, p)
+		h.cascadingCut(p)
+	}
+	h.min = x
+	h.extractMin()
+}
+
+// unmark clears the mark of the element x in a tree of the heap h.
+func (h *HeapOf[K, V, O]) unmark(x *Element[K, V]) {
+	if x.getMark() {
+		x.clearMark()
+		h.marked--
+	}
+}
+
 // cut cuts the link between x and its parent p and makes x a root.
-func (h *Heap[K, V]) cut(x, p *Element[K, V]) {
+func (h *HeapOf[K, V, O]) cut(x, p *Element[K, V]) {
 	p.decreaseDegree()
+	if h.sized {
+		for a := p; a != nil; a = a.p {
+			a.below -= x.below + 1
+		}
+	}
 
 	if x == x.r {
 		p.children = nil
@@ -249,16 +721,21 @@ func (h *Heap[K, V]) cut(x, p *Element[K, V]) {
 	x.l = x
 	x.r = x
 	x.p = nil
-	x.clearMark()
+	h.unmark(x)
 	h.min = h.min.append(x)
+	h.roots++
+	if h.observer != nil {
+		h.observer.Cut()
+	}
 }
 
 // cascadingCut handles the ancestral consequences of cutting an element.
-func (h *Heap[K, V]) cascadingCut(y *Element[K, V]) {
+func (h *HeapOf[K, V, O]) cascadingCut(y *Element[K, V]) {
 	z := y.p
 	if z != nil {
 		if !y.getMark() {
 			y.setMark()
+			h.marked++
 		} else {
 			h.cut(y, z)
 			h.cascadingCut(z)
@@ -268,15 +745,103 @@ func (h *Heap[K, V]) cascadingCut(y *Element[K, V]) {
 
 // Union unions the two fibonacci heaps h and g, and returns the new fibonacci
 // heap with amortized running time Θ(1). The heap h and g will be reset after
-// unioning.
-func (h *Heap[K, V]) Union(g *Heap[K, V]) *Heap[K, V] {
+// unioning. The heap g must order keys in the same way as the heap h, whose
+// comparison function is used by the new heap. Every element of h and g,
+// including suspended and pinned ones, belongs to the new heap afterwards.
+func (h *HeapOf[K, V, O]) Union(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
 	if h == nil || g == nil {
 		panic("fibheap: Union expects non-nil heap h and g")
 	}
 
-	m := &Heap[K, V]{
-		elements: g.elements + h.elements,
+	m := &HeapOf[K, V, O]{
+		order:      h.order,
+		stable:     h.stable,
+		lazy:       h.lazy,
+		seq:        h.seq,
+		elements:   h.elements,
+		tombstones: h.tombstones,
+		roots:      h.roots,
+		threshold:  h.threshold,
+		marked:     h.marked,
+		min:        h.min,
+		suspended:  h.suspended,
+		pinned:     h.pinned,
+		owner:      h.owner,
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opUnion})
+	}
+	list := h.members()
+	// clear heap h, heap g is cleared by meld
+	h.min = nil
+	h.elements, h.tombstones, h.roots, h.marked = 0, 0, 0, 0
+	h.suspended, h.pinned, h.owner = nil, nil, nil
+	h.ids, h.sized = nil, false
+	if g != h {
+		m.meld(g)
+	}
+	moved(h.onMove, list, m)
+	if h.watches != nil {
+		h.notify()
+	}
+	if g.watches != nil {
+		g.notify()
+	}
+
+	return m
+}
+
+// Meld moves every element of the heap g into the heap h with amortized running
+// time Θ(1), leaving g empty. Unlike Union, Meld keeps using the heap h, so its
+// callbacks stay registered. The heap g must order keys in the same way as the
+// heap h. Every element of g, including suspended and pinned ones, belongs to h
+// afterwards. Meld panics if h and g are the same heap.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("fibheap: Meld expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("fibheap: Meld expects two different heaps")
+	}
+	if h.tracer != nil {
+		h.recordHeap(opMeld, g)
+	}
+	h.meld(g)
+	if h.watches != nil {
+		h.notify()
+	}
+	if g.watches != nil {
+		g.notify()
+	}
+}
+
+// UnionCopy returns a new heap holding copies of the elements of the heap h and
+// g with running time O(n), leaving h and g unchanged. Handles to the elements
+// of h and g do not refer to the copies.
+func (h *HeapOf[K, V, O]) UnionCopy(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("fibheap: UnionCopy expects non-nil heap h and g")
+	}
+	m := h.Clone()
+	m.meld(g.Clone())
+	return m
+}
+
+// meld splices the trees of the heap g into the heap h, moves the held elements
+// and the ownership of g to h, and resets g.
+func (h *HeapOf[K, V, O]) meld(g *HeapOf[K, V, O]) {
+	if !validOrder[K](h.order) {
+		h.order = g.order
 	}
+	if g.tracer != nil {
+		g.record(traceOp[K, V]{Op: opEmptied})
+	}
+	list := g.members()
+	h.seq = max(h.seq, g.seq)
+	h.elements += g.elements
+	h.tombstones += g.tombstones
+	h.roots += g.roots
+	h.marked += g.marked
 	if h.min != nil && g.min != nil {
 		l := g.min.l
 		r := h.min.r
@@ -285,22 +850,41 @@ func (h *Heap[K, V]) Union(g *Heap[K, V]) *Heap[K, V] {
 		l.r = r
 		r.l = l
 
-		if h.min.key < g.min.key {
-			m.min = h.min
-		} else {
-			m.min = g.min
+		if h.before(g.min, h.min) {
+			h.min = g.min
 		}
-	} else if h.min != nil {
-		m.min = h.min
-	} else {
-		m.min = g.min
+	} else if g.min != nil {
+		h.min = g.min
 	}
 
-	// clear heap h and heap g
-	h.min = nil
-	h.elements = 0
+	// the elements of g, including the held ones, now belong to h
+	if h.owner == nil {
+		h.owner = g.owner
+	} else if g.owner != nil {
+		h.owner = h.owner.union(g.owner)
+	}
+	h.suspended = mergeHeld(h.suspended, g.suspended)
+	h.pinned = mergeHeld(h.pinned, g.pinned)
+
 	g.min = nil
-	g.elements = 0
+	g.elements, g.tombstones, g.roots, g.marked = 0, 0, 0, 0
+	g.suspended, g.pinned, g.owner = nil, nil, nil
+	// the index of h misses the elements of g, and is rebuilt on demand, while
+	// the counts of ExtractRandom stay right if both heaps kept them
+	h.ids, g.ids = nil, nil
+	h.sized, g.sized = h.sized && g.sized, false
+	h.eagerConsolidate()
+	moved(g.onMove, list, h)
+}
 
-	return m
+// mergeHeld returns the union of the sets a and b of held elements, reusing the
+// larger one.
+func mergeHeld[K any, V any](a, b map[*Element[K, V]]struct{}) map[*Element[K, V]]struct{} {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	for e := range b {
+		a[e] = struct{}{}
+	}
+	return a
 }
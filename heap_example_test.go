@@ -59,6 +59,21 @@ func ExampleHeap_Remove() {
 	fmt.Println("size:", h.Size())
 	fmt.Println("min:", h.Min().Key())
 
-	// Output: size: 3
+	// Output: size: 1
 	//min: 7
 }
+
+func ExampleMaxHeap() {
+	h := &fibheap.MaxHeap[string, int]{}
+	h.Insert("apple", 1)
+	h.Insert("cherry", 3)
+	h.Insert("banana", 2)
+
+	max := h.ExtractMax()
+	fmt.Println(max.Key(), max.Value)
+
+	max = h.Max()
+	fmt.Println(max.Key(), max.Value)
+	// Output: cherry 3
+	//banana 2
+}
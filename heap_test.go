@@ -1,6 +1,7 @@
 package fibheap
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -97,3 +98,378 @@ func TestUnion(t *testing.T) {
 		t.Fatal("g should be clear after Union")
 	}
 }
+
+func TestMeld(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	for i := 10; i < 20; i++ {
+		h.Insert(i, nil)
+	}
+	for i := 0; i < 10; i++ {
+		g.Insert(i, nil)
+	}
+	x := g.Insert(20, nil)
+	g.Suspend(x)
+
+	h.Meld(g)
+	assert(t, h.Size(), 20)
+	assert(t, g.Size(), 0)
+	if g.min != nil || g.Suspended() != 0 {
+		t.Fatal("g should be clear after Meld")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	h.Resume(x)
+	for i := 0; i <= 20; i++ {
+		assert(t, h.ExtractMin().Key(), i)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	h.Meld(h)
+}
+
+func TestUnionCopy(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	for i := 0; i < 10; i++ {
+		h.Insert(i, nil)
+		g.Insert(i+10, nil)
+	}
+	h.ExtractMin()
+	h.Insert(0, nil)
+
+	k := h.UnionCopy(g)
+	assert(t, h.Size(), 10)
+	assert(t, g.Size(), 10)
+	if err := k.Check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		assert(t, k.ExtractMin().Key(), i)
+	}
+	for i := 0; i < 10; i++ {
+		assert(t, h.ExtractMin().Key(), i)
+		assert(t, g.ExtractMin().Key(), i+10)
+	}
+}
+
+func TestHeapExtractMinDetached(t *testing.T) {
+	h := &Heap[int, any]{}
+	elements := make([]*Element[int, any], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, nil)
+	}
+	h.ExtractMin()
+	detached := func(x *Element[int, any]) bool {
+		return x.p == nil && x.l == nil && x.r == nil && x.children == nil && x.degree == 0
+	}
+	h.Delete(elements[50])
+	h.Pin(elements[60])
+	if !detached(elements[50]) || !detached(elements[60]) {
+		t.Fatal("deleted and pinned elements should be detached")
+	}
+	h.Unpin(elements[60])
+	for h.Size() > 0 {
+		if x := h.ExtractMin(); !detached(x) {
+			t.Fatalf("extracted element %d should be detached", x.Key())
+		}
+	}
+}
+
+func TestHeapExtractUntil(t *testing.T) {
+	h := &Heap[int, any]{}
+	for i := 0; i < 100; i++ {
+		h.Insert(i, nil)
+	}
+	list := h.ExtractUntil(49)
+	if len(list) != 50 {
+		t.Fatalf("expected 50 elements, got %d", len(list))
+	}
+	for i, x := range list {
+		assert(t, x.Key(), i)
+	}
+	assert(t, h.Size(), 50)
+	if list := h.ExtractUntil(10); list != nil {
+		t.Fatal("ExtractUntil should return nil")
+	}
+}
+
+func TestHeapExtractWhile(t *testing.T) {
+	h := &Heap[int, any]{}
+	for i := 0; i < 100; i++ {
+		h.Insert(i, nil)
+	}
+	list := h.ExtractWhile(func(k int, _ any) bool { return k < 30 })
+	if len(list) != 30 {
+		t.Fatalf("expected 30 elements, got %d", len(list))
+	}
+	for i, x := range list {
+		assert(t, x.Key(), i)
+	}
+	if list := h.ExtractWhile(func(k int, _ any) bool { return k < 30 }); list != nil {
+		t.Fatal("ExtractWhile should return nil")
+	}
+	assert(t, len(h.ExtractWhile(func(int, any) bool { return true })), 70)
+	if list := h.ExtractWhile(func(int, any) bool { return true }); list != nil {
+		t.Fatal("ExtractWhile on empty heap should return nil")
+	}
+}
+
+func TestHeapPopN(t *testing.T) {
+	h := &Heap[int, any]{}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, nil)
+	}
+	if h.PopN(0) != nil || h.PopN(-1) != nil {
+		t.Fatal("PopN should return nil for n <= 0")
+	}
+	list := h.PopN(2)
+	assert(t, len(list), 2)
+	assert(t, list[0].Key(), 1)
+	assert(t, list[1].Key(), 2)
+	list = h.PopN(10)
+	assert(t, len(list), 4)
+	for i, k := range []int{3, 5, 8, 9} {
+		assert(t, list[i].Key(), k)
+	}
+	if h.PopN(1) != nil {
+		t.Fatal("PopN on empty heap should return nil")
+	}
+}
+
+func TestHeapExtractMinIf(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.Insert(2, "two")
+	h.Insert(1, "one")
+	even := func(k int, _ string) bool { return k%2 == 0 }
+	if x := h.ExtractMinIf(even); x != nil {
+		t.Fatalf("ExtractMinIf should not extract %d", x.Key())
+	}
+	assert(t, h.Size(), 2)
+	h.ExtractMin()
+	if x := h.ExtractMinIf(even); x == nil || x.Value != "two" {
+		t.Fatal("ExtractMinIf should extract the element two")
+	}
+	if x := h.ExtractMinIf(even); x != nil {
+		t.Fatal("ExtractMinIf on empty heap should return nil")
+	}
+}
+
+func TestHeapDelete(t *testing.T) {
+	h := &Heap[string, int]{}
+	elements := make([]*Element[string, int], 26)
+	for i := range elements {
+		elements[i] = h.Insert(string(rune('a'+i)), i)
+	}
+	h.ExtractMin()
+
+	// there is no string smaller than "", so Remove cannot delete it
+	x := h.Insert("", -1)
+	h.Delete(x)
+	for i := 1; i < 26; i += 2 {
+		h.Delete(elements[i])
+	}
+	assert(t, h.Size(), 12)
+	for i := 2; i < 26; i += 2 {
+		assert(t, h.ExtractMin().Value, i)
+	}
+	if h.ExtractMin() != nil {
+		t.Fatal("heap should be empty")
+	}
+}
+
+func TestHeapDeleteHeld(t *testing.T) {
+	h := &Heap[int, any]{}
+	a := h.Insert(1, nil)
+	b := h.Insert(2, nil)
+	h.Insert(3, nil)
+	h.Suspend(a)
+	h.Pin(b)
+	h.Delete(a)
+	h.Delete(b)
+	assert(t, h.Size(), 1)
+	assert(t, h.Suspended(), 0)
+	assert(t, h.Pinned(), 0)
+	assert(t, h.ExtractMin().Key(), 3)
+}
+
+func TestHeapIncreasing(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+
+	// increase the minimum, a root with children, and elements deep in the
+	// trees, then check that everything comes out in order
+	h.Increasing(elements[1], 150)
+	h.Increasing(elements[64], 140)
+	for i := 2; i < 50; i += 7 {
+		h.Increasing(elements[i], 100+i)
+	}
+	h.Increasing(elements[99], 0)
+	assert(t, h.Size(), 99)
+
+	prev := -1
+	for x := h.ExtractMin(); x != nil; x = h.ExtractMin() {
+		if x.Key() < prev {
+			t.Fatalf("key %d extracted after %d", x.Key(), prev)
+		}
+		prev = x.Key()
+	}
+	assert(t, prev, 150)
+}
+
+func TestHeapUpdate(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+
+	// move every element to the mirrored key, half of them up and half down
+	for i := 1; i < 100; i++ {
+		if x := h.Update(elements[i], 100-i); x != elements[i] {
+			t.Fatal("expected Update to keep the element")
+		}
+	}
+	h.Update(elements[50], 50)
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 99; i > 0; i-- {
+		x := h.ExtractMin()
+		assert(t, x.Key(), 100-i)
+		assert(t, x.Value, i)
+	}
+}
+
+func TestHeapDuplicateKeys(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 1000)
+	for i := range elements {
+		elements[i] = h.Insert(i%5, i)
+	}
+	removed := make(map[*Element[int, int]]bool)
+	removed[h.ExtractMin()] = true
+	for i := 11; i < 1000; i += 10 {
+		h.Decreasing(elements[i], 0)
+	}
+	for i := 12; i < 1000; i += 10 {
+		h.Increasing(elements[i], 4)
+	}
+	for i := 13; i < 1000; i += 10 {
+		if !removed[elements[i]] {
+			h.Delete(elements[i])
+			removed[elements[i]] = true
+		}
+	}
+	if x := elements[14]; !removed[x] {
+		h.Remove(x, -1)
+		removed[x] = true
+	}
+	assert(t, h.Size(), 1000-len(removed))
+
+	counts := make(map[int]int)
+	for _, x := range elements {
+		if !removed[x] {
+			counts[x.Key()]++
+		}
+	}
+	prev := 0
+	for x := h.ExtractMin(); x != nil; x = h.ExtractMin() {
+		if x.Key() < prev {
+			t.Fatalf("key %d extracted after %d", x.Key(), prev)
+		}
+		prev = x.Key()
+		counts[x.Key()]--
+	}
+	for k, c := range counts {
+		if c != 0 {
+			t.Fatalf("key %d extracted %d times too few", k, c)
+		}
+	}
+}
+
+func TestHeapStable(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	elements := make([]*Element[int, int], 300)
+	for i := range elements {
+		elements[i] = h.Insert(i%3, i)
+	}
+	h.Insert(-1, -1)
+	h.ExtractMin()
+
+	// the decreased elements join key 0 in insertion order
+	h.Decreasing(elements[299], 0)
+	h.Decreasing(elements[1], 0)
+	expected := []int{}
+	for i := 0; i < 300; i++ {
+		if i%3 == 0 || i == 1 || i == 299 {
+			expected = append(expected, i)
+		}
+	}
+	for i := 0; i < 300; i++ {
+		if i%3 == 1 && i != 1 {
+			expected = append(expected, i)
+		}
+	}
+	for i := 0; i < 300; i++ {
+		if i%3 == 2 && i != 299 {
+			expected = append(expected, i)
+		}
+	}
+	for _, i := range expected {
+		assert(t, h.ExtractMin().Value, i)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	h.Insert(0, 0)
+	h.SetStable(false)
+}
+
+func TestHeapDrain(t *testing.T) {
+	h := &Heap[int, any]{}
+	if h.Drain() != nil {
+		t.Fatal("Drain on empty heap should return nil")
+	}
+	elements := make([]*Element[int, any], 100)
+	for i := range elements {
+		elements[i] = h.Insert(99-i, nil)
+	}
+	h.Pin(elements[0])
+	list := h.Drain()
+	assert(t, len(list), 99)
+	for i, x := range list {
+		assert(t, x.Key(), i)
+	}
+	assert(t, h.Size(), 1)
+	if h.Min() != nil {
+		t.Fatal("only the pinned element should be left")
+	}
+}
+
+func BenchmarkHeapExtractMin(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	for i := 0; i < b.N; i++ {
+		h.Insert(r.Int(), i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.ExtractMin()
+	}
+}
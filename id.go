@@ -0,0 +1,99 @@
+package fibheap
+
+import "sync/atomic"
+
+// lastID is the last element ID handed out to a heap. Heaps take IDs in blocks
+// of idBlock, so that inserting into different heaps does not contend on it.
+var lastID atomic.Uint64
+
+const idBlock = 1 << 10
+
+// ID returns the ID of the element x, which is assigned by the heap on Insert
+// and never changes afterwards. IDs are unique among the elements inserted into
+// the heaps of a process, and are never 0. Unlike a pointer, an ID survives
+// MarshalBinary: the decoded elements keep the IDs of the encoded ones, so that
+// ByID finds them in the decoded heap, and heaps of the process assign larger
+// IDs to new elements afterwards. Copies made by Clone keep the IDs of the
+// original elements as well. MarshalJSON does not encode IDs.
+func (x *Element[K, V]) ID() uint64 {
+	if x == nil {
+		return 0
+	}
+	return x.id
+}
+
+// ByID returns the element of the heap h whose ID is id, including suspended and
+// pinned elements, or nil if there is none. The first call builds an index of
+// the elements of h in O(n) time; the index is then kept up to date by the
+// insertions, so that later calls run in expected Θ(1) time. Meld, Union and
+// Clear drop the index, which is rebuilt by the next call. A nil heap holds no
+// element.
+func (h *HeapOf[K, V, O]) ByID(id uint64) *Element[K, V] {
+	if h == nil {
+		return nil
+	}
+	if h.ids == nil {
+		h.ids = make(map[uint64]*Element[K, V], h.elements+len(h.suspended))
+		h.each(func(e *Element[K, V]) bool {
+			h.ids[e.id] = e
+			return true
+		})
+		for e := range h.suspended {
+			h.ids[e.id] = e
+		}
+	}
+	x := h.ids[id]
+	// the index keeps the elements removed since it was built, which are
+	// dropped when found, and recycled elements may have got another ID
+	if x != nil && (x.id != id || !h.Contains(x)) {
+		delete(h.ids, id)
+		return nil
+	}
+	return x
+}
+
+// identify assigns a new ID to the element x inserted into the heap h, and
+// indexes it if h has an index.
+func (h *HeapOf[K, V, O]) identify(x *Element[K, V]) {
+	x.id = h.newID()
+	if h.ids == nil {
+		return
+	}
+	// the index keeps removed elements until they are looked up, so it is
+	// dropped once they outnumber the elements of h
+	if len(h.ids) > 2*(h.elements+len(h.suspended))+idBlock {
+		h.ids = nil
+		return
+	}
+	h.ids[x.id] = x
+}
+
+// raise sets the counter c to v unless it is already larger.
+func raise(c *atomic.Uint64, v uint64) {
+	for {
+		last := c.Load()
+		if last >= v || c.CompareAndSwap(last, v) {
+			return
+		}
+	}
+}
+
+// newID returns a new ID from the block of the heap h, taking a new block if it
+// is used up.
+func (h *HeapOf[K, V, O]) newID() uint64 {
+	if h.lastID == h.endID {
+		h.endID = lastID.Add(idBlock)
+		h.lastID = h.endID - idBlock
+	}
+	h.lastID++
+	return h.lastID
+}
+
+// reserveIDs makes the heaps of the process assign IDs larger than id from now
+// on, after elements with IDs up to id have been decoded into the heap h.
+func (h *HeapOf[K, V, O]) reserveIDs(id uint64) {
+	raise(&lastID, id)
+	// the block of h may overlap the decoded IDs if they were assigned by
+	// another process
+	h.lastID, h.endID = 0, 0
+}
@@ -0,0 +1,177 @@
+package fibheap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestHeapID(t *testing.T) {
+	h := &Heap[int, int]{}
+	seen := make(map[uint64]bool)
+	var elements []*Element[int, int]
+	for i := 0; i < 3*idBlock; i++ {
+		x := h.Insert(i, i)
+		if x.ID() == 0 || seen[x.ID()] {
+			t.Fatalf("element %d has ID %d, which is zero or taken", i, x.ID())
+		}
+		seen[x.ID()] = true
+		elements = append(elements, x)
+	}
+	// IDs are unique across heaps
+	g := &Heap[int, int]{}
+	if x := g.Insert(0, 0); seen[x.ID()] {
+		t.Fatalf("ID %d was assigned by another heap", x.ID())
+	}
+	var nilElement *Element[int, int]
+	if nilElement.ID() != 0 {
+		t.Fatal("expected ID 0 for a nil element")
+	}
+
+	h.Suspend(elements[1])
+	h.Pin(elements[2])
+	for _, x := range elements[:3] {
+		if h.ByID(x.ID()) != x {
+			t.Fatalf("ByID(%d) does not find its element", x.ID())
+		}
+	}
+	if y := h.Insert(-1, -1); h.ByID(y.ID()) != y {
+		t.Fatal("ByID does not find an element inserted after the index")
+	}
+	x := h.ExtractMin()
+	if h.ByID(x.ID()) != nil {
+		t.Fatal("ByID finds an extracted element")
+	}
+	h.Delete(elements[1])
+	if h.ByID(elements[1].ID()) != nil {
+		t.Fatal("ByID finds a deleted element")
+	}
+	if h.ByID(0) != nil || g.ByID(elements[3].ID()) != nil {
+		t.Fatal("ByID finds an element of another heap")
+	}
+	var nilHeap *Heap[int, int]
+	if nilHeap.ByID(elements[3].ID()) != nil {
+		t.Fatal("expected no element in a nil heap")
+	}
+
+	// after a meld, the index is rebuilt with the elements of g
+	y := g.Min()
+	h.Meld(g)
+	if h.ByID(y.ID()) != y || h.ByID(elements[3].ID()) != elements[3] {
+		t.Fatal("ByID does not find the elements of a melded heap")
+	}
+	c, m := h.CloneMap()
+	if c.ByID(y.ID()) != m[y] {
+		t.Fatal("expected the clone to keep the IDs")
+	}
+	h.Clear()
+	if h.ByID(y.ID()) != nil {
+		t.Fatal("ByID finds an element of a cleared heap")
+	}
+}
+
+func TestHeapIDRecycle(t *testing.T) {
+	h := &Heap[int, int]{}
+	x := h.Insert(1, 1)
+	id := x.ID()
+	h.ByID(id)
+	h.ExtractMin()
+	h.Recycle(x)
+	if y := h.Insert(2, 2); y != x || y.ID() == id {
+		t.Fatal("expected the recycled element to get a new ID")
+	}
+	if h.ByID(id) != nil || h.ByID(x.ID()) != x {
+		t.Fatal("ByID does not follow the new ID of a recycled element")
+	}
+}
+
+func TestHeapIDIndex(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.ByID(h.Insert(0, 0).ID())
+	// the index does not grow with the elements removed since it was built
+	for i := 0; i < 10*idBlock; i++ {
+		h.Insert(i, i)
+		h.ExtractMin()
+	}
+	if len(h.ids) > 2*idBlock {
+		t.Fatalf("the index holds %d entries for %d elements", len(h.ids), h.Size())
+	}
+	x := h.Min()
+	if h.ByID(x.ID()) != x {
+		t.Fatal("ByID does not find the minimum")
+	}
+}
+
+func TestHeapIDMarshalBinary(t *testing.T) {
+	h := &Heap[int, string]{}
+	var ids []uint64
+	for i := 0; i < 100; i++ {
+		ids = append(ids, h.Insert(i, "").ID())
+	}
+	h.Suspend(h.ByID(ids[10]))
+	h.ExtractMin()
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Heap[int, string]{}
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range ids {
+		x := g.ByID(id)
+		if i == 0 {
+			if x != nil {
+				t.Fatal("ByID finds an element extracted before encoding")
+			}
+			continue
+		}
+		if x == nil || x.Key() != i {
+			t.Fatalf("ByID(%d) does not find the element of key %d", id, i)
+		}
+	}
+	// decrease-key works on a restored handle
+	g.Decreasing(g.ByID(ids[50]), -1)
+	if g.Min().ID() != ids[50] {
+		t.Fatal("expected the decreased element to be the minimum")
+	}
+	if x := g.Insert(0, ""); x.ID() <= ids[len(ids)-1] {
+		t.Fatalf("new ID %d is not larger than the decoded ones", x.ID())
+	}
+}
+
+func TestHeapIDUnmarshalEarlierVersion(t *testing.T) {
+	h := &Heap[int, string]{}
+	for i := 0; i < 10; i++ {
+		h.Insert(i, "")
+	}
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encodings of earlier versions have no IDs
+	var enc encodedHeap[int, string]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&enc); err != nil {
+		t.Fatal(err)
+	}
+	for i := range enc.Trees {
+		enc.Trees[i].ID = 0
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(enc); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Heap[int, string]{}
+	if err := g.UnmarshalBinary(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[uint64]bool)
+	for x := range g.Elements() {
+		if x.ID() == 0 || seen[x.ID()] || g.ByID(x.ID()) != x {
+			t.Fatalf("decoded element has ID %d, which is zero, taken or not found", x.ID())
+		}
+		seen[x.ID()] = true
+	}
+}
@@ -0,0 +1,292 @@
+package testsuite
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Conformance runs the conformance tests of fibheap.PriorityQueue on queues
+// created empty by newQueue: emptiness edge cases, duplicate keys, sorted
+// extraction, decreases and deletions, and Random. The function check, if not
+// nil, validates the internal structure of a queue after every step.
+func Conformance[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, newQueue func() Q, check func(q Q) error) {
+	t.Helper()
+	t.Run("Empty", func(t *testing.T) { empty[E](t, newQueue(), check) })
+	t.Run("Duplicates", func(t *testing.T) { duplicates[E](t, newQueue(), check) })
+	t.Run("Sort", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for n := 0; n <= 1000; n += 1 + n/4 {
+			q := newQueue()
+			for i := 0; i < n; i++ {
+				q.Insert(r.Intn(n+1), i)
+			}
+			verify[E](t, q, nil, check)
+		}
+	})
+	t.Run("Decrease", func(t *testing.T) { decrease[E](t, newQueue(), check) })
+	t.Run("Delete", func(t *testing.T) { deletions[E](t, newQueue(), check) })
+	t.Run("Random", func(t *testing.T) { Random[E](t, newQueue(), check) })
+}
+
+// ConformanceMeld runs Conformance on heaps created empty by newHeap, followed
+// by the edge cases of melding and RandomMeld.
+func ConformanceMeld[E fibheap.Handle[int, int], H fibheap.MeldableHeap[int, int, E, H]](t *testing.T, newHeap func() H, check func(h H) error) {
+	t.Helper()
+	Conformance[E](t, newHeap, check)
+	t.Run("Meld", func(t *testing.T) { meld[E](t, newHeap, check) })
+	t.Run("RandomMeld", func(t *testing.T) { RandomMeld[E](t, newHeap, check) })
+}
+
+// empty checks the queue q, which must be empty, when it is empty before,
+// between and after insertions.
+func empty[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, check func(q Q) error) {
+	t.Helper()
+	var zero E
+	isEmpty := func(step string) {
+		t.Helper()
+		validate(t, q, check, step)
+		if q.Size() != 0 || q.Min() != zero {
+			t.Fatalf("%s: expected an empty queue, got %d elements", step, q.Size())
+		}
+	}
+	isEmpty("new queue")
+	if q.ExtractMin() != zero {
+		t.Fatal("ExtractMin returned an element of an empty queue")
+	}
+	isEmpty("ExtractMin on an empty queue")
+
+	x := q.Insert(1, 1)
+	if q.Size() != 1 || q.Min() != x || x.Pair() != (fibheap.Pair[int, int]{Key: 1, Value: 1}) {
+		t.Fatal("expected the only element to be the minimum")
+	}
+	if q.ExtractMin() != x {
+		t.Fatal("ExtractMin did not return the only element")
+	}
+	isEmpty("after extracting the only element")
+
+	q.Delete(q.Insert(2, 2))
+	isEmpty("after deleting the only element")
+
+	x = q.Insert(3, 3)
+	q.Decreasing(x, 0)
+	if q.Min() != x || x.Key() != 0 {
+		t.Fatal("expected the decreased element to be the minimum")
+	}
+	q.ExtractMin()
+	isEmpty("after extracting a decreased element")
+}
+
+// duplicates checks the queue q, which must be empty, with many equal keys.
+func duplicates[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, check func(q Q) error) {
+	t.Helper()
+	var model []fibheap.Pair[int, int]
+	var sevens []E
+	for i := 0; i < 300; i++ {
+		k := 7
+		switch i % 10 {
+		case 3:
+			k = 3
+		case 9:
+			k = 9
+		}
+		x := q.Insert(k, i)
+		if k == 7 {
+			sevens = append(sevens, x)
+		}
+		model = append(model, x.Pair())
+	}
+	validate(t, q, check, "after inserting")
+	if q.Min().Key() != 3 {
+		t.Fatalf("expected minimum 3, got %d", q.Min().Key())
+	}
+	// decreasing to an equal key and increasing do nothing
+	q.Decreasing(sevens[0], 7)
+	q.Decreasing(sevens[1], 8)
+	// decreasing a duplicate to another duplicate key
+	q.Decreasing(sevens[2], 3)
+	for i := range model {
+		if model[i].Value == sevens[2].Pair().Value {
+			model[i].Key = 3
+		}
+	}
+	verify[E](t, q, model, check)
+}
+
+// decrease checks decreases on the queue q, which must be empty.
+func decrease[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, check func(q Q) error) {
+	t.Helper()
+	r := rand.New(rand.NewSource(2))
+	elements := make([]E, 500)
+	for i := range elements {
+		elements[i] = q.Insert(1000+r.Intn(1000), i)
+	}
+	least := q.Min().Key()
+	for i := 0; i < 1000; i++ {
+		x := elements[r.Intn(len(elements))]
+		switch i % 4 {
+		case 0:
+			// below the minimum
+			least--
+			q.Decreasing(x, least)
+		case 1:
+			// the minimum itself
+			x = q.Min()
+			least -= r.Intn(2)
+			q.Decreasing(x, least)
+		case 2:
+			// to the minimum key
+			q.Decreasing(x, least)
+		default:
+			q.Decreasing(x, x.Key()-r.Intn(100))
+			least = min(least, x.Key())
+		}
+		if q.Min().Key() != least {
+			t.Fatalf("decrease %d: expected minimum %d, got %d", i, least, q.Min().Key())
+		}
+		if i%50 == 0 {
+			validate(t, q, check, "after decreasing")
+		}
+	}
+	model := make([]fibheap.Pair[int, int], len(elements))
+	for i, x := range elements {
+		model[i] = x.Pair()
+	}
+	verify[E](t, q, model, check)
+}
+
+// deletions checks deletions on the queue q, which must be empty.
+func deletions[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, check func(q Q) error) {
+	t.Helper()
+	r := rand.New(rand.NewSource(3))
+	elements := make([]E, 300)
+	for i := range elements {
+		elements[i] = q.Insert(r.Intn(100), i)
+	}
+	// the minimum, after consolidating the queue
+	x := q.ExtractMin()
+	y := q.Min()
+	q.Delete(y)
+	elements = slices.DeleteFunc(elements, func(e E) bool { return e == x || e == y })
+	validate(t, q, check, "after deleting the minimum")
+	// the element with the largest key, and a decreased element
+	x = slices.MaxFunc(elements, func(a, b E) int { return a.Key() - b.Key() })
+	q.Delete(x)
+	y = elements[100]
+	q.Decreasing(y, y.Key()/2)
+	q.Delete(y)
+	elements = slices.DeleteFunc(elements, func(e E) bool { return e == x || e == y })
+	validate(t, q, check, "after deleting")
+	// every element in random order
+	r.Shuffle(len(elements), func(i, j int) { elements[i], elements[j] = elements[j], elements[i] })
+	var zero E
+	for i, x := range elements {
+		q.Delete(x)
+		if q.Size() != len(elements)-i-1 {
+			t.Fatalf("deletion %d: expected %d elements, got %d", i, len(elements)-i-1, q.Size())
+		}
+		if i%25 == 0 {
+			validate(t, q, check, "while deleting every element")
+		}
+	}
+	if q.Min() != zero || q.ExtractMin() != zero {
+		t.Fatal("expected an empty queue after deleting every element")
+	}
+}
+
+// meld checks the edge cases of melding heaps created empty by newHeap.
+func meld[E fibheap.Handle[int, int], H fibheap.MeldableHeap[int, int, E, H]](t *testing.T, newHeap func() H, check func(h H) error) {
+	t.Helper()
+	h, g := newHeap(), newHeap()
+	h.Meld(g)
+	validate(t, h, check, "after melding two empty heaps")
+	if h.Size() != 0 || g.Size() != 0 {
+		t.Fatal("expected empty heaps after melding two empty heaps")
+	}
+
+	var model []fibheap.Pair[int, int]
+	for i := 0; i < 50; i++ {
+		model = append(model, g.Insert(i%10, i).Pair())
+	}
+	// into an empty heap, and back
+	h.Meld(g)
+	validate(t, h, check, "after melding into an empty heap")
+	if h.Size() != 50 || g.Size() != 0 {
+		t.Fatalf("expected 50 and 0 elements, got %d and %d", h.Size(), g.Size())
+	}
+	g.Meld(h)
+	h, g = g, h
+	h.Meld(g)
+	validate(t, h, check, "after melding an empty heap")
+
+	// handles of a melded heap stay valid, and equal keys mix
+	var handles []E
+	for i := 50; i < 100; i++ {
+		handles = append(handles, g.Insert(i%10, i))
+	}
+	x := g.ExtractMin()
+	handles = slices.DeleteFunc(handles, func(e E) bool { return e == x })
+	h.Meld(g)
+	if g.Size() != 0 || g.Min() != *new(E) {
+		t.Fatal("expected the melded heap to be empty")
+	}
+	h.Decreasing(handles[0], -1)
+	if h.Min() != handles[0] {
+		t.Fatal("expected a decreased handle of the melded heap to be the minimum")
+	}
+	h.Delete(handles[1])
+	for _, x := range handles[2:] {
+		model = append(model, x.Pair())
+	}
+	model = append(model, handles[0].Pair())
+	validate(t, h, check, "after melding a non-empty heap")
+
+	// the melded heap can be reused
+	model = append(model, g.Insert(5, 100).Pair())
+	h.Meld(g)
+	verify[E](t, h, model, check)
+}
+
+// verify extracts every element from the queue q, and checks that they come
+// in key order, and hold the pairs of model unless model is nil.
+func verify[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, model []fibheap.Pair[int, int], check func(q Q) error) {
+	t.Helper()
+	validate(t, q, check, "before extracting")
+	if model != nil && q.Size() != len(model) {
+		t.Fatalf("expected %d elements, got %d", len(model), q.Size())
+	}
+	var got []fibheap.Pair[int, int]
+	for n := q.Size(); n > 0; n-- {
+		x := q.ExtractMin()
+		if len(got) > 0 && x.Key() < got[len(got)-1].Key {
+			t.Fatalf("extracted %d after %d", x.Key(), got[len(got)-1].Key)
+		}
+		got = append(got, x.Pair())
+	}
+	validate(t, q, check, "after extracting every element")
+	if q.Size() != 0 {
+		t.Fatalf("expected an empty queue, got %d elements", q.Size())
+	}
+	if model == nil {
+		return
+	}
+	byValue := func(a, b fibheap.Pair[int, int]) int { return a.Value - b.Value }
+	slices.SortFunc(got, byValue)
+	slices.SortFunc(model, byValue)
+	if !slices.Equal(got, model) {
+		t.Fatal("the extracted elements differ from the inserted ones")
+	}
+}
+
+// validate checks the structure of the queue q with check, if not nil.
+func validate[Q any](t *testing.T, q Q, check func(q Q) error, step string) {
+	t.Helper()
+	if check == nil {
+		return
+	}
+	if err := check(q); err != nil {
+		t.Fatalf("%s: %v", step, err)
+	}
+}
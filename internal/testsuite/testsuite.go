@@ -0,0 +1,104 @@
+// Package testsuite holds the tests shared by the heap implementations of this
+// module. The tests run against fibheap.PriorityQueue and fibheap.MeldableHeap,
+// and compare the heap with a model, so every implementation is held to the
+// same behavior.
+package testsuite
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Random runs a random sequence of insertions, decreases, deletions and
+// extractions on the empty queue q, comparing it with a model. The function
+// check, if not nil, validates the internal structure of q every few
+// operations.
+func Random[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, check func(q Q) error) {
+	t.Helper()
+	random[E](t, q, nil, nil, check)
+}
+
+// RandomMeld is Random for meldable heaps created by newHeap, which also melds
+// small heaps into the heap under test, and the heap under test into them.
+func RandomMeld[E fibheap.Handle[int, int], H fibheap.MeldableHeap[int, int, E, H]](t *testing.T, newHeap func() H, check func(h H) error) {
+	t.Helper()
+	random[E](t, newHeap(), newHeap, func(h, g H, swap bool) H {
+		if swap {
+			g.Meld(h)
+			return g
+		}
+		h.Meld(g)
+		return h
+	}, check)
+}
+
+// random implements Random, and melds heaps created by newHeap with meld if
+// newHeap is not nil.
+func random[E fibheap.Handle[int, int], Q fibheap.PriorityQueue[int, int, E]](t *testing.T, q Q, newHeap func() Q, meld func(q, g Q, swap bool) Q, check func(q Q) error) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	var elements []E
+	var model []int
+	var zero E
+	for i := 0; i < 10000; i++ {
+		switch op := r.Intn(12); {
+		case op < 5 || len(elements) == 0:
+			k := r.Intn(10000)
+			elements = append(elements, q.Insert(k, i))
+			model = append(model, k)
+		case op < 8:
+			j := r.Intn(len(elements))
+			k := elements[j].Key() - r.Intn(1000)
+			q.Decreasing(elements[j], k)
+			model[j] = k
+		case op < 9:
+			j := r.Intn(len(elements))
+			q.Delete(elements[j])
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		case op < 10 && newHeap != nil:
+			g := newHeap()
+			for n := r.Intn(50); n > 0; n-- {
+				k := r.Intn(10000)
+				elements = append(elements, g.Insert(k, i))
+				model = append(model, k)
+			}
+			melded := g
+			if kept := meld(q, g, r.Intn(2) == 0); any(kept) == any(g) {
+				melded, q = q, kept
+			}
+			if melded.Size() != 0 {
+				t.Fatalf("operation %d: Meld left %d elements in the melded heap", i, melded.Size())
+			}
+		default:
+			x := q.ExtractMin()
+			j := slices.Index(elements, x)
+			if j < 0 {
+				t.Fatalf("operation %d: ExtractMin returned an unknown element", i)
+			}
+			if x.Key() != slices.Min(model) {
+				t.Fatalf("operation %d: expected minimum %d, got %d", i, slices.Min(model), x.Key())
+			}
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		}
+		if check != nil && i%10 == 0 {
+			if err := check(q); err != nil {
+				t.Fatalf("operation %d: %v", i, err)
+			}
+		}
+		if q.Size() != len(model) {
+			t.Fatalf("operation %d: expected %d elements, got %d", i, len(model), q.Size())
+		}
+		if len(model) == 0 {
+			if q.Min() != zero {
+				t.Fatalf("operation %d: expected no minimum in an empty heap", i)
+			}
+		} else if q.Min().Key() != slices.Min(model) {
+			t.Fatalf("operation %d: expected minimum %d, got %d", i, slices.Min(model), q.Min().Key())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package interval solves the interval partitioning problem, also known as room
+// allocation: given a set of intervals, it computes the minimum number of
+// resources needed so that no two overlapping intervals share a resource, and
+// assigns every interval to a resource.
+package interval
+
+import (
+	"cmp"
+	"sort"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Interval is the half-open interval [Start, End). Two intervals overlap if
+// each one starts before the other one ends, so an interval ending at t and an
+// interval starting at t can share a resource.
+type Interval[T cmp.Ordered] struct {
+	Start T
+	End   T
+}
+
+// Schedule returns the minimum number of resources needed to hold every
+// interval, and assign[i] is the resource, numbered from 0, assigned to
+// intervals[i]. An interval always takes the lowest-numbered free resource.
+// Schedule runs in O(n log n) time and panics if an interval ends before it
+// starts.
+func Schedule[T cmp.Ordered](intervals []Interval[T]) (resources int, assign []int) {
+	order := make([]int, len(intervals))
+	for i, iv := range intervals {
+		if cmp.Less(iv.End, iv.Start) {
+			panic("interval: Schedule expects intervals that do not end before they start")
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return cmp.Less(intervals[order[a]].Start, intervals[order[b]].Start)
+	})
+
+	// busy maps the end of the active intervals to the resources holding
+	// them, and free holds the released resources keyed by their number.
+	busy := &fibheap.Heap[T, []int]{}
+	ends := make(map[T]*fibheap.Element[T, []int])
+	free := &fibheap.Heap[int, struct{}]{}
+
+	assign = make([]int, len(intervals))
+	for _, i := range order {
+		iv := intervals[i]
+		for _, e := range busy.ExtractUntil(iv.Start) {
+			delete(ends, e.Key())
+			for _, r := range e.Value {
+				free.Insert(r, struct{}{})
+			}
+		}
+
+		r := resources
+		if e := free.ExtractMin(); e != nil {
+			r = e.Key()
+		} else {
+			resources++
+		}
+		assign[i] = r
+
+		if e, ok := ends[iv.End]; ok {
+			e.Value = append(e.Value, r)
+		} else {
+			ends[iv.End] = busy.Insert(iv.End, []int{r})
+		}
+	}
+	return resources, assign
+}
+
+// MaxOverlap returns the maximum number of intervals that overlap at a single
+// point, which equals the number of resources returned by Schedule.
+func MaxOverlap[T cmp.Ordered](intervals []Interval[T]) int {
+	resources, _ := Schedule(intervals)
+	return resources
+}
@@ -0,0 +1,71 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSchedule(t *testing.T) {
+	intervals := []Interval[int]{
+		{0, 30},
+		{5, 10},
+		{15, 20},
+		{10, 15},
+		{30, 40},
+		{5, 35},
+	}
+	resources, assign := Schedule(intervals)
+	if resources != 3 {
+		t.Fatalf("expected 3 resources, got %d", resources)
+	}
+	expected := []int{0, 1, 1, 1, 0, 2}
+	for i := range expected {
+		if assign[i] != expected[i] {
+			t.Fatalf("expected assignment %v, got %v", expected, assign)
+		}
+	}
+
+	if resources, assign := Schedule[int](nil); resources != 0 || len(assign) != 0 {
+		t.Fatal("no interval should need no resource")
+	}
+}
+
+func TestScheduleRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	intervals := make([]Interval[float64], 500)
+	for i := range intervals {
+		start := r.Float64() * 1000
+		intervals[i] = Interval[float64]{start, start + r.Float64()*50}
+	}
+	resources, assign := Schedule(intervals)
+
+	// no two overlapping intervals may share a resource
+	for i := range intervals {
+		if assign[i] < 0 || assign[i] >= resources {
+			t.Fatalf("resource %d out of range", assign[i])
+		}
+		for j := i + 1; j < len(intervals); j++ {
+			a, b := intervals[i], intervals[j]
+			if assign[i] == assign[j] && a.Start < b.End && b.Start < a.End {
+				t.Fatalf("overlapping intervals %v and %v share resource %d", a, b, assign[i])
+			}
+		}
+	}
+
+	// the number of resources must equal the maximum overlap
+	max := 0
+	for _, a := range intervals {
+		n := 0
+		for _, b := range intervals {
+			if b.Start <= a.Start && a.Start < b.End {
+				n++
+			}
+		}
+		if n > max {
+			max = n
+		}
+	}
+	if resources != max || MaxOverlap(intervals) != max {
+		t.Fatalf("expected %d resources, got %d", max, resources)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build ignore
+
+// gen.go generates int.go, which implements IntHeap, and its tests from
+// uint64.go, which implements Uint64Heap, and its tests by replacing the key
+// type. Run it with go generate.
+package main
+
+import (
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	generate("uint64.go", "int.go")
+	generate("uint64_test.go", "int_test.go")
+}
+
+// generate writes the file dst generated from the file src.
+func generate(src, dst string) {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := strings.NewReplacer("Uint64", "Int", "uint64", "int")
+	out := "// Code generated by gen.go from " + src + ". DO NOT EDIT.\n\n" + r.Replace(string(b))
+	if b, err = format.Source([]byte(out)); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(dst, b, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,20 @@
+// Package intheap implements Fibonacci heaps specialized to integer keys:
+// Uint64Heap orders uint64 keys and IntHeap orders int keys, and both store an
+// int value with every key, such as the index of a vertex. They have the
+// amortized bounds of package fibheap, but they are not generic: keys are
+// compared with the < operator, and are stored inline with the links in a
+// single slice of fixed-size nodes. The nodes hold no pointers, so the garbage
+// collector does not scan them however large the heap grows, and a heap which
+// has reached its size allocates no more.
+//
+// Elements are referred to by handles, which are the indices of their nodes. A
+// handle is valid until its element is extracted or deleted, after which a
+// later Insert may reuse it for another element.
+//
+// IntHeap is generated from Uint64Heap by gen.go.
+package intheap
+
+//go:generate go run gen.go
+
+// Handle refers to an element of a heap. The zero Handle refers to no element.
+type Handle uint32
@@ -0,0 +1,31 @@
+package intheap
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGenerated fails if the files generated by gen.go are out of date.
+func TestGenerated(t *testing.T) {
+	for src, dst := range map[string]string{"uint64.go": "int.go", "uint64_test.go": "int_test.go"} {
+		b, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := strings.NewReplacer("Uint64", "Int", "uint64", "int")
+		expected, err := format.Source([]byte("// Code generated by gen.go from " + src + ". DO NOT EDIT.\n\n" + r.Replace(string(b))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, expected) {
+			t.Errorf("%s is out of date, run go generate", dst)
+		}
+	}
+}
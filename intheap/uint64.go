@@ -0,0 +1,301 @@
+package intheap
+
+import "math"
+
+// uint64Node is an element of the heap, stored in a slot of its nodes.
+type uint64Node struct {
+	key   uint64
+	value int
+	// slot indices of the parent, the siblings and a child, where slot 0
+	// stands for none. A free slot has l set to 0, and r linking the list of
+	// free slots.
+	p, l, r, child uint32
+	// store mark in the LSB
+	degree uint32
+}
+
+// Uint64Heap represents a Fibonacci heap of uint64 keys with int values. The
+// zero value is an empty heap.
+type Uint64Heap struct {
+	// nodes[0] is never used, so that slot 0 stands for none
+	nodes []uint64Node
+	// first slot of the list of reusable slots linked by r
+	free     uint32
+	min      uint32
+	elements int
+	// degree table of consolidate, kept between calls
+	table []uint32
+}
+
+// Size returns the number of elements in the heap h
+func (h *Uint64Heap) Size() int {
+	return h.elements
+}
+
+// Grow makes room for n more elements in the heap h, so that the next n
+// insertions do not allocate.
+func (h *Uint64Heap) Grow(n int) {
+	if n < 0 {
+		panic("intheap: Grow expects a non-negative count")
+	}
+	if len(h.nodes) == 0 {
+		n++
+	}
+	if n > cap(h.nodes)-len(h.nodes) {
+		nodes := make([]uint64Node, len(h.nodes), len(h.nodes)+n)
+		copy(nodes, h.nodes)
+		h.nodes = nodes
+	}
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// handle of the inserted element with amortized running time Θ(1)
+func (h *Uint64Heap) Insert(key uint64, value int) Handle {
+	i := h.alloc()
+	h.nodes[i] = uint64Node{key: key, value: value, l: i, r: i}
+	h.elements++
+	if h.min == 0 {
+		h.min = i
+	} else {
+		h.splice(h.min, i)
+		if key < h.nodes[h.min].key {
+			h.min = i
+		}
+	}
+	return Handle(i)
+}
+
+// alloc returns a free slot, reusing the slot of a removed element if there is
+// one.
+func (h *Uint64Heap) alloc() uint32 {
+	if i := h.free; i != 0 {
+		h.free = h.nodes[i].r
+		return i
+	}
+	if len(h.nodes) == 0 {
+		h.nodes = append(h.nodes, uint64Node{})
+	}
+	if len(h.nodes) == math.MaxUint32 {
+		panic("intheap: too many elements")
+	}
+	h.nodes = append(h.nodes, uint64Node{})
+	return uint32(len(h.nodes) - 1)
+}
+
+// Min fetches the minimum key and its value from the heap h with running time
+// Θ(1). The boolean is false if h is empty.
+func (h *Uint64Heap) Min() (key uint64, value int, ok bool) {
+	if h.min == 0 {
+		return 0, 0, false
+	}
+	x := &h.nodes[h.min]
+	return x.key, x.value, true
+}
+
+// ExtractMin fetches and removes the minimum key and its value from the heap h
+// with amortized running time O(log n). The boolean is false if h is empty.
+func (h *Uint64Heap) ExtractMin() (key uint64, value int, ok bool) {
+	if h.min == 0 {
+		return 0, 0, false
+	}
+	i := h.min
+	z := &h.nodes[i]
+	key, value = z.key, z.value
+	if c := z.child; c != 0 {
+		for j := c; ; {
+			e := &h.nodes[j]
+			e.p = 0
+			e.degree &^= 1
+			if j = e.r; j == c {
+				break
+			}
+		}
+		h.splice(i, c)
+		z.child = 0
+	}
+	if z.r == i {
+		h.min = 0
+	} else {
+		h.min = z.r
+		h.unlink(i)
+		h.consolidate()
+	}
+	h.elements--
+	*z = uint64Node{r: h.free}
+	h.free = i
+	return key, value, true
+}
+
+// Contains reports whether the handle x refers to an element of the heap h.
+func (h *Uint64Heap) Contains(x Handle) bool {
+	return x != 0 && int(x) < len(h.nodes) && h.nodes[x].l != 0
+}
+
+// Key returns the key of the element x. Key panics if x does not refer to an
+// element of the heap h.
+func (h *Uint64Heap) Key(x Handle) uint64 {
+	h.mustContain(x, "Key")
+	return h.nodes[x].key
+}
+
+// Value returns the value of the element x. Value panics if x does not refer to
+// an element of the heap h.
+func (h *Uint64Heap) Value(x Handle) int {
+	h.mustContain(x, "Value")
+	return h.nodes[x].value
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// Θ(1). If the new key is larger or equal than the key of x, Decreasing does
+// nothing. Decreasing panics if x does not refer to an element of the heap h.
+func (h *Uint64Heap) Decreasing(x Handle, key uint64) {
+	h.mustContain(x, "Decreasing")
+	i := uint32(x)
+	e := &h.nodes[i]
+	if key >= e.key {
+		return
+	}
+	e.key = key
+	if p := e.p; p != 0 && key < h.nodes[p].key {
+		h.cut(i, p)
+		h.cascadingCut(p)
+	}
+	if key < h.nodes[h.min].key {
+		h.min = i
+	}
+}
+
+// Delete removes the element x from the heap h with amortized running time
+// O(log n). Delete panics if x does not refer to an element of the heap h.
+func (h *Uint64Heap) Delete(x Handle) {
+	h.mustContain(x, "Delete")
+	i := uint32(x)
+	if p := h.nodes[i].p; p != 0 {
+		h.cut(i, p)
+		h.cascadingCut(p)
+	}
+	h.min = i
+	h.ExtractMin()
+}
+
+// Clear removes all the elements from the heap h with running time Θ(1),
+// keeping its storage for later insertions. Every handle of h is invalidated.
+func (h *Uint64Heap) Clear() {
+	h.nodes = h.nodes[:0]
+	h.free = 0
+	h.min = 0
+	h.elements = 0
+}
+
+// mustContain panics with a message naming the method if the handle x does not
+// refer to an element of the heap h.
+func (h *Uint64Heap) mustContain(x Handle, method string) {
+	if !h.Contains(x) {
+		panic("intheap: " + method + " expects an element of the heap")
+	}
+}
+
+// splice joins the circular list starting at the slot j into the circular list
+// of the slot i, right after i.
+func (h *Uint64Heap) splice(i, j uint32) {
+	x, y := &h.nodes[i], &h.nodes[j]
+	r, l := x.r, y.l
+	x.r = j
+	y.l = i
+	h.nodes[r].l = l
+	h.nodes[l].r = r
+}
+
+// unlink removes the slot i from its circular list.
+func (h *Uint64Heap) unlink(i uint32) {
+	x := &h.nodes[i]
+	h.nodes[x.l].r = x.r
+	h.nodes[x.r].l = x.l
+	x.l, x.r = i, i
+}
+
+// link removes the root y from the root list, and makes y a child of the root
+// x.
+func (h *Uint64Heap) link(y, x uint32) {
+	h.unlink(y)
+	c, p := &h.nodes[y], &h.nodes[x]
+	c.p = x
+	c.degree &^= 1
+	if p.child == 0 {
+		p.child = y
+	} else {
+		h.splice(p.child, y)
+	}
+	p.degree += 2
+}
+
+// cut moves the slot i from the children of the slot p to the root list.
+func (h *Uint64Heap) cut(i, p uint32) {
+	x, y := &h.nodes[i], &h.nodes[p]
+	if x.r == i {
+		y.child = 0
+	} else {
+		if y.child == i {
+			y.child = x.r
+		}
+		h.unlink(i)
+	}
+	y.degree -= 2
+	x.p = 0
+	x.degree &^= 1
+	h.splice(h.min, i)
+}
+
+// cascadingCut marks the slot i if it lost its first child, or cuts it and
+// continues with its parent if it lost its second one.
+func (h *Uint64Heap) cascadingCut(i uint32) {
+	for {
+		x := &h.nodes[i]
+		p := x.p
+		if p == 0 {
+			return
+		}
+		if x.degree&1 == 0 {
+			x.degree |= 1
+			return
+		}
+		h.cut(i, p)
+		i = p
+	}
+}
+
+// consolidate links the roots of equal degree until every root has a distinct
+// degree, and finds the new minimum.
+func (h *Uint64Heap) consolidate() {
+	a := h.table
+	clear(a)
+	end := h.nodes[h.min].l
+	for w := h.min; ; {
+		next := h.nodes[w].r
+		x := w
+		d := h.nodes[x].degree >> 1
+		for ; int(d) < len(a) && a[d] != 0; d++ {
+			y := a[d]
+			if h.nodes[y].key < h.nodes[x].key {
+				x, y = y, x
+			}
+			h.link(y, x)
+			a[d] = 0
+		}
+		for int(d) >= len(a) {
+			a = append(a, 0)
+		}
+		a[d] = x
+		if w == end {
+			break
+		}
+		w = next
+	}
+	h.table = a
+	h.min = 0
+	for _, i := range a {
+		if i != 0 && (h.min == 0 || h.nodes[i].key < h.nodes[h.min].key) {
+			h.min = i
+		}
+	}
+}
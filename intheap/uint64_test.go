@@ -0,0 +1,247 @@
+package intheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// checkUint64 verifies the links, the degrees, the heap order and the size of
+// the heap h.
+func checkUint64(t *testing.T, h *Uint64Heap) {
+	t.Helper()
+	if h.min == 0 {
+		if h.elements != 0 {
+			t.Fatalf("empty root list with size %d", h.elements)
+		}
+		return
+	}
+	var count int
+	var walk func(first, parent uint32)
+	walk = func(first, parent uint32) {
+		for i := first; ; {
+			x := &h.nodes[i]
+			count++
+			if x.p != parent || h.nodes[x.r].l != i || h.nodes[x.l].r != i {
+				t.Fatalf("slot %d is badly linked", i)
+			}
+			if parent != 0 && x.key < h.nodes[parent].key {
+				t.Fatalf("slot %d is smaller than its parent", i)
+			}
+			if parent == 0 && x.key < h.nodes[h.min].key {
+				t.Fatalf("root %d is smaller than the minimum", i)
+			}
+			degree := 0
+			if c := x.child; c != 0 {
+				for j := c; ; {
+					degree++
+					if j = h.nodes[j].r; j == c {
+						break
+					}
+				}
+				walk(c, i)
+			}
+			if int(x.degree>>1) != degree {
+				t.Fatalf("slot %d has degree %d but %d children", i, x.degree>>1, degree)
+			}
+			if i = x.r; i == first {
+				break
+			}
+		}
+	}
+	walk(h.min, 0)
+	if count != h.elements {
+		t.Fatalf("counted %d elements instead of %d", count, h.elements)
+	}
+}
+
+func TestUint64Heap(t *testing.T) {
+	h := &Uint64Heap{}
+	if _, _, ok := h.Min(); ok {
+		t.Fatal("expected an empty heap")
+	}
+	if _, _, ok := h.ExtractMin(); ok {
+		t.Fatal("expected an empty heap")
+	}
+	for i, k := range []uint64{5, 3, 8, 1, 9, 2} {
+		x := h.Insert(k, i)
+		if h.Key(x) != k || h.Value(x) != i {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", k, i, h.Key(x), h.Value(x))
+		}
+	}
+	if k, v, ok := h.Min(); h.Size() != 6 || !ok || k != 1 || v != 3 {
+		t.Fatalf("expected 6 elements and minimum (1, 3), got %d and (%d, %d)", h.Size(), k, v)
+	}
+	for _, expected := range []uint64{1, 2, 3, 5, 8, 9} {
+		if k, _, ok := h.ExtractMin(); !ok || k != expected {
+			t.Fatalf("expected %d, got %d", expected, k)
+		}
+		checkUint64(t, h)
+	}
+}
+
+func TestUint64HeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Uint64Heap{}
+	var handles []Handle
+	var model []uint64
+	for i := 0; i < 10000; i++ {
+		switch op := r.Intn(12); {
+		case op < 5 || len(handles) == 0:
+			k := uint64(r.Intn(10000))
+			handles = append(handles, h.Insert(k, i))
+			model = append(model, k)
+		case op < 8:
+			j := r.Intn(len(handles))
+			k := model[j] - uint64(r.Intn(int(model[j])+1))
+			h.Decreasing(handles[j], k)
+			model[j] = k
+		case op < 9:
+			j := r.Intn(len(handles))
+			h.Delete(handles[j])
+			handles = slices.Delete(handles, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		default:
+			min := slices.Min(model)
+			k, _, ok := h.ExtractMin()
+			if !ok || k != min {
+				t.Fatalf("operation %d: expected minimum %d, got %d", i, min, k)
+			}
+			j := slices.Index(model, k)
+			handles = slices.Delete(handles, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		}
+		if h.Size() != len(model) {
+			t.Fatalf("operation %d: expected size %d, got %d", i, len(model), h.Size())
+		}
+		if i%100 == 0 {
+			checkUint64(t, h)
+		}
+	}
+}
+
+func TestUint64HeapReuse(t *testing.T) {
+	h := &Uint64Heap{}
+	h.Grow(100)
+	if n := testing.AllocsPerRun(10, func() {
+		for i := 0; i < 100; i++ {
+			h.Insert(uint64(i), i)
+		}
+		for h.Size() > 0 {
+			h.ExtractMin()
+		}
+	}); n != 0 {
+		t.Fatalf("expected no allocation, got %v", n)
+	}
+	x := h.Insert(1, 1)
+	h.Delete(x)
+	if h.Contains(x) {
+		t.Fatal("expected the deleted handle to be removed")
+	}
+	// the slot of the deleted element is reused
+	if y := h.Insert(2, 2); y != x || !h.Contains(x) || h.Key(x) != 2 {
+		t.Fatal("expected Insert to reuse the free slot")
+	}
+	h.Clear()
+	if h.Size() != 0 || h.Contains(x) {
+		t.Fatal("expected Clear to empty the heap")
+	}
+	h.Insert(3, 3)
+	if k, _, _ := h.Min(); k != 3 {
+		t.Fatalf("expected minimum 3, got %d", k)
+	}
+	checkUint64(t, h)
+}
+
+func TestUint64HeapMisuse(t *testing.T) {
+	h := &Uint64Heap{}
+	x := h.Insert(1, 0)
+	h.Insert(3, 0)
+	h.ExtractMin()
+	for name, fn := range map[string]func(){
+		"Decreasing extracted": func() { h.Decreasing(x, 0) },
+		"Delete extracted":     func() { h.Delete(x) },
+		"Key zero":             func() { h.Key(0) },
+		"Value out of range":   func() { h.Value(100) },
+		"Grow negative":        func() { h.Grow(-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// uint64Graph returns a random graph of n vertices with the given number of
+// edges per vertex, as lists of targets and weights.
+func uint64Graph(n, edges int) (to [][]int, weight [][]uint64) {
+	r := rand.New(rand.NewSource(1))
+	to = make([][]int, n)
+	weight = make([][]uint64, n)
+	for u := range to {
+		for i := 0; i < edges; i++ {
+			to[u] = append(to[u], r.Intn(n))
+			weight[u] = append(weight[u], uint64(r.Intn(1<<20)))
+		}
+	}
+	return to, weight
+}
+
+func BenchmarkUint64HeapDijkstra(b *testing.B) {
+	to, weight := uint64Graph(1<<16, 8)
+	n := len(to)
+	b.Run("intheap", func(b *testing.B) {
+		h := &Uint64Heap{}
+		queued := make([]Handle, n)
+		done := make([]bool, n)
+		for i := 0; i < b.N; i++ {
+			clear(queued)
+			clear(done)
+			h.Clear()
+			queued[0] = h.Insert(0, 0)
+			for h.Size() > 0 {
+				d, u, _ := h.ExtractMin()
+				done[u] = true
+				for j, v := range to[u] {
+					switch {
+					case done[v]:
+					case queued[v] == 0:
+						queued[v] = h.Insert(d+weight[u][j], v)
+					default:
+						h.Decreasing(queued[v], d+weight[u][j])
+					}
+				}
+			}
+		}
+	})
+	b.Run("fibheap", func(b *testing.B) {
+		queued := make([]*fibheap.Element[uint64, int], n)
+		done := make([]bool, n)
+		for i := 0; i < b.N; i++ {
+			clear(queued)
+			clear(done)
+			h := &fibheap.Heap[uint64, int]{}
+			queued[0] = h.Insert(0, 0)
+			for h.Size() > 0 {
+				x := h.ExtractMin()
+				d, u := x.Key(), x.Value
+				done[u] = true
+				for j, v := range to[u] {
+					switch {
+					case done[v]:
+					case queued[v] == nil:
+						queued[v] = h.Insert(d+weight[u][j], v)
+					default:
+						h.Decreasing(queued[v], d+weight[u][j])
+					}
+				}
+			}
+		}
+	})
+}
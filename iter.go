@@ -0,0 +1,58 @@
+package fibheap
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the key-value pairs of the heap h, in no
+// particular order. The heap is not modified and must not be modified during
+// the iteration.
+func (h *HeapOf[K, V, O]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		h.each(func(e *Element[K, V]) bool {
+			return yield(e.key, e.Value)
+		})
+	}
+}
+
+// Elements returns an iterator over the elements of the heap h, in no
+// particular order. Pinned elements are included while suspended ones are not.
+// The heap is not modified and must not be modified during the iteration.
+func (h *HeapOf[K, V, O]) Elements() iter.Seq[*Element[K, V]] {
+	return func(yield func(*Element[K, V]) bool) {
+		h.each(yield)
+	}
+}
+
+// each calls yield for every element of the heap h, including pinned elements,
+// until yield returns false.
+func (h *HeapOf[K, V, O]) each(yield func(*Element[K, V]) bool) bool {
+	if h == nil {
+		return true
+	}
+	if !eachList(h.min, yield) {
+		return false
+	}
+	for e := range h.pinned {
+		if !yield(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// eachList calls yield for every element in the circular list starting at x
+// and their descendants, except for tombstones, until yield returns false.
+func eachList[K any, V any](x *Element[K, V], yield func(*Element[K, V]) bool) bool {
+	if x == nil {
+		return true
+	}
+	for e := x; ; {
+		if (e.flags&tombstone == 0 && !yield(e)) || !eachList(e.children, yield) {
+			return false
+		}
+		if e = e.r; e == x {
+			return true
+		}
+	}
+}
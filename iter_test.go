@@ -0,0 +1,62 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapAll(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, i*i)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[50], -1)
+	h.Pin(elements[60])
+	h.Suspend(elements[70])
+
+	seen := make(map[int]int)
+	for k, v := range h.All() {
+		seen[k] = v
+	}
+	assert(t, len(seen), 98)
+	if v, ok := seen[-1]; !ok || v != 2500 {
+		t.Fatal("All should yield the decreased element")
+	}
+	if _, ok := seen[60]; !ok {
+		t.Fatal("All should yield the pinned element")
+	}
+	if _, ok := seen[70]; ok {
+		t.Fatal("All should not yield the suspended element")
+	}
+	assert(t, h.Size(), 98)
+	assert(t, h.Min().Key(), -1)
+
+	count := 0
+	for range h.All() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	assert(t, count, 10)
+}
+
+func TestHeapElements(t *testing.T) {
+	h := &Heap[int, any]{}
+	for range h.Elements() {
+		t.Fatal("empty heap should yield nothing")
+	}
+	elements := make(map[*Element[int, any]]bool)
+	for i := 0; i < 100; i++ {
+		elements[h.Insert(i, nil)] = true
+	}
+	h.ExtractMin()
+	for e := range h.Elements() {
+		if !elements[e] {
+			t.Fatalf("unexpected element %d", e.Key())
+		}
+		delete(elements, e)
+	}
+	assert(t, len(elements), 1)
+}
@@ -0,0 +1,60 @@
+package fibheap
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// MarshalJSON implements the json.Marshaler interface. The heap h is encoded as
+// an array of {"key": ..., "value": ...} objects sorted by key, which takes
+// O(n log n) time. Pinned elements are included while suspended ones are not.
+func (h *HeapOf[K, V, O]) MarshalJSON() ([]byte, error) {
+	list := make([]*Element[K, V], 0, h.Size())
+	h.each(func(e *Element[K, V]) bool {
+		list = append(list, e)
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return h.before(list[i], list[j])
+	})
+	out := make([]Pair[K, V], len(list))
+	for i, e := range list {
+		out[i] = Pair[K, V]{Key: e.key, Value: e.Value}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It replaces the
+// contents of the heap h by inserting the elements of an array encoded by
+// MarshalJSON, in order. The heap h keeps its comparison function, stability
+// and callbacks.
+func (h *HeapOf[K, V, O]) UnmarshalJSON(data []byte) error {
+	h.mustNotNil("UnmarshalJSON")
+	var list []Pair[K, V]
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	h.min = nil
+	h.elements = 0
+	h.tombstones = 0
+	h.roots = 0
+	h.marked = 0
+	h.pinned = nil
+	h.suspended = nil
+	// the elements held so far belong to no heap any more, as after Clear
+	if h.owner != nil {
+		h.owner.cleared = true
+		h.owner = nil
+	}
+	h.ids, h.sized = nil, false
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opClear})
+	}
+	for _, e := range list {
+		h.Insert(e.Key, e.Value)
+	}
+	if len(list) == 0 && h.watches != nil {
+		h.notify()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package fibheap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHeapMarshalJSON(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.Insert(3, "three")
+	h.Insert(1, "one")
+	h.Insert(2, "two")
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"key":1,"value":"one"},{"key":2,"value":"two"},{"key":3,"value":"three"}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+	assert(t, h.Size(), 3)
+
+	empty, err := json.Marshal(&Heap[int, string]{})
+	if err != nil || string(empty) != "[]" {
+		t.Fatalf("expected [], got %s", empty)
+	}
+}
+
+func TestHeapUnmarshalJSON(t *testing.T) {
+	var q struct {
+		Jobs *Heap[string, int] `json:"jobs"`
+	}
+	data := `{"jobs":[{"key":"b","value":2},{"key":"c","value":3},{"key":"a","value":1}]}`
+	if err := json.Unmarshal([]byte(data), &q); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		assert(t, q.Jobs.ExtractMin().Value, i)
+	}
+
+	h := &Heap[int, int]{}
+	h.Insert(100, 100)
+	if err := json.Unmarshal([]byte(`[{"key":1,"value":1}]`), h); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.Size(), 1)
+	assert(t, h.Min().Key(), 1)
+
+	if err := json.Unmarshal([]byte(`{"key":1}`), h); err == nil {
+		t.Fatal("UnmarshalJSON should fail on an object")
+	}
+}
+
+func TestHeapUnmarshalJSONDetaches(t *testing.T) {
+	h := &Heap[int, int]{}
+	x := h.Insert(2, 2)
+	y := h.Insert(3, 3)
+	h.Pin(y)
+	if err := json.Unmarshal([]byte(`[{"key":1,"value":1}]`), h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Contains(x) || h.Contains(y) {
+		t.Fatal("expected the replaced elements to leave the heap")
+	}
+	// the replaced elements can be reused as extracted ones
+	h.Recycle(x)
+	h.InsertElement(y.Init(0, 0))
+	assert(t, h.Size(), 2)
+	assert(t, h.Min().Key(), 0)
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
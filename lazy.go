@@ -0,0 +1,75 @@
+package fibheap
+
+// SetLazyDelete sets whether Delete removes elements of the heap h lazily. A
+// lazily deleted element stays in its tree as a tombstone, which costs Θ(1),
+// and is removed when it becomes a root during a later consolidation, so the
+// cleanup is amortized into ExtractMin. Lazy deletion pays off when many
+// elements are deleted before they reach the minimum, such as cancelled events
+// of a simulation, at the price of the memory held by tombstones.
+func (h *HeapOf[K, V, O]) SetLazyDelete(lazy bool) {
+	h.mustNotNil("SetLazyDelete")
+	h.lazy = lazy
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opLazy, Int: boolInt(lazy)})
+	}
+}
+
+// LazyDelete reports whether Delete removes elements of the heap h lazily.
+func (h *HeapOf[K, V, O]) LazyDelete() bool {
+	return h != nil && h.lazy
+}
+
+// Tombstones returns the number of elements lazily deleted from the heap h
+// which are still held in its trees.
+func (h *HeapOf[K, V, O]) Tombstones() int {
+	if h == nil {
+		return 0
+	}
+	return h.tombstones
+}
+
+// bury turns the element x, which is in a tree but is not the minimum, into a
+// tombstone.
+func (h *HeapOf[K, V, O]) bury(x *Element[K, V]) {
+	x.flags |= tombstone
+	x.owner = nil
+	h.elements--
+	h.tombstones++
+	h.extracted(x)
+}
+
+// purge removes the tombstones among the roots of the heap h, whose children
+// become roots in turn, and leaves h.min at any remaining root, or nil.
+func (h *HeapOf[K, V, O]) purge() {
+	var roots []*Element[K, V]
+	for w := h.min; ; {
+		roots = append(roots, w)
+		if w = w.r; w == h.min {
+			break
+		}
+	}
+	var live *Element[K, V]
+	h.roots = 0
+	for i := 0; i < len(roots); i++ {
+		x := roots[i]
+		if x.flags&tombstone == 0 {
+			live = live.append(x)
+			h.roots++
+			continue
+		}
+		h.tombstones--
+		if c := x.children; c != nil {
+			for e := c; ; {
+				e.p = nil
+				h.unmark(e)
+				roots = append(roots, e)
+				if e = e.r; e == c {
+					break
+				}
+			}
+		}
+		x.p, x.l, x.r, x.children = nil, nil, nil, nil
+		x.degree, x.below = 0, 0
+	}
+	h.min = live
+}
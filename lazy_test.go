@@ -0,0 +1,130 @@
+package fibheap
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHeapLazyDelete(t *testing.T) {
+	h := &Heap[int, any]{}
+	h.SetLazyDelete(true)
+	if !h.LazyDelete() {
+		t.Fatal("expected lazy deletion")
+	}
+	elements := make([]*Element[int, any], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, nil)
+	}
+	h.ExtractMin()
+	for i := 2; i < 100; i += 2 {
+		h.Delete(elements[i])
+	}
+	assert(t, h.Size(), 50)
+	assert(t, h.Tombstones(), 49)
+	if h.Contains(elements[2]) {
+		t.Fatal("expected a deleted element not to belong to the heap")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	// deleting the minimum removes it at once
+	h.Delete(elements[1])
+	assert(t, h.Min().Key(), 3)
+	n := 0
+	for range h.All() {
+		n++
+	}
+	assert(t, n, 49)
+	for i := 3; i < 100; i += 2 {
+		assert(t, h.ExtractMin().Key(), i)
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert(t, h.Size(), 0)
+	assert(t, h.Tombstones(), 0)
+}
+
+func TestHeapLazyDeleteRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	h.SetLazyDelete(true)
+	var elements []*Element[int, int]
+	var model []int
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(10); {
+		case op < 4 || len(elements) == 0:
+			k := r.Intn(1000)
+			elements = append(elements, h.Insert(k, 0))
+			model = append(model, k)
+		case op < 5:
+			j := r.Intn(len(elements))
+			k := elements[j].Key() - r.Intn(100)
+			h.Decreasing(elements[j], k)
+			model[j] = k
+		case op < 8:
+			j := r.Intn(len(elements))
+			h.Delete(elements[j])
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		default:
+			x := h.ExtractMin()
+			j := slices.Index(elements, x)
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		}
+		if err := h.Check(); err != nil {
+			t.Fatalf("operation %d: %v", i, err)
+		}
+		assert(t, h.Size(), len(model))
+		if len(model) > 0 && h.Min().Key() != slices.Min(model) {
+			t.Fatalf("expected minimum %d, got %d", slices.Min(model), h.Min().Key())
+		}
+	}
+}
+
+func TestHeapLazyDeleteCopies(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.SetLazyDelete(true)
+	var elements []*Element[int, string]
+	for i := 0; i < 20; i++ {
+		elements = append(elements, h.Insert(i, "v"))
+	}
+	h.ExtractMin()
+	h.Delete(elements[5])
+	h.Delete(elements[17])
+
+	var b strings.Builder
+	if err := h.Dot(&b); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, strings.Count(b.String(), "diagonals"), 2)
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Heap[int, string]{}
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []*Heap[int, string]{h.Clone(), g, h.Clone().Union(&Heap[int, string]{})} {
+		if err := c.Check(); err != nil {
+			t.Fatal(err)
+		}
+		assert(t, c.Size(), 17)
+		assert(t, c.Tombstones(), 2)
+		if !c.LazyDelete() {
+			t.Fatal("expected lazy deletion")
+		}
+		var keys []int
+		for c.Size() > 0 {
+			keys = append(keys, c.ExtractMin().Key())
+		}
+		if !slices.Equal(keys, []int{1, 2, 3, 4, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 18, 19}) {
+			t.Fatalf("unexpected keys %v", keys)
+		}
+	}
+}
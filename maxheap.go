@@ -0,0 +1,184 @@
+package fibheap
+
+import (
+	"cmp"
+	"math/rand/v2"
+)
+
+// MaxHeap represents a max-oriented fibonacci heap of keys of an ordered type,
+// which fetches and extracts the maximum key instead of the minimum one. NaN
+// keys are the smallest ones, so they are extracted last. The zero value is an
+// empty heap.
+type MaxHeap[K cmp.Ordered, V any] = MaxHeapOf[K, V, Ordered[K]]
+
+// MaxHeapFunc represents a max-oriented fibonacci heap whose keys are ordered
+// by a comparison function. It must be created by NewMaxHeapFunc.
+type MaxHeapFunc[K any, V any] = MaxHeapOf[K, V, Func[K]]
+
+// MaxHeapOf represents a max-oriented fibonacci heap whose keys are ordered by
+// O. It is used through its aliases MaxHeap and MaxHeapFunc.
+type MaxHeapOf[K any, V any, O Order[K]] struct {
+	heap HeapOf[K, V, reverse[K, O]]
+}
+
+// NewMaxHeapFunc returns an empty max-oriented heap which orders keys with less.
+// The function less must report whether a is strictly smaller than b, and
+// define a strict weak ordering.
+func NewMaxHeapFunc[K any, V any](less func(a, b K) bool) *MaxHeapFunc[K, V] {
+	if less == nil {
+		panic("fibheap: NewMaxHeapFunc expects a non-nil less function")
+	}
+	h := &MaxHeapFunc[K, V]{}
+	h.heap.order.order = less
+	return h
+}
+
+// SetStable sets whether the heap h extracts elements with equal keys in
+// insertion order, as Heap.SetStable. SetStable panics if the heap h is not
+// empty.
+func (h *MaxHeapOf[K, V, O]) SetStable(stable bool) {
+	h.heap.SetStable(stable)
+}
+
+// SetConsolidateThreshold sets the length of the root list above which the
+// heap h is consolidated on insert and meld, as Heap.SetConsolidateThreshold.
+func (h *MaxHeapOf[K, V, O]) SetConsolidateThreshold(roots int) {
+	h.heap.SetConsolidateThreshold(roots)
+}
+
+// Stable reports whether the heap h breaks ties by insertion order.
+func (h *MaxHeapOf[K, V, O]) Stable() bool {
+	return h.heap.Stable()
+}
+
+// Size returns the number of elements in the heap h
+func (h *MaxHeapOf[K, V, O]) Size() int {
+	return h.heap.Size()
+}
+
+// Contains reports whether the element x belongs to the heap h.
+func (h *MaxHeapOf[K, V, O]) Contains(x *Element[K, V]) bool {
+	return h.heap.Contains(x)
+}
+
+// ByID returns the element of the heap h whose ID is id, or nil, as Heap.ByID.
+func (h *MaxHeapOf[K, V, O]) ByID(id uint64) *Element[K, V] {
+	return h.heap.ByID(id)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with amortized running time Θ(1)
+func (h *MaxHeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	return h.heap.Insert(key, value)
+}
+
+// InsertElement inserts the element x, which belongs to no heap, to the heap h
+// without allocating, as Heap.InsertElement.
+func (h *MaxHeapOf[K, V, O]) InsertElement(x *Element[K, V]) {
+	h.heap.InsertElement(x)
+}
+
+// Max fetches the maximum key from the heap h with running time Θ(1)
+func (h *MaxHeapOf[K, V, O]) Max() *Element[K, V] {
+	return h.heap.Min()
+}
+
+// MaxN returns the k largest elements of the heap h in descending order without
+// extracting them, as Heap.MinN.
+func (h *MaxHeapOf[K, V, O]) MaxN(k int) []*Element[K, V] {
+	return h.heap.MinN(k)
+}
+
+// ExtractMax fetches and removes the maximum key from the heap h with amortized
+// running time O(log n)
+func (h *MaxHeapOf[K, V, O]) ExtractMax() *Element[K, V] {
+	return h.heap.ExtractMin()
+}
+
+// ExtractRandom fetches and removes an element chosen uniformly at random, as
+// Heap.ExtractRandom.
+func (h *MaxHeapOf[K, V, O]) ExtractRandom(r *rand.Rand) *Element[K, V] {
+	return h.heap.ExtractRandom(r)
+}
+
+// Increasing increases the key of the element x with amortized running time
+// Θ(1). If the new key is smaller or equal than the key of x, Increasing does
+// nothing.
+func (h *MaxHeapOf[K, V, O]) Increasing(x *Element[K, V], key K) {
+	h.heap.Decreasing(x, key)
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// O(log n). If the new key is larger or equal than the key of x, Decreasing does
+// nothing.
+func (h *MaxHeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	h.heap.Increasing(x, key)
+}
+
+// Update changes the key of the element x to key, increasing or decreasing it
+// as needed, and returns x, as Heap.Update.
+func (h *MaxHeapOf[K, V, O]) Update(x *Element[K, V], key K) *Element[K, V] {
+	return h.heap.Update(x, key)
+}
+
+// Remove removes the element x by given a key maximumKey which is larger than
+// any key in the heap h.
+func (h *MaxHeapOf[K, V, O]) Remove(x *Element[K, V], maximumKey K) {
+	h.heap.Remove(x, maximumKey)
+}
+
+// Delete removes the element x from the heap h with amortized running time
+// O(log n), without requiring a key larger than any key in the heap.
+func (h *MaxHeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	h.heap.Delete(x)
+}
+
+// Clear removes every element from the heap h in Θ(1), as Heap.Clear.
+func (h *MaxHeapOf[K, V, O]) Clear() {
+	h.heap.Clear()
+}
+
+// Recycle hands the element x, which has been removed from a heap, back to the
+// heap h for reuse by a later Insert, as Heap.Recycle.
+func (h *MaxHeapOf[K, V, O]) Recycle(x *Element[K, V]) {
+	h.heap.Recycle(x)
+}
+
+// Stats returns statistics about the shape of the heap h with running time
+// Θ(n), as Heap.Stats.
+func (h *MaxHeapOf[K, V, O]) Stats() Stats {
+	return h.heap.Stats()
+}
+
+// SetObserver sets the observer of the operations of the heap h, as
+// Heap.SetObserver.
+func (h *MaxHeapOf[K, V, O]) SetObserver(o Observer) {
+	h.heap.SetObserver(o)
+}
+
+// Union unions the two max-oriented heaps h and g, and returns the new heap with
+// amortized running time Θ(1). The heap h and g will be reset after unioning.
+func (h *MaxHeapOf[K, V, O]) Union(g *MaxHeapOf[K, V, O]) *MaxHeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("fibheap: Union expects non-nil heap h and g")
+	}
+	return &MaxHeapOf[K, V, O]{heap: *h.heap.Union(&g.heap)}
+}
+
+// Meld moves every element of the max-oriented heap g into the heap h with
+// amortized running time Θ(1), leaving g empty.
+func (h *MaxHeapOf[K, V, O]) Meld(g *MaxHeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("fibheap: Meld expects non-nil heap h and g")
+	}
+	h.heap.Meld(&g.heap)
+}
+
+// UnionCopy returns a new max-oriented heap holding copies of the elements of
+// the heap h and g with running time O(n), leaving h and g unchanged.
+func (h *MaxHeapOf[K, V, O]) UnionCopy(g *MaxHeapOf[K, V, O]) *MaxHeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("fibheap: UnionCopy expects non-nil heap h and g")
+	}
+	return &MaxHeapOf[K, V, O]{heap: *h.heap.UnionCopy(&g.heap)}
+}
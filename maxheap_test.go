@@ -0,0 +1,108 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestMaxHeap(t *testing.T) {
+	h := &MaxHeap[uint, any]{}
+	elements := make([]*Element[uint, any], 100)
+	for i := range elements {
+		elements[i] = h.Insert(uint(i), nil)
+	}
+	assert(t, int(h.Max().Key()), 99)
+	for i := 99; i >= 90; i-- {
+		assert(t, int(h.ExtractMax().Key()), i)
+	}
+	assert(t, h.Size(), 90)
+	for i, x := range h.MaxN(3) {
+		assert(t, int(x.Key()), 89-i)
+	}
+
+	h.Increasing(elements[10], 1000)
+	h.Increasing(elements[20], 0)
+	assert(t, int(h.ExtractMax().Key()), 1000)
+	h.Remove(elements[50], 2000)
+	assert(t, h.Size(), 88)
+	for i := 89; i >= 0; i-- {
+		if i == 10 || i == 50 {
+			continue
+		}
+		assert(t, int(h.ExtractMax().Key()), i)
+	}
+	if h.ExtractMax() != nil {
+		t.Fatal("heap should be empty")
+	}
+}
+
+func TestMaxHeapFunc(t *testing.T) {
+	h := NewMaxHeapFunc[[]int, any](func(a, b []int) bool {
+		return len(a) < len(b)
+	})
+	g := NewMaxHeapFunc[[]int, any](func(a, b []int) bool {
+		return len(a) < len(b)
+	})
+	h.Insert(make([]int, 1), nil)
+	h.Insert(make([]int, 3), nil)
+	g.Insert(make([]int, 2), nil)
+	g.Insert(make([]int, 4), nil)
+	k := h.Union(g)
+	for i := 4; i > 0; i-- {
+		assert(t, len(k.ExtractMax().Key()), i)
+	}
+}
+
+func TestMaxHeapMeld(t *testing.T) {
+	h := &MaxHeap[int, any]{}
+	g := &MaxHeap[int, any]{}
+	for i := 0; i < 5; i++ {
+		h.Insert(i, nil)
+		g.Insert(i+5, nil)
+	}
+	k := h.UnionCopy(g)
+	h.Meld(g)
+	assert(t, g.Size(), 0)
+	for i := 9; i >= 0; i-- {
+		assert(t, h.ExtractMax().Key(), i)
+		assert(t, k.ExtractMax().Key(), i)
+	}
+}
+
+func TestMaxHeapDecreasing(t *testing.T) {
+	h := &MaxHeap[int, any]{}
+	elements := make([]*Element[int, any], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, nil)
+	}
+	h.Decreasing(elements[9], -1)
+	// decreasing to a larger key does nothing
+	h.Decreasing(elements[5], 20)
+	for i := 8; i >= 0; i-- {
+		assert(t, h.ExtractMax().Key(), i)
+	}
+	assert(t, h.ExtractMax().Key(), -1)
+}
+
+func TestMaxHeapStable(t *testing.T) {
+	h := &MaxHeap[int, int]{}
+	h.SetStable(true)
+	if !h.Stable() {
+		t.Fatal("the heap should be stable")
+	}
+	for i := 0; i < 100; i++ {
+		h.Insert(i%2, i)
+	}
+	for i := 0; i < 100; i++ {
+		assert(t, h.ExtractMax().Value, i%50*2+1-i/50)
+	}
+}
+
+func TestMaxHeapClear(t *testing.T) {
+	h := &MaxHeap[int, any]{}
+	x := h.Insert(1, nil)
+	h.Clear()
+	assert(t, h.Size(), 0)
+	if h.Contains(x) || h.Max() != nil {
+		t.Fatal("the cleared heap should be empty")
+	}
+}
@@ -0,0 +1,58 @@
+package fibheap
+
+import (
+	"cmp"
+	"iter"
+)
+
+// MergeSorted returns an iterator merging the key-value pairs of streams, each
+// of which must yield its keys in ascending order, into a single sequence in
+// ascending key order. Pairs with equal keys are yielded in the order of their
+// streams. Each stream is consumed once, lazily, with O(log k) work per pair
+// for k streams.
+func MergeSorted[K cmp.Ordered, V any](streams ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return mergeSorted(Ordered[K]{}.Less, streams)
+}
+
+// MergeSortedFunc is like MergeSorted, but orders keys with less.
+func MergeSortedFunc[K any, V any](less func(a, b K) bool, streams ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	if less == nil {
+		panic("fibheap: MergeSortedFunc expects a non-nil less function")
+	}
+	return mergeSorted(less, streams)
+}
+
+func mergeSorted[K any, V any](less func(a, b K) bool, streams []iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		// the heap holds the indexes of the streams which have a pending pair,
+		// ordered by the key of the pair and then by index
+		keys := make([]K, len(streams))
+		values := make([]V, len(streams))
+		h := NewHeapFunc[int, struct{}](func(a, b int) bool {
+			if less(keys[a], keys[b]) {
+				return true
+			}
+			return a < b && !less(keys[b], keys[a])
+		})
+		next := make([]func() (K, V, bool), len(streams))
+		for i, s := range streams {
+			var stop func()
+			next[i], stop = iter.Pull2(s)
+			defer stop()
+			var ok bool
+			if keys[i], values[i], ok = next[i](); ok {
+				h.Insert(i, struct{}{})
+			}
+		}
+		for h.min != nil {
+			i := h.ExtractMin().key
+			if !yield(keys[i], values[i]) {
+				return
+			}
+			var ok bool
+			if keys[i], values[i], ok = next[i](); ok {
+				h.Insert(i, struct{}{})
+			}
+		}
+	}
+}
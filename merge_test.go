@@ -0,0 +1,78 @@
+package fibheap
+
+import (
+	"iter"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// pairs returns an iterator over the keys with the value name.
+func pairs(name string, keys ...int) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for _, k := range keys {
+			if !yield(k, name) {
+				return
+			}
+		}
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	var keys []int
+	var values []string
+	for k, v := range MergeSorted(pairs("a", 1, 4, 4, 9), pairs("b"), pairs("c", 0, 4, 10), pairs("d", 2)) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if expected := []int{0, 1, 2, 4, 4, 4, 9, 10}; !slices.Equal(keys, expected) {
+		t.Fatalf("expected keys %v, got %v", expected, keys)
+	}
+	if expected := []string{"c", "a", "d", "a", "a", "c", "a", "c"}; !slices.Equal(values, expected) {
+		t.Fatalf("expected values %v, got %v", expected, values)
+	}
+
+	// stopping early stops the streams
+	n := 0
+	for range MergeSorted(pairs("a", 1, 2, 3), pairs("b", 1, 2, 3)) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	assert(t, n, 3)
+}
+
+func TestMergeSortedFunc(t *testing.T) {
+	greater := func(a, b int) bool { return a > b }
+	keys := slices.Collect(maps.Keys(maps.Collect(MergeSortedFunc(greater, pairs("a", 9, 5, 1), pairs("b", 8, 2)))))
+	slices.Sort(keys)
+	if expected := []int{1, 2, 5, 8, 9}; !slices.Equal(keys, expected) {
+		t.Fatalf("expected keys %v, got %v", expected, keys)
+	}
+	var merged []int
+	for k := range MergeSortedFunc(greater, pairs("a", 9, 5, 1), pairs("b", 8, 2)) {
+		merged = append(merged, k)
+	}
+	if expected := []int{9, 8, 5, 2, 1}; !slices.Equal(merged, expected) {
+		t.Fatalf("expected keys %v, got %v", expected, merged)
+	}
+}
+
+func BenchmarkMergeSorted(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	streams := make([]iter.Seq2[int, string], 64)
+	for i := range streams {
+		keys := make([]int, 1000)
+		for j := range keys {
+			keys[j] = r.Intn(1 << 20)
+		}
+		slices.Sort(keys)
+		streams[i] = pairs("", keys...)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range MergeSorted(streams...) {
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package fibheap
+
+import "cmp"
+
+// MinMaxElement is an element of a MinMaxHeap, holding a key and a value. It
+// serves as a handle to the element in the methods of the heap.
+type MinMaxElement[K any, V any] struct {
+	min *Element[K, *MinMaxElement[K, V]]
+	max *Element[K, *MinMaxElement[K, V]]
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element x.
+func (x *MinMaxElement[K, V]) Key() K {
+	return x.min.key
+}
+
+// Pair returns the key and the value of the element x.
+func (x *MinMaxElement[K, V]) Pair() Pair[K, V] {
+	return Pair[K, V]{Key: x.min.key, Value: x.Value}
+}
+
+// MinMaxHeap represents a double-ended priority queue of keys of an ordered
+// type, which fetches and extracts both the minimum and the maximum key. Every
+// element is held in a min-oriented and a max-oriented fibonacci heap at once,
+// which the MinMaxHeap keeps consistent: extracting from one end deletes the
+// element from the other heap. Fetching either end is Θ(1), inserting is Θ(1),
+// and extracting either end, deleting and changing a key are O(log n)
+// amortized. The zero value is an empty heap.
+type MinMaxHeap[K cmp.Ordered, V any] = MinMaxHeapOf[K, V, Ordered[K]]
+
+// MinMaxHeapFunc represents a double-ended priority queue whose keys are
+// ordered by a comparison function. It must be created by NewMinMaxHeapFunc.
+type MinMaxHeapFunc[K any, V any] = MinMaxHeapOf[K, V, Func[K]]
+
+// MinMaxHeapOf represents a double-ended priority queue whose keys are ordered
+// by O. It is used through its aliases MinMaxHeap and MinMaxHeapFunc.
+type MinMaxHeapOf[K any, V any, O Order[K]] struct {
+	min HeapOf[K, *MinMaxElement[K, V], O]
+	max HeapOf[K, *MinMaxElement[K, V], reverse[K, O]]
+}
+
+// NewMinMaxHeapFunc returns an empty double-ended priority queue which orders
+// keys with less. The function less must report whether a is strictly smaller
+// than b, and define a strict weak ordering.
+func NewMinMaxHeapFunc[K any, V any](less func(a, b K) bool) *MinMaxHeapFunc[K, V] {
+	if less == nil {
+		panic("fibheap: NewMinMaxHeapFunc expects a non-nil less function")
+	}
+	h := &MinMaxHeapFunc[K, V]{}
+	h.min.order = less
+	h.max.order.order = less
+	return h
+}
+
+// Size returns the number of elements in the heap h
+func (h *MinMaxHeapOf[K, V, O]) Size() int {
+	return h.min.Size()
+}
+
+// Contains reports whether the element x belongs to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) Contains(x *MinMaxElement[K, V]) bool {
+	return x != nil && h.min.Contains(x.min)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with amortized running time Θ(1)
+func (h *MinMaxHeapOf[K, V, O]) Insert(key K, value V) *MinMaxElement[K, V] {
+	x := &MinMaxElement[K, V]{Value: value}
+	x.min = h.min.Insert(key, x)
+	x.max = h.max.Insert(key, x)
+	return x
+}
+
+// Min fetches the element with the minimum key from the heap h with running
+// time Θ(1). It returns nil if h is empty.
+func (h *MinMaxHeapOf[K, V, O]) Min() *MinMaxElement[K, V] {
+	if e := h.min.Min(); e != nil {
+		return e.Value
+	}
+	return nil
+}
+
+// Max fetches the element with the maximum key from the heap h with running
+// time Θ(1). It returns nil if h is empty.
+func (h *MinMaxHeapOf[K, V, O]) Max() *MinMaxElement[K, V] {
+	if e := h.max.Min(); e != nil {
+		return e.Value
+	}
+	return nil
+}
+
+// ExtractMin fetches and removes the element with the minimum key from the heap
+// h with amortized running time O(log n). It returns nil if h is empty.
+func (h *MinMaxHeapOf[K, V, O]) ExtractMin() *MinMaxElement[K, V] {
+	e := h.min.ExtractMin()
+	if e == nil {
+		return nil
+	}
+	h.max.Delete(e.Value.max)
+	return e.Value
+}
+
+// ExtractMax fetches and removes the element with the maximum key from the heap
+// h with amortized running time O(log n). It returns nil if h is empty.
+func (h *MinMaxHeapOf[K, V, O]) ExtractMax() *MinMaxElement[K, V] {
+	e := h.max.ExtractMin()
+	if e == nil {
+		return nil
+	}
+	h.min.Delete(e.Value.min)
+	return e.Value
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// O(log n). If the new key is larger or equal than the key of x, Decreasing
+// does nothing. Decreasing panics if x does not belong to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) Decreasing(x *MinMaxElement[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	h.min.Decreasing(x.min, key)
+	// a smaller key is a larger one in the reverse order
+	h.max.Increasing(x.max, key)
+}
+
+// Increasing increases the key of the element x with amortized running time
+// O(log n). If the new key is smaller or equal than the key of x, Increasing
+// does nothing. Increasing panics if x does not belong to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) Increasing(x *MinMaxElement[K, V], key K) {
+	h.mustContain(x, "Increasing")
+	h.min.Increasing(x.min, key)
+	h.max.Decreasing(x.max, key)
+}
+
+// Update changes the key of the element x to key, decreasing or increasing it
+// as needed, with amortized running time O(log n). Update panics if x does not
+// belong to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) Update(x *MinMaxElement[K, V], key K) {
+	h.mustContain(x, "Update")
+	h.min.Update(x.min, key)
+	h.max.Update(x.max, key)
+}
+
+// Delete removes the element x from the heap h with amortized running time
+// O(log n). Delete panics if x does not belong to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) Delete(x *MinMaxElement[K, V]) {
+	h.mustContain(x, "Delete")
+	h.min.Delete(x.min)
+	h.max.Delete(x.max)
+}
+
+// Clear removes every element from the heap h in Θ(1).
+func (h *MinMaxHeapOf[K, V, O]) Clear() {
+	h.min.Clear()
+	h.max.Clear()
+}
+
+// mustContain panics with a message naming the method if the element x does not
+// belong to the heap h.
+func (h *MinMaxHeapOf[K, V, O]) mustContain(x *MinMaxElement[K, V], method string) {
+	if !h.Contains(x) {
+		panic("fibheap: " + method + " expects an element of the heap")
+	}
+}
@@ -0,0 +1,124 @@
+package fibheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMinMaxHeap(t *testing.T) {
+	h := &MinMaxHeap[int, string]{}
+	if h.Min() != nil || h.Max() != nil || h.ExtractMin() != nil || h.ExtractMax() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	elements := make([]*MinMaxElement[int, string], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, string(rune('a'+i)))
+	}
+	assert(t, h.Min().Key(), 0)
+	assert(t, h.Max().Key(), 9)
+	if x := h.ExtractMax(); x != elements[9] || x.Value != "j" || h.Contains(x) {
+		t.Fatal("expected ExtractMax to remove the element of key 9")
+	}
+	if x := h.ExtractMin(); x != elements[0] || h.Contains(x) {
+		t.Fatal("expected ExtractMin to remove the element of key 0")
+	}
+	assert(t, h.Size(), 8)
+
+	h.Decreasing(elements[5], -1)
+	h.Increasing(elements[4], 20)
+	assert(t, h.Min().Key(), -1)
+	assert(t, h.Max().Key(), 20)
+	h.Update(elements[4], 3)
+	h.Delete(elements[5])
+	assert(t, h.Max().Key(), 8)
+	for _, k := range []int{1, 2, 3, 3, 6, 7, 8} {
+		assert(t, h.ExtractMin().Key(), k)
+	}
+	checkMinMax(t, h)
+
+	g := &MinMaxHeap[int, string]{}
+	y := g.Insert(1, "")
+	for name, fn := range map[string]func(){
+		"Decreasing foreign": func() { h.Decreasing(y, 0) },
+		"Delete extracted":   func() { h.Delete(elements[0]) },
+		"Update nil":         func() { h.Update(nil, 0) },
+	} {
+		mustPanic(t, name, "fibheap: ", fn)
+	}
+}
+
+func TestMinMaxHeapFunc(t *testing.T) {
+	h := NewMinMaxHeapFunc[string, int](func(a, b string) bool { return len(a) < len(b) })
+	for _, s := range []string{"bb", "a", "dddd", "ccc"} {
+		h.Insert(s, len(s))
+	}
+	if h.Min().Key() != "a" || h.Max().Key() != "dddd" {
+		t.Fatalf("expected a and dddd, got %s and %s", h.Min().Key(), h.Max().Key())
+	}
+	h.Clear()
+	if h.Size() != 0 || h.Min() != nil || h.Max() != nil {
+		t.Fatal("expected Clear to empty the heap")
+	}
+	mustPanic(t, "NewMinMaxHeapFunc", "fibheap: ", func() { NewMinMaxHeapFunc[int, int](nil) })
+}
+
+func TestMinMaxHeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &MinMaxHeap[int, int]{}
+	var elements []*MinMaxElement[int, int]
+	var model []int
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(10); {
+		case op < 4 || len(model) == 0:
+			k := r.Intn(1000)
+			elements = append(elements, h.Insert(k, i))
+			model = append(model, k)
+		case op < 6:
+			j := r.Intn(len(model))
+			k := r.Intn(1000)
+			h.Update(elements[j], k)
+			model[j] = k
+		case op < 7:
+			j := r.Intn(len(model))
+			h.Delete(elements[j])
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		default:
+			var x *MinMaxElement[int, int]
+			var expected int
+			if op < 9 {
+				x, expected = h.ExtractMin(), slices.Min(model)
+			} else {
+				x, expected = h.ExtractMax(), slices.Max(model)
+			}
+			if x.Key() != expected {
+				t.Fatalf("operation %d: expected %d, got %d", i, expected, x.Key())
+			}
+			j := slices.Index(elements, x)
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		}
+		assert(t, h.Size(), len(model))
+		if i%100 == 0 {
+			checkMinMax(t, h)
+		}
+	}
+}
+
+// checkMinMax checks both heaps of h, and that they hold the same elements.
+func checkMinMax[K any, V any, O Order[K]](t *testing.T, h *MinMaxHeapOf[K, V, O]) {
+	t.Helper()
+	if err := h.min.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.max.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.max.Size(), h.min.Size())
+	for e := range h.min.Elements() {
+		if !h.max.Contains(e.Value.max) {
+			t.Fatal("an element of the min heap is missing from the max heap")
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package fibheap
+
+// SetOnMove sets fn to be called with every element leaving the heap h, so that
+// an external index mirroring the elements of h, such as a map from the values
+// to the elements, can stay in sync with it. The heap to is nil if x is
+// extracted, deleted, including lazily, or cleared, and it is the heap x
+// belongs to afterwards if x is moved by Meld, where g is the heap whose
+// callback is called, or by Union, where both h and g are. Elements moving
+// within the heap keep their handles, and suspended and pinned elements stay
+// in the heap, so neither is reported. Replacing the contents of h with
+// UnmarshalBinary or UnmarshalJSON is not reported either.
+//
+// The callback is called synchronously by the operation, after the element has
+// left h, and must not modify h or to. It adds a call per element to Clear,
+// Meld and Union, which then take O(n) time instead of Θ(1). A nil fn removes
+// the callback, which is not copied by Clone, nor kept by the heap returned by
+// Union.
+func (h *HeapOf[K, V, O]) SetOnMove(fn func(x *Element[K, V], to *HeapOf[K, V, O])) {
+	h.mustNotNil("SetOnMove")
+	h.onMove = fn
+}
+
+// extracted reports the element x, which has just left the heap h for no heap,
+// to the observer and the move callback of h.
+func (h *HeapOf[K, V, O]) extracted(x *Element[K, V]) {
+	if h.observer != nil {
+		h.observer.Extracted()
+	}
+	if h.onMove != nil {
+		h.onMove(x, nil)
+	}
+}
+
+// members returns the elements of the heap h, including suspended ones, if h
+// has a move callback, and nil otherwise.
+func (h *HeapOf[K, V, O]) members() []*Element[K, V] {
+	if h.onMove == nil {
+		return nil
+	}
+	list := make([]*Element[K, V], 0, h.elements+len(h.suspended))
+	h.each(func(e *Element[K, V]) bool {
+		list = append(list, e)
+		return true
+	})
+	for e := range h.suspended {
+		list = append(list, e)
+	}
+	return list
+}
+
+// moved calls the move callback fn with every element of list, which has moved
+// to the heap to.
+func moved[K any, V any, O Order[K]](fn func(x *Element[K, V], to *HeapOf[K, V, O]), list []*Element[K, V], to *HeapOf[K, V, O]) {
+	for _, e := range list {
+		fn(e, to)
+	}
+}
@@ -0,0 +1,90 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHeapOnMove(t *testing.T) {
+	h := &Heap[int, int]{}
+	g := &Heap[int, int]{}
+	// index maps the values to their elements and heaps, and is kept in sync
+	// with both heaps by the callbacks only
+	type entry struct {
+		x    *Element[int, int]
+		heap *Heap[int, int]
+	}
+	index := make(map[int]entry)
+	onMove := func(x *Element[int, int], to *Heap[int, int]) {
+		if to == nil {
+			delete(index, x.Value)
+			return
+		}
+		if !to.Contains(x) {
+			t.Fatal("a moved element does not belong to its new heap")
+		}
+		index[x.Value] = entry{x, to}
+	}
+	h.SetOnMove(onMove)
+	g.SetOnMove(onMove)
+	h.SetLazyDelete(true)
+
+	r := rand.New(rand.NewSource(1))
+	next := 0
+	for i := 0; i < 3000; i++ {
+		q := h
+		if r.Intn(2) == 0 {
+			q = g
+		}
+		switch op := r.Intn(20); {
+		case op < 8 || q.Size() == 0:
+			x := q.Insert(r.Intn(1000), next)
+			index[next] = entry{x, q}
+			next++
+		case op < 10:
+			q.ExtractMin()
+		case op < 13:
+			for _, e := range index {
+				if e.heap == q {
+					if r.Intn(3) == 0 {
+						q.Suspend(e.x)
+					}
+					q.Delete(e.x)
+					break
+				}
+			}
+		case op < 15:
+			q.Drain()
+		case op < 17:
+			if q == h {
+				h.Meld(g)
+			} else {
+				g.Meld(h)
+			}
+		case op < 18:
+			// the heap returned by Union has no callback until it is set
+			m := h.Union(g)
+			m.SetOnMove(onMove)
+			q.Meld(m)
+		default:
+			if r.Intn(10) == 0 {
+				q.Clear()
+			}
+		}
+		for v, e := range index {
+			if !e.heap.Contains(e.x) || e.x.Value != v {
+				t.Fatalf("operation %d: the index holds the value %d of another heap", i, v)
+			}
+		}
+		if len(index) != h.Size()+len(h.suspended)+g.Size()+len(g.suspended) {
+			t.Fatalf("operation %d: the index holds %d elements, the heaps %d", i, len(index), h.Size()+g.Size())
+		}
+	}
+
+	h.SetOnMove(nil)
+	h.ExtractMin()
+	mustPanic(t, "SetOnMove", "fibheap: SetOnMove on a nil heap", func() {
+		var h *Heap[int, int]
+		h.SetOnMove(nil)
+	})
+}
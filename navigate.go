@@ -0,0 +1,69 @@
+package fibheap
+
+import "iter"
+
+// The methods of this file expose the tree structure of a heap read-only, so
+// that tools such as visualizers and checkers can walk it. The structure
+// changes with every operation modifying the heap, which must not be modified
+// during a walk. Lazily deleted elements stay in the trees until a
+// consolidation removes them; Contains reports false for them.
+
+// Roots returns an iterator over the roots of the trees of the heap h, starting
+// from the minimum. Pinned and suspended elements are held apart from the trees
+// and are not yielded.
+func (h *HeapOf[K, V, O]) Roots() iter.Seq[*Element[K, V]] {
+	return func(yield func(*Element[K, V]) bool) {
+		if h != nil {
+			siblings(h.min, yield)
+		}
+	}
+}
+
+// Parent returns the parent of the element x in its tree, or nil if x is a
+// root, is held apart from the trees, or does not belong to a heap.
+func (x *Element[K, V]) Parent() *Element[K, V] {
+	if x == nil {
+		return nil
+	}
+	return x.p
+}
+
+// Children returns an iterator over the children of the element x.
+func (x *Element[K, V]) Children() iter.Seq[*Element[K, V]] {
+	return func(yield func(*Element[K, V]) bool) {
+		if x != nil {
+			siblings(x.children, yield)
+		}
+	}
+}
+
+// Degree returns the number of children of the element x.
+func (x *Element[K, V]) Degree() int {
+	if x == nil {
+		return 0
+	}
+	return x.getDegree()
+}
+
+// Marked reports whether the element x has lost a child since it became the
+// child of its parent, so that losing another one cuts it from its parent.
+// Roots are never marked.
+func (x *Element[K, V]) Marked() bool {
+	return x != nil && x.getMark()
+}
+
+// siblings calls yield for every element of the circular list starting at x,
+// until yield returns false.
+func siblings[K any, V any](x *Element[K, V], yield func(*Element[K, V]) bool) {
+	if x == nil {
+		return
+	}
+	for e := x; ; {
+		if !yield(e) {
+			return
+		}
+		if e = e.r; e == x {
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHeapNavigate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 500)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(1000), i)
+	}
+	h.ExtractMin()
+	for i := 0; i < 200; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+			h.Decreasing(x, x.Key()-r.Intn(100))
+		}
+	}
+	h.SetLazyDelete(true)
+	for i := 0; i < 20; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) && x != h.Min() {
+			h.Delete(x)
+		}
+	}
+	h.Pin(h.Min())
+
+	// walk the trees through the exported accessors only
+	visited, marked, deleted := 0, 0, 0
+	var visit func(x *Element[int, int], parent *Element[int, int])
+	visit = func(x *Element[int, int], parent *Element[int, int]) {
+		visited++
+		if x.Parent() != parent {
+			t.Fatal("the parent of a child should be the element it was reached from")
+		}
+		if parent != nil && x.Key() < parent.Key() {
+			t.Fatal("a child should not be smaller than its parent")
+		}
+		if x.Marked() {
+			if parent == nil {
+				t.Fatal("a root should not be marked")
+			}
+			marked++
+		}
+		if !h.Contains(x) {
+			deleted++
+		}
+		degree := 0
+		for c := range x.Children() {
+			degree++
+			visit(c, x)
+		}
+		assert(t, x.Degree(), degree)
+	}
+	roots := 0
+	for x := range h.Roots() {
+		if roots == 0 && x != h.Min() {
+			t.Fatal("the first root should be the minimum")
+		}
+		roots++
+		visit(x, nil)
+	}
+	assert(t, roots, h.Stats().Roots)
+	assert(t, deleted, h.Tombstones())
+	assert(t, visited, h.Size()-h.Pinned()+h.Tombstones())
+	if marked == 0 {
+		t.Fatal("expected some marked elements")
+	}
+
+	var x *Element[int, int]
+	if x.Parent() != nil || x.Degree() != 0 || x.Marked() {
+		t.Fatal("a nil element should have no structure")
+	}
+	for range x.Children() {
+		t.Fatal("a nil element should have no children")
+	}
+	var nilHeap *Heap[int, int]
+	for range nilHeap.Roots() {
+		t.Fatal("a nil heap should have no roots")
+	}
+}
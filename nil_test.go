@@ -0,0 +1,123 @@
+package fibheap
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// mustPanic calls f and fails unless it panics with a message starting with
+// prefix.
+func mustPanic(t *testing.T, name, prefix string, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		msg, _ := recover().(string)
+		if !strings.HasPrefix(msg, prefix) {
+			t.Errorf("%s: expected a panic with %q, got %q", name, prefix, msg)
+		}
+	}()
+	f()
+}
+
+func TestHeapNil(t *testing.T) {
+	for name, h := range map[string]*Heap[int, any]{"nil": nil, "empty": {}} {
+		t.Run(name, func(t *testing.T) {
+			all := func(int, any) bool { return true }
+			if h.Size() != 0 || h.Min() != nil || h.Contains(nil) || h.Contains(&Element[int, any]{}) {
+				t.Fatal("the heap should be empty")
+			}
+			if h.ExtractMin() != nil || h.ExtractMinIf(all) != nil || h.ExtractUntil(0) != nil ||
+				h.ExtractWhile(all) != nil || h.PopN(1) != nil || h.Drain() != nil || h.MinN(1) != nil ||
+				h.ExtractRandom(nil) != nil {
+				t.Fatal("extracting from the heap should return nil")
+			}
+			if h.Suspended() != 0 || h.Pinned() != 0 || h.Tombstones() != 0 || h.Stable() || h.LazyDelete() {
+				t.Fatal("the heap should have no held element and no option")
+			}
+			for range h.All() {
+				t.Fatal("All should yield nothing")
+			}
+			for range h.Descend() {
+				t.Fatal("Descend should yield nothing")
+			}
+			for range h.Sorted() {
+				t.Fatal("Sorted should yield nothing")
+			}
+			if s := h.Stats(); s.Size != 0 || s.Roots != 0 {
+				t.Fatalf("unexpected stats %+v", s)
+			}
+			if err := h.Check(); err != nil {
+				t.Fatal(err)
+			}
+			if err := h.Dot(io.Discard); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := h.MarshalJSON(); err != nil || string(data) != "[]" {
+				t.Fatalf("expected an empty JSON list, got %s", data)
+			}
+			data, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := &Heap[int, any]{}
+			if err := g.UnmarshalBinary(data); err != nil || g.Size() != 0 {
+				t.Fatal("the encoding should decode to an empty heap")
+			}
+			h.Clear()
+
+			_, err = h.TryExtractMin()
+			checkErr(t, err, ErrEmptyHeap)
+			checkErr(t, h.TryDecreasing(nil, 0), ErrElementNotInHeap)
+			checkErr(t, h.TryIncreasing(nil, 0), ErrElementNotInHeap)
+			checkErr(t, h.TryRemove(nil, 0), ErrElementNotInHeap)
+			checkErr(t, h.TryDelete(nil), ErrElementNotInHeap)
+			checkErr(t, h.TryPin(nil), ErrElementNotInHeap)
+			checkErr(t, h.TryUnpin(nil), ErrElementNotInHeap)
+			checkErr(t, h.TrySuspend(nil), ErrElementNotInHeap)
+			checkErr(t, h.TryResume(nil), ErrElementNotInHeap)
+
+			for method, f := range map[string]func(){
+				"Decreasing": func() { h.Decreasing(nil, 0) },
+				"Increasing": func() { h.Increasing(nil, 0) },
+				"Update":     func() { h.Update(nil, 0) },
+				"Remove":     func() { h.Remove(nil, 0) },
+				"Delete":     func() { h.Delete(nil) },
+				"Pin":        func() { h.Pin(nil) },
+				"Unpin":      func() { h.Unpin(nil) },
+				"Suspend":    func() { h.Suspend(nil) },
+				"Resume":     func() { h.Resume(nil) },
+			} {
+				mustPanic(t, method, "fibheap: "+method+" expects an element", f)
+			}
+			mustPanic(t, "Meld", "fibheap: Meld expects non-nil heap", func() { h.Meld(nil) })
+		})
+	}
+
+	var h *Heap[int, any]
+	for method, f := range map[string]func(){
+		"Insert":          func() { h.Insert(0, nil) },
+		"InsertAll":       func() { h.InsertAll(nil) },
+		"InsertBatch":     func() { h.InsertBatch(nil) },
+		"InsertElement":   func() { h.InsertElement(&Element[int, any]{}) },
+		"DeleteWhere":     func() { h.DeleteWhere(nil) },
+		"ReplaceMin":      func() { h.ReplaceMin(0, nil) },
+		"SetWorkBudget":   func() { h.SetWorkBudget(0, nil) },
+		"Recycle":         func() { h.Recycle(&Element[int, any]{}) },
+		"SetStable":       func() { h.SetStable(true) },
+		"SetLazyDelete":   func() { h.SetLazyDelete(true) },
+		"SetObserver":     func() { h.SetObserver(nil) },
+		"OnSizeAbove":     func() { h.OnSizeAbove(0, nil) },
+		"UnmarshalBinary": func() { h.UnmarshalBinary(nil) },
+		"UnmarshalJSON":   func() { h.UnmarshalJSON([]byte("[]")) },
+	} {
+		mustPanic(t, method, "fibheap: "+method+" on a nil heap", f)
+	}
+	if h.Clone() != nil {
+		t.Fatal("the clone of a nil heap should be nil")
+	}
+	mustPanic(t, "Recycle", "fibheap: Recycle expects", func() { (&Heap[int, any]{}).Recycle(nil) })
+	mustPanic(t, "Reset", "fibheap: Reset expects", func() { (*Element[int, any])(nil).Reset() })
+	mustPanic(t, "Init", "fibheap: Init expects", func() { (*Element[int, any])(nil).Init(0, nil) })
+	mustPanic(t, "InsertElement", "fibheap: InsertElement expects", func() { (&Heap[int, any]{}).InsertElement(nil) })
+}
@@ -0,0 +1,35 @@
+package fibheap
+
+import (
+	"time"
+)
+
+// Observer receives the operations of a heap, for example to export operation
+// counts and consolidation times as metrics. The methods are called
+// synchronously by the operations of the heap, so they should be cheap, and
+// must not modify the heap.
+type Observer interface {
+	// Inserted is called for every element inserted into the heap.
+	Inserted()
+	// Extracted is called for every element leaving the heap, whether it is
+	// extracted as the minimum or deleted. Suspended and pinned elements stay
+	// in the heap, so Suspend, Pin and their reverse are not reported, and
+	// deleting such an element is reported once.
+	Extracted()
+	// Consolidated is called after the root list has been consolidated, with
+	// the number of roots before the consolidation, the number of links it
+	// made, and the time it took. Long root lists make expensive
+	// consolidations.
+	Consolidated(roots, links int, elapsed time.Duration)
+	// Cut is called for every element cut from its parent and moved to the
+	// root list, by Decreasing or by the cascading cuts following it.
+	Cut()
+}
+
+// SetObserver sets the observer of the operations of the heap h. A nil o
+// removes the observer. The observer is not copied by Clone, nor kept by the
+// heap returned by Union.
+func (h *HeapOf[K, V, O]) SetObserver(o Observer) {
+	h.mustNotNil("SetObserver")
+	h.observer = o
+}
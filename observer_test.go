@@ -0,0 +1,103 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// recorder is an Observer counting the operations of a heap.
+type recorder struct {
+	inserts, extracts, cuts, consolidations int
+	// roots after the last consolidation
+	roots int
+}
+
+func (r *recorder) Inserted()  { r.inserts++ }
+func (r *recorder) Extracted() { r.extracts++ }
+func (r *recorder) Cut()       { r.cuts++ }
+
+func (r *recorder) Consolidated(roots, links int, elapsed time.Duration) {
+	r.consolidations++
+	r.roots = roots - links
+}
+
+func TestHeapObserver(t *testing.T) {
+	rec := &recorder{}
+	h := &Heap[int, int]{}
+	h.SetObserver(rec)
+	h.InsertAll([]Pair[int, int]{{Key: 100}, {Key: 101}})
+	r := rand.New(rand.NewSource(1))
+	var elements []*Element[int, int]
+	for i := 0; i < 1000; i++ {
+		switch op := r.Intn(4); {
+		case op < 2 || len(elements) == 0:
+			elements = append(elements, h.Insert(r.Intn(1000), i))
+		case op < 3:
+			x := elements[r.Intn(len(elements))]
+			if h.Contains(x) {
+				h.Decreasing(x, x.Key()-r.Intn(100))
+			}
+		default:
+			h.ExtractMin()
+			if n := h.Stats().Roots; n != rec.roots {
+				t.Fatalf("expected %d roots after consolidation, got %d", rec.roots, n)
+			}
+		}
+	}
+	assert(t, rec.inserts-rec.extracts, h.Size())
+	if rec.cuts == 0 || rec.consolidations == 0 {
+		t.Fatalf("expected cuts and consolidations, got %+v", rec)
+	}
+
+	h.SetObserver(nil)
+	h.Insert(0, 0)
+	assert(t, rec.inserts-rec.extracts, h.Size()-1)
+}
+
+func TestHeapObserverHeld(t *testing.T) {
+	rec := &recorder{}
+	h := &Heap[int, int]{}
+	h.SetObserver(rec)
+	r := rand.New(rand.NewSource(1))
+	var elements []*Element[int, int]
+	for i := 0; i < 2000; i++ {
+		switch op := r.Intn(8); {
+		case op < 3 || len(elements) == 0:
+			elements = append(elements, h.Insert(r.Intn(1000), i))
+		case op == 3:
+			h.ExtractMin()
+		default:
+			x := elements[r.Intn(len(elements))]
+			if !h.Contains(x) {
+				continue
+			}
+			switch {
+			case x.flags&suspended != 0:
+				if op == 4 {
+					h.Delete(x)
+				} else {
+					h.Resume(x)
+				}
+			case x.flags&pinned != 0:
+				if op == 4 {
+					h.Delete(x)
+				} else {
+					h.Unpin(x)
+				}
+			case op == 4:
+				h.Delete(x)
+			case op == 5:
+				h.Pin(x)
+			default:
+				h.Suspend(x)
+			}
+		}
+		if rec.inserts-rec.extracts != h.Size()+h.Suspended() {
+			t.Fatalf("step %d: %d inserts and %d extracts for size %d and %d suspended",
+				i, rec.inserts, rec.extracts, h.Size(), h.Suspended())
+		}
+	}
+	h.Drain()
+	assert(t, rec.inserts-rec.extracts, h.Size()+h.Suspended())
+}
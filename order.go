@@ -0,0 +1,76 @@
+package fibheap
+
+import (
+	"cmp"
+)
+
+// Order is the constraint satisfied by the orderings of the keys of a heap. The
+// ordering of a heap is a type parameter, so that heaps of ordered keys can
+// be used without being created by a constructor, while heaps ordered by a
+// comparison function must be created with it.
+type Order[K any] interface {
+	// Less reports whether a is strictly smaller than b.
+	Less(a, b K) bool
+}
+
+// Ordered orders the keys of an ordered type as cmp.Compare does. It is the
+// ordering of Heap, MaxHeap, BoundedHeap and SyncHeap. For floating-point keys,
+// a NaN is smaller than any other key and equal to any other NaN, so that NaN
+// keys do not break the ordering of the heap; the < operator reports false for
+// any comparison with a NaN instead.
+type Ordered[K cmp.Ordered] struct{}
+
+// Less reports whether a is smaller than b, as cmp.Less.
+func (Ordered[K]) Less(a, b K) bool {
+	return cmp.Less(a, b)
+}
+
+// Func orders keys with a comparison function. It is the ordering of the heaps
+// created by NewHeapFunc and the other Func constructors.
+type Func[K any] func(a, b K) bool
+
+// Less reports whether f(a, b).
+func (f Func[K]) Less(a, b K) bool {
+	return f(a, b)
+}
+
+// CompareFunc returns the less function of the three-way comparison function
+// compare, which returns a negative number when a < b, a positive number when
+// a > b and zero otherwise, as cmp.Compare and strings.Compare do. It adapts such
+// functions to NewHeapFunc and the other constructors taking a less function.
+func CompareFunc[K any](compare func(a, b K) int) func(a, b K) bool {
+	if compare == nil {
+		panic("fibheap: CompareFunc expects a non-nil compare function")
+	}
+	return func(a, b K) bool {
+		return compare(a, b) < 0
+	}
+}
+
+// valid reports whether f is set, since the zero value of a heap ordered by Func
+// lacks its comparison function.
+func (f Func[K]) valid() bool {
+	return f != nil
+}
+
+// reverse reverses the ordering O, for max-oriented heaps.
+type reverse[K any, O Order[K]] struct {
+	order O
+}
+
+// Less reports whether b is strictly smaller than a in the ordering O.
+func (r reverse[K, O]) Less(a, b K) bool {
+	return r.order.Less(b, a)
+}
+
+func (r reverse[K, O]) valid() bool {
+	return validOrder[K](r.order)
+}
+
+// validOrder reports whether the ordering o can compare keys.
+func validOrder[K any, O Order[K]](o O) bool {
+	if v, ok := any(o).(interface{ valid() bool }); ok {
+		return v.valid()
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package fibheap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type deadline struct {
+	at       time.Time
+	priority int
+}
+
+func TestNewHeapFunc(t *testing.T) {
+	now := time.Now()
+	h := NewHeapFunc[deadline, string](func(a, b deadline) bool {
+		if !a.at.Equal(b.at) {
+			return a.at.Before(b.at)
+		}
+		return a.priority > b.priority
+	})
+	h.Insert(deadline{now.Add(time.Second), 0}, "later")
+	low := h.Insert(deadline{now, 1}, "low")
+	h.Insert(deadline{now, 2}, "high")
+	h.Insert(deadline{now.Add(time.Minute), 0}, "latest")
+	assert(t, h.Size(), 4)
+
+	if x := h.ExtractMin(); x.Value != "high" {
+		t.Fatalf("expected high, got %s", x.Value)
+	}
+	h.Decreasing(low, deadline{now.Add(-time.Second), 0})
+	for _, expected := range []string{"low", "later", "latest"} {
+		if x := h.ExtractMin(); x.Value != expected {
+			t.Fatalf("expected %s, got %s", expected, x.Value)
+		}
+	}
+}
+
+func TestNewHeapFuncUnion(t *testing.T) {
+	greater := func(a, b int) bool { return a > b }
+	h := NewHeapFunc[int, any](greater)
+	g := NewHeapFunc[int, any](greater)
+	for i := 0; i < 10; i++ {
+		h.Insert(i, nil)
+		g.Insert(i+10, nil)
+	}
+	k := h.Union(g)
+	for i := 19; i >= 0; i-- {
+		assert(t, k.ExtractMin().Key(), i)
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	type priority uint8
+	h := &Heap[priority, any]{}
+	h.Insert(200, nil)
+	h.Insert(3, nil)
+	h.Insert(100, nil)
+	if x := h.ExtractMin(); x.Key() != 3 {
+		t.Fatalf("expected 3, got %d", x.Key())
+	}
+
+	s := &Heap[string, any]{}
+	s.Insert("b", nil)
+	s.Insert("a", nil)
+	if x := s.ExtractMin(); x.Key() != "a" {
+		t.Fatalf("expected a, got %s", x.Key())
+	}
+
+	d := &Heap[time.Duration, any]{}
+	d.Insert(time.Second, nil)
+	d.Insert(-time.Second, nil)
+	if x := d.ExtractMin(); x.Key() != -time.Second {
+		t.Fatalf("expected -1s, got %v", x.Key())
+	}
+
+}
+
+func TestHeapFuncZero(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"HeapFunc":    func() { (&HeapFunc[deadline, any]{}).Insert(deadline{}, nil) },
+		"MaxHeapFunc": func() { (&MaxHeapFunc[deadline, any]{}).Insert(deadline{}, nil) },
+		"InsertAll":   func() { (&HeapFunc[deadline, any]{}).InsertAll([]Pair[deadline, any]{{}}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestCompareFunc(t *testing.T) {
+	h := NewHeapFunc[string, any](CompareFunc(strings.Compare))
+	for _, k := range []string{"b", "c", "a"} {
+		h.Insert(k, nil)
+	}
+	for _, expected := range []string{"a", "b", "c"} {
+		if k := h.ExtractMin().Key(); k != expected {
+			t.Fatalf("expected %s, got %s", expected, k)
+		}
+	}
+}
+
+func TestOrderedNaN(t *testing.T) {
+	var o Ordered[float64]
+	nan := math.NaN()
+	if !o.Less(nan, math.Inf(-1)) || o.Less(0, nan) || o.Less(nan, nan) {
+		t.Fatal("a NaN should be smaller than any other key and equal to NaN")
+	}
+}
+
+func TestHeapNaN(t *testing.T) {
+	nan := math.NaN()
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[float64, any]{}
+	var elements []*Element[float64, any]
+	for i := 0; i < 500; i++ {
+		k := float64(r.Intn(100))
+		if r.Intn(10) == 0 {
+			k = nan
+		}
+		elements = append(elements, h.Insert(k, nil))
+	}
+	h.ExtractMin()
+	for i := 0; i < 200; i++ {
+		x := elements[r.Intn(len(elements))]
+		if !h.Contains(x) {
+			continue
+		}
+		switch r.Intn(3) {
+		case 0:
+			h.Decreasing(x, nan)
+		case 1:
+			h.Decreasing(x, x.Key()-float64(r.Intn(10)))
+		case 2:
+			h.Increasing(x, float64(r.Intn(200)))
+		}
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	var keys []float64
+	for x := range h.Elements() {
+		keys = append(keys, x.Key())
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if x := h.ExtractMin().Key(); x != k && !(math.IsNaN(x) && math.IsNaN(k)) {
+			t.Fatalf("expected %v, got %v", k, x)
+		}
+	}
+
+	// a NaN key is not decreased any further, but increasing it is
+	x := h.Insert(nan, nil)
+	checkErr(t, h.TryDecreasing(x, 0), ErrKeyNotSmaller)
+	checkErr(t, h.TryDecreasing(x, nan), ErrKeyNotSmaller)
+	checkErr(t, h.TryIncreasing(x, 0), nil)
+	checkErr(t, h.TryDecreasing(x, nan), nil)
+	if !math.IsNaN(h.Min().Key()) {
+		t.Fatal("expected the NaN key to be the minimum")
+	}
+}
@@ -0,0 +1,280 @@
+// Package otelfibheap instruments a fibonacci heap with OpenTelemetry. Every
+// operation is recorded as a span and counted by metrics, and the time an
+// element waits in the heap from Insert to its extraction is recorded both as a
+// histogram and as an attribute of the extracting span, so that queueing delay
+// shows up in distributed traces.
+//
+// The metrics are recorded by a fibheap.Observer set on the wrapped heap, so
+// they count the same operations as any other observer. The package is a
+// module of its own, so that the users of fibheap do not depend on
+// OpenTelemetry.
+package otelfibheap
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/ksw2000/go-fibheap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used by the tracer and the meter.
+const ScopeName = "github.com/ksw2000/go-fibheap/otelfibheap"
+
+// Attribute keys recorded on spans.
+const (
+	// SizeKey is the number of elements in the heap after the operation.
+	SizeKey = attribute.Key("fibheap.size")
+	// WaitKey is the time in seconds the extracted element waited in the heap.
+	WaitKey = attribute.Key("fibheap.wait")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. The global tracer provider is
+// used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider. The global meter provider is used
+// by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Heap is an instrumented fibonacci heap. Like the heap it wraps, it is not safe
+// for concurrent use. The wrapped heap is owned by the Heap and is not exposed,
+// so that every element leaving it is seen by the instrumentation.
+type Heap[K cmp.Ordered, V any] struct {
+	heap     fibheap.Heap[K, V]
+	enqueued map[*fibheap.Element[K, V]]time.Time
+	tracer   trace.Tracer
+	wait     metric.Float64Histogram
+	observer observer
+}
+
+// observer records the operations of the wrapped heap into the metrics, within
+// the context of the operation in progress.
+type observer struct {
+	ctx      context.Context
+	inserts  metric.Int64Counter
+	extracts metric.Int64Counter
+	size     metric.Int64UpDownCounter
+	cuts     metric.Int64Counter
+	links    metric.Int64Counter
+}
+
+func (o *observer) Inserted() {
+	o.inserts.Add(o.ctx, 1)
+	o.size.Add(o.ctx, 1)
+}
+
+func (o *observer) Extracted() {
+	o.extracts.Add(o.ctx, 1)
+	o.size.Add(o.ctx, -1)
+}
+
+func (o *observer) Cut() {
+	o.cuts.Add(o.ctx, 1)
+}
+
+func (o *observer) Consolidated(roots, links int, elapsed time.Duration) {
+	o.links.Add(o.ctx, int64(links))
+}
+
+// New returns an empty instrumented heap.
+func New[K cmp.Ordered, V any](opts ...Option) (*Heap[K, V], error) {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	h := &Heap[K, V]{
+		enqueued: make(map[*fibheap.Element[K, V]]time.Time),
+		tracer:   c.tracerProvider.Tracer(ScopeName),
+	}
+	o := &h.observer
+	meter := c.meterProvider.Meter(ScopeName)
+	var err error
+	if o.inserts, err = meter.Int64Counter("fibheap.inserts",
+		metric.WithDescription("Number of elements inserted into the heap.")); err != nil {
+		return nil, err
+	}
+	if o.extracts, err = meter.Int64Counter("fibheap.extracts",
+		metric.WithDescription("Number of elements extracted or deleted from the heap.")); err != nil {
+		return nil, err
+	}
+	if o.size, err = meter.Int64UpDownCounter("fibheap.size",
+		metric.WithDescription("Number of elements in the heap, including pinned and suspended ones.")); err != nil {
+		return nil, err
+	}
+	if o.cuts, err = meter.Int64Counter("fibheap.cuts",
+		metric.WithDescription("Number of elements cut from their parent.")); err != nil {
+		return nil, err
+	}
+	if o.links, err = meter.Int64Counter("fibheap.links",
+		metric.WithDescription("Number of trees linked by consolidations.")); err != nil {
+		return nil, err
+	}
+	if h.wait, err = meter.Float64Histogram("fibheap.wait",
+		metric.WithDescription("Time elements waited in the heap before being extracted or deleted."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	h.heap.SetObserver(o)
+	return h, nil
+}
+
+// Size returns the number of elements in the heap h
+func (h *Heap[K, V]) Size() int {
+	return h.heap.Size()
+}
+
+// Min fetches the minimum key from the heap h
+func (h *Heap[K, V]) Min() *fibheap.Element[K, V] {
+	return h.heap.Min()
+}
+
+// Contains reports whether the element x belongs to the heap h.
+func (h *Heap[K, V]) Contains(x *fibheap.Element[K, V]) bool {
+	return h.heap.Contains(x)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element.
+func (h *Heap[K, V]) Insert(ctx context.Context, key K, value V) *fibheap.Element[K, V] {
+	ctx, span := h.start(ctx, "fibheap.Insert")
+	defer h.end(span)
+
+	x := h.heap.Insert(key, value)
+	h.enqueued[x] = time.Now()
+	return x
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h. The span
+// records how long the extracted element waited in the heap.
+func (h *Heap[K, V]) ExtractMin(ctx context.Context) *fibheap.Element[K, V] {
+	ctx, span := h.start(ctx, "fibheap.ExtractMin")
+	defer h.end(span)
+
+	x := h.heap.ExtractMin()
+	if x != nil {
+		h.waited(ctx, span, x)
+	}
+	return x
+}
+
+// Decreasing decreases the key of the element x to key, as Heap.Decreasing.
+func (h *Heap[K, V]) Decreasing(ctx context.Context, x *fibheap.Element[K, V], key K) {
+	_, span := h.start(ctx, "fibheap.Decreasing")
+	defer h.end(span)
+
+	h.heap.Decreasing(x, key)
+}
+
+// Update changes the key of the element x to key in either direction, as
+// Heap.Update.
+func (h *Heap[K, V]) Update(ctx context.Context, x *fibheap.Element[K, V], key K) *fibheap.Element[K, V] {
+	_, span := h.start(ctx, "fibheap.Update")
+	defer h.end(span)
+
+	return h.heap.Update(x, key)
+}
+
+// Remove removes the element x by given a key minimumKey which is smaller than
+// any key in the heap h, as Heap.Remove.
+func (h *Heap[K, V]) Remove(ctx context.Context, x *fibheap.Element[K, V], minimumKey K) {
+	ctx, span := h.start(ctx, "fibheap.Remove")
+	defer h.end(span)
+
+	h.heap.Remove(x, minimumKey)
+	h.waited(ctx, span, x)
+}
+
+// Delete removes the element x from the heap h, as Heap.Delete. The span
+// records how long the deleted element waited in the heap.
+func (h *Heap[K, V]) Delete(ctx context.Context, x *fibheap.Element[K, V]) {
+	ctx, span := h.start(ctx, "fibheap.Delete")
+	defer h.end(span)
+
+	h.heap.Delete(x)
+	h.waited(ctx, span, x)
+}
+
+// Pin holds the element x back from extraction until Unpin is called, as
+// Heap.Pin.
+func (h *Heap[K, V]) Pin(ctx context.Context, x *fibheap.Element[K, V]) {
+	_, span := h.start(ctx, "fibheap.Pin")
+	defer h.end(span)
+
+	h.heap.Pin(x)
+}
+
+// Unpin releases the element x pinned by Pin, as Heap.Unpin.
+func (h *Heap[K, V]) Unpin(ctx context.Context, x *fibheap.Element[K, V]) {
+	_, span := h.start(ctx, "fibheap.Unpin")
+	defer h.end(span)
+
+	h.heap.Unpin(x)
+}
+
+// Suspend removes the element x from the heap h until Resume is called, as
+// Heap.Suspend. The time a suspended element waits is still recorded when it is
+// extracted.
+func (h *Heap[K, V]) Suspend(ctx context.Context, x *fibheap.Element[K, V]) {
+	_, span := h.start(ctx, "fibheap.Suspend")
+	defer h.end(span)
+
+	h.heap.Suspend(x)
+}
+
+// Resume reinserts the element x suspended by Suspend, as Heap.Resume.
+func (h *Heap[K, V]) Resume(ctx context.Context, x *fibheap.Element[K, V]) {
+	_, span := h.start(ctx, "fibheap.Resume")
+	defer h.end(span)
+
+	h.heap.Resume(x)
+}
+
+// start starts the span of an operation, and makes its context the one of the
+// metrics recorded by the observer.
+func (h *Heap[K, V]) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	ctx, span := h.tracer.Start(ctx, name)
+	h.observer.ctx = ctx
+	return ctx, span
+}
+
+// end records the size of the heap h on the span and ends it.
+func (h *Heap[K, V]) end(span trace.Span) {
+	span.SetAttributes(SizeKey.Int(h.heap.Size()))
+	span.End()
+	h.observer.ctx = nil
+}
+
+// waited records how long the element x, which left the heap, waited in it.
+func (h *Heap[K, V]) waited(ctx context.Context, span trace.Span, x *fibheap.Element[K, V]) {
+	if at, ok := h.enqueued[x]; ok {
+		delete(h.enqueued, x)
+		wait := time.Since(at).Seconds()
+		h.wait.Record(ctx, wait)
+		span.SetAttributes(WaitKey.Float64(wait))
+	}
+}
@@ -0,0 +1,135 @@
+package otelfibheap
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHeap(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	h, err := New[int, string](
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Insert(ctx, 3, "three")
+	x := h.Insert(ctx, 2, "two")
+	h.Insert(ctx, 1, "one")
+	h.Decreasing(ctx, x, 0)
+	h.Remove(ctx, x, -1)
+	if m := h.ExtractMin(ctx); m == nil || m.Value != "one" {
+		t.Fatal("ExtractMin should extract the element one")
+	}
+	if h.Size() != 1 || h.Min().Value != "three" {
+		t.Fatal("the element three should be left in the heap")
+	}
+
+	names := []string{
+		"fibheap.Insert", "fibheap.Insert", "fibheap.Insert",
+		"fibheap.Decreasing", "fibheap.Remove", "fibheap.ExtractMin",
+	}
+	ended := spans.Ended()
+	if len(ended) != len(names) {
+		t.Fatalf("expected %d spans, got %d", len(names), len(ended))
+	}
+	for i, span := range ended {
+		if span.Name() != names[i] {
+			t.Fatalf("expected span %s, got %s", names[i], span.Name())
+		}
+	}
+	if !hasAttribute(ended[5].Attributes(), WaitKey) {
+		t.Fatal("ExtractMin span should record the wait time")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	sums := map[string]int64{}
+	var waits uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					waits += dp.Count
+				}
+			}
+		}
+	}
+	if sums["fibheap.inserts"] != 3 || sums["fibheap.extracts"] != 2 || sums["fibheap.size"] != 1 {
+		t.Fatalf("unexpected metrics %v", sums)
+	}
+	if waits != 2 {
+		t.Fatalf("expected 2 recorded waits, got %d", waits)
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHeapHeld(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	h, err := New[int, string](WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := h.Insert(ctx, 1, "a")
+	b := h.Insert(ctx, 2, "b")
+	c := h.Insert(ctx, 3, "c")
+	d := h.Insert(ctx, 4, "d")
+	h.Pin(ctx, a)
+	h.Suspend(ctx, b)
+	h.Update(ctx, c, 5)
+	if m := h.ExtractMin(ctx); m != d {
+		t.Fatal("ExtractMin should skip the pinned and suspended elements")
+	}
+	h.Delete(ctx, b)
+	h.Unpin(ctx, a)
+	h.Delete(ctx, a)
+	h.Delete(ctx, c)
+	if h.Size() != 0 || len(h.enqueued) != 0 {
+		t.Fatalf("expected an empty heap, got %d elements and %d enqueued", h.Size(), len(h.enqueued))
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	sums := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range data.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	if sums["fibheap.inserts"] != 4 || sums["fibheap.extracts"] != 4 || sums["fibheap.size"] != 0 {
+		t.Fatalf("unexpected metrics %v", sums)
+	}
+}
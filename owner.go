@@ -0,0 +1,69 @@
+package fibheap
+
+// owner identifies the heap an element belongs to. Every element of a heap
+// points to the owner of the heap. Union links the owners of the two heaps
+// instead of rewriting the elements, so the owner of an element is found by
+// following next to the end, as in a disjoint-set forest. Owners are linked by
+// rank, so that the path from any owner is O(log n) long without being
+// compressed. Clear marks the last owner of a heap as cleared instead of
+// visiting the elements, so that they belong to no heap afterwards.
+type owner struct {
+	next    *owner
+	rank    int
+	cleared bool
+}
+
+// find returns the last owner reachable from o. It does not modify the owners,
+// so that read-only methods such as Contains do not write to the heap; methods
+// modifying an element shorten its path instead, see mustContain.
+func (o *owner) find() *owner {
+	for o.next != nil {
+		o = o.next
+	}
+	return o
+}
+
+// union links the owners o and p, which are the last ones of their paths, and
+// returns the one left last.
+func (o *owner) union(p *owner) *owner {
+	if o.rank < p.rank {
+		o, p = p, o
+	}
+	p.next = o
+	if o.rank == p.rank {
+		o.rank++
+	}
+	return o
+}
+
+// own returns the owner of the heap h, creating it on first use.
+func (h *HeapOf[K, V, O]) own() *owner {
+	if h.owner == nil {
+		h.owner = &owner{}
+	}
+	return h.owner
+}
+
+// Contains reports whether the element x belongs to the heap h, that is, x was
+// inserted into h, or into a heap unioned into h, and has not been extracted or
+// deleted since. Suspended and pinned elements still belong to their heap. A nil
+// heap contains no element.
+func (h *HeapOf[K, V, O]) Contains(x *Element[K, V]) bool {
+	return h != nil && x != nil && x.owner != nil && h.owner != nil && x.owner.find() == h.owner
+}
+
+// mustContain panics with a message naming the method if the element x does not
+// belong to the heap h. Otherwise, x is pointed directly to the owner of h, so
+// that later lookups of x are Θ(1).
+func (h *HeapOf[K, V, O]) mustContain(x *Element[K, V], method string) {
+	if !h.Contains(x) {
+		panic("fibheap: " + method + " expects an element of the heap")
+	}
+	x.owner = h.owner
+}
+
+// detached reports whether the element x belongs to no heap, because it was
+// extracted or deleted, or its heap was cleared.
+func (x *Element[K, V]) detached() bool {
+	return x.owner == nil || x.owner.find().cleared
+}
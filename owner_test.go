@@ -0,0 +1,129 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func checkContains(t *testing.T, h *Heap[int, any], x *Element[int, any], expected bool) {
+	t.Helper()
+	if h.Contains(x) != expected {
+		t.Errorf("Contains returned %v, expected %v", !expected, expected)
+	}
+}
+
+func TestHeapContains(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	y := g.Insert(2, nil)
+	checkContains(t, h, x, true)
+	checkContains(t, h, y, false)
+	checkContains(t, h, nil, false)
+
+	m := h.Union(g)
+	checkContains(t, m, x, true)
+	checkContains(t, m, y, true)
+	checkContains(t, h, x, false)
+	checkContains(t, g, y, false)
+
+	// heaps reused after a union do not own the elements of the union
+	z := h.Insert(3, nil)
+	checkContains(t, h, z, true)
+	checkContains(t, m, z, false)
+	checkContains(t, h, x, false)
+
+	m.Suspend(y)
+	checkContains(t, m, y, true)
+	m.Resume(y)
+	m.ExtractMin()
+	checkContains(t, m, x, false)
+	m.Delete(y)
+	checkContains(t, m, y, false)
+}
+
+func TestHeapContainsUnionChain(t *testing.T) {
+	h := &Heap[int, any]{}
+	var elements []*Element[int, any]
+	for i := 0; i < 10; i++ {
+		g := &Heap[int, any]{}
+		elements = append(elements, g.Insert(i, nil))
+		if i%2 == 0 {
+			h = h.Union(g)
+		} else {
+			h = g.Union(h)
+		}
+	}
+	owners := make([]*owner, len(elements))
+	for i, e := range elements {
+		owners[i] = e.owner
+		checkContains(t, h, e, true)
+	}
+	for i, e := range elements {
+		// Contains must not write to the elements or their owners
+		if e.owner != owners[i] {
+			t.Fatalf("Contains changed the owner of element %d", i)
+		}
+	}
+	h.Decreasing(elements[0], -1)
+	if elements[0].owner != h.owner {
+		t.Fatal("Decreasing should point the element to the owner of the heap")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeapUnionHeld(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	y := g.Insert(2, nil)
+	h.Insert(3, nil)
+	h.Pin(x)
+	g.Suspend(y)
+	m := h.Union(g)
+	assert(t, m.Size(), 2)
+	assert(t, m.Pinned(), 1)
+	assert(t, m.Suspended(), 1)
+	if err := m.Check(); err != nil {
+		t.Fatal(err)
+	}
+	m.Unpin(x)
+	m.Resume(y)
+	assert(t, m.ExtractMin().Key(), 1)
+	assert(t, m.ExtractMin().Key(), 2)
+}
+
+func TestHeapMisuse(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	y := g.Insert(2, nil)
+	h.Insert(3, nil)
+	h.ExtractMin()
+	for name, fn := range map[string]func(){
+		"Decreasing extracted": func() { h.Decreasing(x, 0) },
+		"Delete extracted":     func() { h.Delete(x) },
+		"Decreasing foreign":   func() { h.Decreasing(y, 0) },
+		"Increasing foreign":   func() { h.Increasing(y, 5) },
+		"Update foreign":       func() { h.Update(y, 5) },
+		"Remove foreign":       func() { h.Remove(y, 0) },
+		"Delete foreign":       func() { h.Delete(y) },
+		"Suspend foreign":      func() { h.Suspend(y) },
+		"Pin foreign":          func() { h.Pin(y) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.Size(), 1)
+	assert(t, g.Size(), 1)
+}
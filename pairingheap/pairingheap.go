@@ -0,0 +1,254 @@
+// Package pairingheap implements a pairing heap with the same API as the
+// Fibonacci heap of package fibheap. A pairing heap is a single heap-ordered
+// tree whose root is the minimum. Removing the root links its children in
+// pairs, and then links the pairs into one tree. Its bounds are amortized, and
+// its simple links make it one of the fastest heaps in practice:
+// fetching the minimum is Θ(1),
+// inserting and melding are Θ(1),
+// extracting the minimum and deleting are O(log n),
+// and decreasing a key is O(log n), although it is much faster in practice.
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package pairingheap
+
+import (
+	"cmp"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Element is an element of a pairing heap. It is a node of the tree of the
+// heap, and remains a valid handle until it is extracted or deleted.
+type Element[K any, V any] struct {
+	// child is the first child, next the next sibling, and prev the previous
+	// sibling, or the parent of a first child. The root has no prev.
+	child, next, prev *Element[K, V]
+	key               K
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// Heap represents the pairing heap. The keys of a Heap are of an ordered type,
+// and its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order    O
+	root     *Element[K, V]
+	elements int
+}
+
+var _ fibheap.MeldableHeap[int, any, *Element[int, any], *Heap[int, any]] = (*Heap[int, any])(nil)
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("pairingheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less}
+}
+
+// Size returns the number of elements in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.elements
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with running time Θ(1)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	if h.elements == 0 {
+		h.mustOrder()
+	}
+	e := &Element[K, V]{key: key, Value: value}
+	h.root = h.link(h.root, e)
+	h.elements++
+	return e
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1)
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	return h.root
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with amortized
+// running time O(log n)
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	z := h.root
+	if z == nil {
+		return nil
+	}
+	h.root = h.combine(z.child)
+	z.child = nil
+	h.elements--
+	return z
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// O(log n). The element x is cut from its parent and linked with the root. If
+// the new key is larger or equal than the key of x, Decreasing does nothing.
+// Decreasing panics if x was extracted or deleted.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	if !h.order.Less(key, x.key) {
+		return
+	}
+	x.key = key
+	if x != h.root {
+		h.cut(x)
+		h.root = h.link(h.root, x)
+	}
+}
+
+// Delete removes the element x from the heap h with amortized running time
+// O(log n). The element x is cut from its parent, and its children are combined
+// as by ExtractMin and linked with the root. Delete panics if x was extracted or
+// deleted.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	h.mustContain(x, "Delete")
+	if x == h.root {
+		h.ExtractMin()
+		return
+	}
+	h.cut(x)
+	h.root = h.link(h.root, h.combine(x.child))
+	x.child = nil
+	h.elements--
+}
+
+// Meld moves every element of the heap g into the heap h with running time
+// Θ(1), leaving g empty. The heap g must order keys in the same way as the heap
+// h.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("pairingheap: Meld expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("pairingheap: Meld expects two different heaps")
+	}
+	if !valid[K](h.order) {
+		h.order = g.order
+	}
+	h.root = h.link(h.root, g.root)
+	h.elements += g.elements
+	g.root, g.elements = nil, 0
+}
+
+// Union unions the two pairing heaps h and g, and returns the new pairing heap
+// with running time Θ(1). The heap h and g will be reset after unioning.
+func (h *HeapOf[K, V, O]) Union(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("pairingheap: Union expects non-nil heap h and g")
+	}
+	m := &HeapOf[K, V, O]{order: h.order}
+	m.Meld(h)
+	if g != h {
+		m.Meld(g)
+	}
+	return m
+}
+
+// link links the trees rooted at a and b, either of which may be nil, making
+// the root with the larger key the first child of the other, and returns the
+// root of the linked tree. The roots must have no siblings.
+func (h *HeapOf[K, V, O]) link(a, b *Element[K, V]) *Element[K, V] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case h.order.Less(b.key, a.key):
+		a, b = b, a
+	}
+	b.prev = a
+	b.next = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	return a
+}
+
+// combine links the siblings starting at first into one tree and returns its
+// root, or nil if there is none. The siblings are linked in pairs from left to
+// right, and the pairs are then linked from right to left.
+func (h *HeapOf[K, V, O]) combine(first *Element[K, V]) *Element[K, V] {
+	// the linked pairs are stacked through next
+	var pairs *Element[K, V]
+	for first != nil {
+		a, b := first, first.next
+		if b != nil {
+			first = b.next
+			b.next, b.prev = nil, nil
+		} else {
+			first = nil
+		}
+		a.next, a.prev = nil, nil
+		t := h.link(a, b)
+		t.next = pairs
+		pairs = t
+	}
+	var root *Element[K, V]
+	for pairs != nil {
+		t := pairs
+		pairs = t.next
+		t.next = nil
+		root = h.link(t, root)
+	}
+	return root
+}
+
+// cut removes the element x, which is not the root, from the list of children
+// of its parent.
+func (h *HeapOf[K, V, O]) cut(x *Element[K, V]) {
+	if x.prev.child == x {
+		x.prev.child = x.next
+	} else {
+		x.prev.next = x.next
+	}
+	if x.next != nil {
+		x.next.prev = x.prev
+	}
+	x.next, x.prev = nil, nil
+}
+
+// mustContain panics if the element x is not in a tree, that is, it is neither
+// the root of the heap h nor linked to a parent or a sibling.
+func (h *HeapOf[K, V, O]) mustContain(x *Element[K, V], method string) {
+	if x == nil || (x.prev == nil && x != h.root) {
+		panic("pairingheap: " + method + " expects an element of the heap")
+	}
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !valid[K](h.order) {
+		panic("pairingheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
+
+// valid reports whether the ordering o can compare keys, which the zero Func
+// cannot.
+func valid[K any, O fibheap.Order[K]](o O) bool {
+	f, ok := any(o).(fibheap.Func[K])
+	return !ok || f != nil
+}
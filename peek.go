@@ -0,0 +1,143 @@
+package fibheap
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// MinN returns the k smallest elements of the heap h in ascending order without
+// extracting them, so that, for example, the two smallest keys can be compared
+// before deciding whether to extract the minimum. If the heap holds fewer than
+// k elements, all of them are returned. It returns nil if k <= 0 or the heap is
+// empty. Pinned and suspended elements are not returned, as by Min.
+//
+// The heap is not modified. MinN searches the trees best first from the roots,
+// in O(r + k log(r+k)) time for a heap of r roots.
+func (h *HeapOf[K, V, O]) MinN(k int) []*Element[K, V] {
+	if k <= 0 || h == nil || h.min == nil {
+		return nil
+	}
+	list := make([]*Element[K, V], 0, min(k, h.elements))
+	h.ascend(false, func(e *Element[K, V]) bool {
+		list = append(list, e)
+		return len(list) < k
+	})
+	return list
+}
+
+// Sorted returns an iterator over the key-value pairs of the heap h in
+// ascending key order, including pinned elements but not suspended ones. The
+// iteration takes a snapshot of the pairs when it starts, in O(n) time, and
+// then finds them lazily in O(log n) time each, so that stopping early is
+// cheap. The heap is not modified, and modifying it during the iteration does
+// not affect the yielded pairs, so that a queue can be dumped in order while
+// it stays in use.
+func (h *HeapOf[K, V, O]) Sorted() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if h == nil {
+			return
+		}
+		s := &snapshot[K, V, O]{h: h, list: make([]pair[K, V], 0, h.Size())}
+		h.each(func(e *Element[K, V]) bool {
+			s.list = append(s.list, pair[K, V]{e.key, e.Value, e.seq})
+			return true
+		})
+		heap.Init(s)
+		for s.Len() > 0 {
+			p := s.list[0]
+			heap.Pop(s)
+			if !yield(p.key, p.value) {
+				return
+			}
+		}
+	}
+}
+
+// pair is a key-value pair copied out of a heap by Sorted.
+type pair[K any, V any] struct {
+	key   K
+	value V
+	seq   uint64
+}
+
+// snapshot is a binary heap of the pairs left to be yielded by Sorted, ordered
+// as their elements were in the heap h.
+type snapshot[K any, V any, O Order[K]] struct {
+	h    *HeapOf[K, V, O]
+	list []pair[K, V]
+}
+
+func (s *snapshot[K, V, O]) Len() int      { return len(s.list) }
+func (s *snapshot[K, V, O]) Swap(i, j int) { s.list[i], s.list[j] = s.list[j], s.list[i] }
+func (s *snapshot[K, V, O]) Push(x any)    { s.list = append(s.list, x.(pair[K, V])) }
+
+func (s *snapshot[K, V, O]) Less(i, j int) bool {
+	a, b := &s.list[i], &s.list[j]
+	less := s.h.order.Less
+	return less(a.key, b.key) || (s.h.stable && a.seq < b.seq && !less(b.key, a.key))
+}
+
+// Pop drops the last pair without returning it, which the caller has already
+// read, to avoid boxing it.
+func (s *snapshot[K, V, O]) Pop() any {
+	s.list = s.list[:len(s.list)-1]
+	return nil
+}
+
+// ascend calls yield with the elements of the trees of the heap h in ascending
+// order, and with the pinned elements as well if pinned is set, until yield
+// returns false. Since every tree is heap-ordered, the next element is the
+// smallest one among the roots and the children of the elements already
+// yielded, which are kept in a binary heap.
+func (h *HeapOf[K, V, O]) ascend(pinned bool, yield func(*Element[K, V]) bool) {
+	if h == nil {
+		return
+	}
+	c := &candidates[K, V, O]{h: h}
+	if h.min != nil {
+		for e := h.min; ; {
+			c.list = append(c.list, e)
+			if e = e.r; e == h.min {
+				break
+			}
+		}
+	}
+	if pinned {
+		for e := range h.pinned {
+			c.list = append(c.list, e)
+		}
+	}
+	heap.Init(c)
+	for c.Len() > 0 {
+		x := heap.Pop(c).(*Element[K, V])
+		if x.flags&tombstone == 0 && !yield(x) {
+			return
+		}
+		if x.children != nil {
+			for e := x.children; ; {
+				heap.Push(c, e)
+				if e = e.r; e == x.children {
+					break
+				}
+			}
+		}
+	}
+}
+
+// candidates is the frontier of ascend, a binary heap of elements whose parents
+// were already visited.
+type candidates[K any, V any, O Order[K]] struct {
+	h    *HeapOf[K, V, O]
+	list []*Element[K, V]
+}
+
+func (c *candidates[K, V, O]) Len() int           { return len(c.list) }
+func (c *candidates[K, V, O]) Less(i, j int) bool { return c.h.before(c.list[i], c.list[j]) }
+func (c *candidates[K, V, O]) Swap(i, j int)      { c.list[i], c.list[j] = c.list[j], c.list[i] }
+func (c *candidates[K, V, O]) Push(x any)         { c.list = append(c.list, x.(*Element[K, V])) }
+
+func (c *candidates[K, V, O]) Pop() any {
+	x := c.list[len(c.list)-1]
+	c.list = c.list[:len(c.list)-1]
+	return x
+}
@@ -0,0 +1,144 @@
+package fibheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHeapMinN(t *testing.T) {
+	h := &Heap[int, any]{}
+	if h.MinN(3) != nil {
+		t.Fatal("MinN on empty heap should return nil")
+	}
+	r := rand.New(rand.NewSource(1))
+	var keys []int
+	for i := 0; i < 200; i++ {
+		k := r.Intn(100)
+		keys = append(keys, k)
+		h.Insert(k, nil)
+	}
+	// consolidate the heap into trees
+	h.ExtractMin()
+	slices.Sort(keys)
+	keys = keys[1:]
+	if h.MinN(0) != nil {
+		t.Fatal("MinN should return nil for k <= 0")
+	}
+	for _, k := range []int{1, 2, 10, len(keys), len(keys) + 5} {
+		list := h.MinN(k)
+		assert(t, len(list), min(k, len(keys)))
+		for i, x := range list {
+			assert(t, x.Key(), keys[i])
+		}
+	}
+	assert(t, h.Size(), len(keys))
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeapMinNSkipped(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.SetLazyDelete(true)
+	elements := make([]*Element[int, string], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, "")
+	}
+	h.ExtractMin()
+	// tombstones, pinned and suspended elements are skipped
+	h.Delete(elements[2])
+	h.Pin(elements[3])
+	h.Suspend(elements[4])
+	list := h.MinN(4)
+	assert(t, len(list), 4)
+	for i, k := range []int{1, 5, 6, 7} {
+		assert(t, list[i].Key(), k)
+	}
+
+	g := &Heap[int, string]{}
+	g.SetStable(true)
+	for _, v := range []string{"a", "b", "c"} {
+		g.Insert(1, v)
+	}
+	g.Insert(0, "")
+	g.ExtractMin()
+	list = g.MinN(3)
+	for i, v := range []string{"a", "b", "c"} {
+		if list[i].Value != v {
+			t.Fatalf("expected equal keys in insertion order, got %q at %d", list[i].Value, i)
+		}
+	}
+}
+
+func TestHeapSorted(t *testing.T) {
+	h := &Heap[int, int]{}
+	for range h.Sorted() {
+		t.Fatal("Sorted should yield nothing on an empty heap")
+	}
+	h.SetLazyDelete(true)
+	r := rand.New(rand.NewSource(1))
+	var elements []*Element[int, int]
+	for i := 0; i < 300; i++ {
+		elements = append(elements, h.Insert(r.Intn(100), i))
+	}
+	h.ExtractMin()
+	h.Delete(elements[10])
+	h.Pin(elements[20])
+	h.Suspend(elements[30])
+	var keys []int
+	for k := range h.All() {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	stats := h.Stats()
+
+	var sorted []int
+	for k, v := range h.Sorted() {
+		if elements[v].Key() != k {
+			t.Fatalf("unexpected pair (%d, %d)", k, v)
+		}
+		sorted = append(sorted, k)
+	}
+	if !slices.Equal(sorted, keys) {
+		t.Fatalf("expected %v, got %v", keys, sorted)
+	}
+	if s := h.Stats(); s.Roots != stats.Roots || s.Size != stats.Size {
+		t.Fatal("Sorted should not modify the heap")
+	}
+	// stopping early
+	n := 0
+	for range h.Sorted() {
+		if n++; n == 5 {
+			break
+		}
+	}
+	assert(t, n, 5)
+}
+
+func TestHeapSortedModified(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	r := rand.New(rand.NewSource(1))
+	var elements []*Element[int, int]
+	for i := 0; i < 200; i++ {
+		elements = append(elements, h.Insert(r.Intn(50), i))
+	}
+	var want []int
+	for _, v := range h.Sorted() {
+		want = append(want, v)
+	}
+
+	var got []int
+	for _, v := range h.Sorted() {
+		got = append(got, v)
+		h.Insert(r.Intn(50), len(elements))
+		h.ExtractMin()
+		if m := h.Min(); m != nil {
+			h.Decreasing(m, m.Key()-1)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,256 @@
+// Package persistent implements a persistent heap. A heap is an immutable
+// value: Insert, ExtractMin and Meld return new heaps which share most of their
+// structure with the heaps they are derived from, so every version of a heap
+// stays valid. Versions can be kept as snapshots, backtracked to, or shared
+// between goroutines without locks or copies.
+//
+// Fibonacci heaps do not lend themselves to persistence, since their bounds are
+// amortized: an expensive operation could be repeated on an old version as many
+// times as wanted. The heap is a skew binomial heap of Brodal and Okasaki
+// instead, whose bounds are worst-case:
+// fetching the minimum and inserting are Θ(1),
+// extracting the minimum and melding are O(log n).
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package persistent
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// list is an immutable singly linked list, whose tails are shared between
+// versions of a heap.
+type list[T any] struct {
+	head T
+	tail *list[T]
+}
+
+// push returns the list l with x prepended.
+func push[T any](x T, l *list[T]) *list[T] {
+	return &list[T]{head: x, tail: l}
+}
+
+// tree is a skew binomial tree. Trees are never modified once they are part of
+// a heap.
+type tree[K any, V any] struct {
+	rank int
+	root fibheap.Pair[K, V]
+	// pairs placed by skew links, at most rank of them, which are not smaller
+	// than root
+	aux *list[fibheap.Pair[K, V]]
+	// children in decreasing rank
+	children *list[*tree[K, V]]
+}
+
+// Heap represents the persistent heap. The keys of a Heap are of an ordered
+// type, and its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc. A HeapOf is a small value which is copied freely.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order O
+	// trees in increasing rank, except that the first two may have the same
+	// rank
+	trees *list[*tree[K, V]]
+	min   fibheap.Pair[K, V]
+	size  int
+}
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) HeapFunc[K, V] {
+	if less == nil {
+		panic("persistent: NewHeapFunc expects a non-nil less function")
+	}
+	return HeapFunc[K, V]{order: less}
+}
+
+// Size returns the number of elements in the heap h
+func (h HeapOf[K, V, O]) Size() int {
+	return h.size
+}
+
+// Min fetches the key-value pair with the minimum key from the heap h with
+// running time Θ(1). It reports false if the heap h is empty.
+func (h HeapOf[K, V, O]) Min() (fibheap.Pair[K, V], bool) {
+	return h.min, h.size != 0
+}
+
+// Insert returns the heap h with the key-value pair (key, value) inserted, with
+// running time Θ(1). The heap h is unchanged.
+func (h HeapOf[K, V, O]) Insert(key K, value V) HeapOf[K, V, O] {
+	if h.size == 0 {
+		h.mustOrder()
+	}
+	p := fibheap.Pair[K, V]{Key: key, Value: value}
+	if ts := h.trees; ts != nil && ts.tail != nil && ts.head.rank == ts.tail.head.rank {
+		h.trees = push(h.skewLink(p, ts.head, ts.tail.head), ts.tail.tail)
+	} else {
+		h.trees = push(&tree[K, V]{root: p}, ts)
+	}
+	if h.size == 0 || h.order.Less(key, h.min.Key) {
+		h.min = p
+	}
+	h.size++
+	return h
+}
+
+// ExtractMin returns the key-value pair with the minimum key of the heap h, and
+// the heap h without it, with running time O(log n). The heap h is unchanged.
+// ExtractMin reports false if the heap h is empty.
+func (h HeapOf[K, V, O]) ExtractMin() (min fibheap.Pair[K, V], rest HeapOf[K, V, O], ok bool) {
+	if h.size == 0 {
+		return min, h, false
+	}
+	// the tree of the minimum is removed by copying the trees before it
+	var m *tree[K, V]
+	for ts := h.trees; ts != nil; ts = ts.tail {
+		if m == nil || h.order.Less(ts.head.root.Key, m.root.Key) {
+			m = ts.head
+		}
+	}
+	var prefix []*tree[K, V]
+	ts := h.trees
+	for ; ts.head != m; ts = ts.tail {
+		prefix = append(prefix, ts.head)
+	}
+	ts = ts.tail
+	for i := len(prefix) - 1; i >= 0; i-- {
+		ts = push(prefix[i], ts)
+	}
+	var children *list[*tree[K, V]]
+	for c := m.children; c != nil; c = c.tail {
+		children = push(c.head, children)
+	}
+
+	rest = HeapOf[K, V, O]{order: h.order, trees: h.mergeTrees(h.normalize(children), h.normalize(ts)), size: h.size - 1}
+	for a := m.aux; a != nil; a = a.tail {
+		rest.size--
+	}
+	rest.min = rest.findMin()
+	for a := m.aux; a != nil; a = a.tail {
+		rest = rest.Insert(a.head.Key, a.head.Value)
+	}
+	return m.root, rest, true
+}
+
+// Meld returns a heap holding the elements of both the heap h and the heap g,
+// with running time O(log n). The heaps h and g are unchanged, and must order
+// keys in the same way.
+func (h HeapOf[K, V, O]) Meld(g HeapOf[K, V, O]) HeapOf[K, V, O] {
+	switch {
+	case g.size == 0:
+		return h
+	case h.size == 0:
+		return g
+	}
+	m := HeapOf[K, V, O]{order: h.order, min: h.min, size: h.size + g.size}
+	m.trees = h.mergeTrees(h.normalize(h.trees), h.normalize(g.trees))
+	if h.order.Less(g.min.Key, h.min.Key) {
+		m.min = g.min
+	}
+	return m
+}
+
+// All returns an iterator over the key-value pairs of the heap h in ascending
+// order of keys, with running time O(log n) per pair. The iteration extracts
+// the pairs from successive versions of h, which is unchanged.
+func (h HeapOf[K, V, O]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for {
+			min, rest, ok := h.ExtractMin()
+			if !ok || !yield(min.Key, min.Value) {
+				return
+			}
+			h = rest
+		}
+	}
+}
+
+// findMin returns the minimum root of the heap h, which must be up to date in
+// every field but min.
+func (h HeapOf[K, V, O]) findMin() fibheap.Pair[K, V] {
+	var min fibheap.Pair[K, V]
+	for ts := h.trees; ts != nil; ts = ts.tail {
+		if ts == h.trees || h.order.Less(ts.head.root.Key, min.Key) {
+			min = ts.head.root
+		}
+	}
+	return min
+}
+
+// link returns the tree of rank r+1 made of the trees t1 and t2 of rank r.
+func (h HeapOf[K, V, O]) link(t1, t2 *tree[K, V]) *tree[K, V] {
+	if h.order.Less(t2.root.Key, t1.root.Key) {
+		t1, t2 = t2, t1
+	}
+	return &tree[K, V]{rank: t1.rank + 1, root: t1.root, aux: t1.aux, children: push(t2, t1.children)}
+}
+
+// skewLink returns the tree made of the trees t1 and t2 of rank r and the pair
+// p, which has rank r+1 as well.
+func (h HeapOf[K, V, O]) skewLink(p fibheap.Pair[K, V], t1, t2 *tree[K, V]) *tree[K, V] {
+	t := h.link(t1, t2)
+	if h.order.Less(t.root.Key, p.Key) {
+		t.aux = push(p, t.aux)
+	} else {
+		t.aux = push(t.root, t.aux)
+		t.root = p
+	}
+	return t
+}
+
+// insertTree returns the trees ts with the tree t prepended, linking t with the
+// first tree of ts as long as their ranks are equal. The rank of t must not be
+// larger than the rank of any tree of ts.
+func (h HeapOf[K, V, O]) insertTree(t *tree[K, V], ts *list[*tree[K, V]]) *list[*tree[K, V]] {
+	for ts != nil && ts.head.rank == t.rank {
+		t = h.link(t, ts.head)
+		ts = ts.tail
+	}
+	return push(t, ts)
+}
+
+// mergeTrees merges the lists of trees a and b, which are in strictly
+// increasing rank, into a list of trees in strictly increasing rank.
+func (h HeapOf[K, V, O]) mergeTrees(a, b *list[*tree[K, V]]) *list[*tree[K, V]] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.head.rank < b.head.rank:
+		return push(a.head, h.mergeTrees(a.tail, b))
+	case b.head.rank < a.head.rank:
+		return push(b.head, h.mergeTrees(a, b.tail))
+	default:
+		return h.insertTree(h.link(a.head, b.head), h.mergeTrees(a.tail, b.tail))
+	}
+}
+
+// normalize returns the trees ts, which are in increasing rank except for the
+// first two, in strictly increasing rank.
+func (h HeapOf[K, V, O]) normalize(ts *list[*tree[K, V]]) *list[*tree[K, V]] {
+	if ts == nil {
+		return nil
+	}
+	return h.insertTree(ts.head, ts.tail)
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h HeapOf[K, V, O]) mustOrder() {
+	if f, ok := any(h.order).(fibheap.Func[K]); ok && f == nil {
+		panic("persistent: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
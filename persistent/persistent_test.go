@@ -0,0 +1,281 @@
+package persistent
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// check verifies the ranks, the heap order, the minimum and the size of the
+// heap h.
+func (h HeapOf[K, V, O]) check() error {
+	size := 0
+	var checkTree func(t *tree[K, V]) error
+	checkTree = func(t *tree[K, V]) error {
+		size++
+		aux := 0
+		for a := t.aux; a != nil; a = a.tail {
+			if h.order.Less(a.head.Key, t.root.Key) {
+				return errors.New("an auxiliary pair is smaller than its root")
+			}
+			aux++
+			size++
+		}
+		if aux > t.rank {
+			return fmt.Errorf("%d auxiliary pairs in a tree of rank %d", aux, t.rank)
+		}
+		rank := t.rank
+		for c := t.children; c != nil; c = c.tail {
+			if c.head.rank >= rank {
+				return errors.New("the children are not in decreasing rank")
+			}
+			rank = c.head.rank
+			if h.order.Less(c.head.root.Key, t.root.Key) {
+				return errors.New("a child is smaller than its parent")
+			}
+			if err := checkTree(c.head); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for ts := h.trees; ts != nil; ts = ts.tail {
+		if next := ts.tail; next != nil && next.head.rank <= ts.head.rank && (ts != h.trees || next.head.rank != ts.head.rank) {
+			return errors.New("the trees are not in increasing rank")
+		}
+		if err := checkTree(ts.head); err != nil {
+			return err
+		}
+	}
+	if size != h.size {
+		return fmt.Errorf("expected size %d, got %d", size, h.size)
+	}
+	if min := h.findMin(); h.size != 0 && (h.order.Less(min.Key, h.min.Key) || h.order.Less(h.min.Key, min.Key)) {
+		return errors.New("the minimum is not the smallest root")
+	}
+	return nil
+}
+
+func keys[K any, V any, O fibheap.Order[K]](h HeapOf[K, V, O]) []K {
+	var keys []K
+	for k := range h.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestHeap(t *testing.T) {
+	var h Heap[int, string]
+	if _, ok := h.Min(); ok {
+		t.Fatal("an empty heap should have no minimum")
+	}
+	if _, rest, ok := h.ExtractMin(); ok || rest.Size() != 0 {
+		t.Fatal("ExtractMin should report false on an empty heap")
+	}
+	r := rand.New(rand.NewSource(1))
+	var expected []int
+	for i := 0; i < 1000; i++ {
+		k := r.Intn(100)
+		h = h.Insert(k, fmt.Sprint(k))
+		expected = append(expected, k)
+		if err := h.check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Sort(expected)
+	if min, ok := h.Min(); !ok || min.Key != expected[0] {
+		t.Fatalf("expected minimum %d, got %v", expected[0], min)
+	}
+	for i, k := range expected {
+		min, rest, ok := h.ExtractMin()
+		if !ok || min.Key != k || min.Value != fmt.Sprint(k) {
+			t.Fatalf("extraction %d: expected %d, got %v", i, k, min)
+		}
+		if err := rest.check(); err != nil {
+			t.Fatal(err)
+		}
+		h = rest
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+}
+
+func TestHeapPersistence(t *testing.T) {
+	var versions []Heap[int, int]
+	var h Heap[int, int]
+	for i := 0; i < 50; i++ {
+		versions = append(versions, h)
+		h = h.Insert((i*17)%50, i)
+	}
+	// deriving heaps from a version leaves it unchanged
+	_, rest, _ := h.ExtractMin()
+	rest.Insert(-1, 0).Meld(versions[20])
+	h.Insert(-2, 0)
+	for i, v := range versions {
+		if v.Size() != i || len(keys(v)) != i {
+			t.Fatalf("version %d: expected %d elements, got %d", i, i, len(keys(v)))
+		}
+		if err := v.check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(keys(h), keys(rest.Insert(0, 0))) {
+		t.Fatal("expected the same keys after reinserting the minimum")
+	}
+}
+
+func TestHeapMeld(t *testing.T) {
+	var empty Heap[int, int]
+	h := empty.Insert(3, 0).Insert(1, 0).Insert(4, 0)
+	g := empty.Insert(1, 1).Insert(5, 1).Insert(9, 1).Insert(2, 1)
+	if m := h.Meld(empty); !slices.Equal(keys(m), keys(h)) {
+		t.Fatal("melding an empty heap should not change the keys")
+	}
+	if m := empty.Meld(g); !slices.Equal(keys(m), keys(g)) {
+		t.Fatal("melding into an empty heap should give the keys of the heap")
+	}
+	m := h.Meld(g)
+	if err := m.check(); err != nil {
+		t.Fatal(err)
+	}
+	if k := keys(m); !slices.Equal(k, []int{1, 1, 2, 3, 4, 5, 9}) {
+		t.Fatalf("unexpected keys %v", k)
+	}
+	// a heap melded with itself holds every element twice
+	if k := keys(g.Meld(g)); !slices.Equal(k, []int{1, 1, 2, 2, 5, 5, 9, 9}) {
+		t.Fatalf("unexpected keys %v", k)
+	}
+	if h.Size() != 3 || g.Size() != 4 {
+		t.Fatal("Meld should leave the heaps unchanged")
+	}
+}
+
+func TestHeapFunc(t *testing.T) {
+	h := NewHeapFunc[string, int](func(a, b string) bool { return len(a) > len(b) })
+	for i, s := range []string{"a", "abc", "ab", "abcd"} {
+		h = h.Insert(s, i)
+	}
+	if min, _ := h.Min(); min.Key != "abcd" || min.Value != 3 {
+		t.Fatalf("expected abcd, got %v", min)
+	}
+	if k := keys(h); !slices.Equal(k, []string{"abcd", "abc", "ab", "a"}) {
+		t.Fatalf("unexpected keys %v", k)
+	}
+
+	for name, f := range map[string]func(){
+		"NewHeapFunc(nil)":   func() { NewHeapFunc[int, int](nil) },
+		"zero HeapFunc":      func() { HeapFunc[int, int]{}.Insert(0, 0) },
+		"zero HeapFunc Meld": func() { HeapFunc[int, int]{}.Meld(HeapFunc[int, int]{}).Insert(0, 0) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestHeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	type version struct {
+		heap  Heap[int, int]
+		model []int
+	}
+	versions := []version{{}}
+	for i := 0; i < 5000; i++ {
+		v := versions[r.Intn(len(versions))]
+		model := slices.Clone(v.model)
+		switch op := r.Intn(10); {
+		case op < 5:
+			k := r.Intn(1000)
+			v.heap = v.heap.Insert(k, i)
+			model = append(model, k)
+		case op < 8:
+			min, rest, ok := v.heap.ExtractMin()
+			if ok != (len(model) != 0) {
+				t.Fatalf("operation %d: ExtractMin reported %t with %d elements", i, ok, len(model))
+			}
+			if ok {
+				j := slices.Index(model, slices.Min(model))
+				if min.Key != model[j] {
+					t.Fatalf("operation %d: expected minimum %d, got %d", i, model[j], min.Key)
+				}
+				model = slices.Delete(model, j, j+1)
+			}
+			v.heap = rest
+		default:
+			// melding versions repeatedly would double their sizes
+			if w := versions[r.Intn(len(versions))]; len(model)+len(w.model) < 500 {
+				v.heap = v.heap.Meld(w.heap)
+				model = append(model, w.model...)
+			}
+		}
+		v.model = model
+		if err := v.heap.check(); err != nil {
+			t.Fatalf("operation %d: %v", i, err)
+		}
+		if v.heap.Size() != len(model) {
+			t.Fatalf("operation %d: expected %d elements, got %d", i, len(model), v.heap.Size())
+		}
+		if len(versions) < 100 {
+			versions = append(versions, v)
+		} else {
+			versions[r.Intn(len(versions))] = v
+		}
+	}
+	for _, v := range versions {
+		slices.Sort(v.model)
+		if !slices.Equal(keys(v.heap), v.model) {
+			t.Fatal("a version differs from its model")
+		}
+	}
+}
+
+func TestHeapConcurrent(t *testing.T) {
+	var h Heap[int, int]
+	for i := 0; i < 1000; i++ {
+		h = h.Insert(i, i)
+	}
+	// versions are shared between goroutines without locking
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := h
+			for i := 0; i < 100; i++ {
+				v = v.Insert(-g, g)
+				_, v, _ = v.ExtractMin()
+				_, v, _ = v.ExtractMin()
+			}
+			if min, _ := v.Min(); min.Key != 100 {
+				t.Errorf("goroutine %d: expected minimum 100, got %d", g, min.Key)
+			}
+		}()
+	}
+	wg.Wait()
+	if min, _ := h.Min(); h.Size() != 1000 || min.Key != 0 {
+		t.Fatal("the shared heap should be unchanged")
+	}
+}
+
+func BenchmarkHeapInsertExtract(b *testing.B) {
+	var h Heap[int, int]
+	for i := 0; i < 1000; i++ {
+		h = h.Insert(i*7919%1000, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h = h.Insert(i%1000, i)
+		_, h, _ = h.ExtractMin()
+	}
+}
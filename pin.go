@@ -0,0 +1,44 @@
+package fibheap
+
+// Pin holds the element x in the heap h so that Min and ExtractMin skip it until
+// Unpin is called. A pinned element keeps being counted by Size, and its key can
+// still be decreased. Pin panics if x is already pinned or suspended.
+func (h *HeapOf[K, V, O]) Pin(x *Element[K, V]) {
+	h.mustContain(x, "Pin")
+	if x.flags&(suspended|pinned) != 0 {
+		panic("fibheap: Pin expects an element which is not pinned or suspended")
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opPin, ID: x.id})
+	}
+	h.park(x, pinned)
+	if h.pinned == nil {
+		h.pinned = make(map[*Element[K, V]]struct{})
+	}
+	h.pinned[x] = struct{}{}
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// Unpin releases the element x pinned by Pin with amortized running time Θ(1),
+// so that it can be extracted again. Unpin panics if x is not pinned in the
+// heap h.
+func (h *HeapOf[K, V, O]) Unpin(x *Element[K, V]) {
+	if !h.Contains(x) || x.flags&pinned == 0 {
+		panic("fibheap: Unpin expects an element pinned in the heap")
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opUnpin, ID: x.id})
+	}
+	delete(h.pinned, x)
+	h.unpark(x, pinned)
+}
+
+// Pinned returns the number of elements pinned in the heap h.
+func (h *HeapOf[K, V, O]) Pinned() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.pinned)
+}
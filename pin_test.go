@@ -0,0 +1,71 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapPin(t *testing.T) {
+	h := &Heap[int, any]{}
+	elements := make([]*Element[int, any], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, nil)
+	}
+	h.Pin(elements[0])
+	h.Pin(elements[1])
+	assert(t, h.Size(), 10)
+	assert(t, h.Pinned(), 2)
+	assert(t, h.Min().Key(), 2)
+
+	for i := 2; i < 5; i++ {
+		assert(t, h.ExtractMin().Key(), i)
+	}
+	h.Unpin(elements[1])
+	h.Decreasing(elements[0], -1)
+	assert(t, h.ExtractMin().Key(), 1)
+	assert(t, h.ExtractMin().Key(), 5)
+	h.Unpin(elements[0])
+	assert(t, h.ExtractMin().Key(), -1)
+	assert(t, h.Size(), 4)
+	assert(t, h.Pinned(), 0)
+}
+
+func TestHeapPinOnlyElement(t *testing.T) {
+	h := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	h.Pin(x)
+	if h.ExtractMin() != nil {
+		t.Fatal("ExtractMin should skip the pinned element")
+	}
+	assert(t, h.Size(), 1)
+	h.Unpin(x)
+	if h.ExtractMin() != x {
+		t.Fatal("ExtractMin should extract the unpinned element")
+	}
+	assert(t, h.Size(), 0)
+}
+
+func TestHeapPinPanics(t *testing.T) {
+	h := &Heap[int, any]{}
+	a := h.Insert(1, nil)
+	b := h.Insert(2, nil)
+	h.Pin(a)
+	h.Suspend(b)
+
+	for name, fn := range map[string]func(){
+		"Pin twice":           func() { h.Pin(a) },
+		"Pin suspended":       func() { h.Pin(b) },
+		"Suspend pinned":      func() { h.Suspend(a) },
+		"Unpin not pinned":    func() { h.Unpin(b) },
+		"Resume pinned":       func() { h.Resume(a) },
+		"Unpin in other heap": func() { (&Heap[int, any]{}).Unpin(a) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s should panic()", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,66 @@
+package fibheap
+
+// Recycle hands the element x, which has been removed from a heap, back to the
+// heap h, so that a later Insert into h reuses x instead of allocating a new
+// element. Recycling extracted elements avoids the allocation per Insert in
+// workloads that keep inserting and extracting, and reduces the pressure on the
+// garbage collector. The key and the value of x are cleared, and x must not be
+// used afterwards: a later Insert may return it as a new element. Recycle
+// panics if x still belongs to a heap, is a tombstone of a lazily deleted
+// element, or has already been recycled.
+func (h *HeapOf[K, V, O]) Recycle(x *Element[K, V]) {
+	h.mustNotNil("Recycle")
+	if x == nil || !x.detached() || x.flags&(tombstone|recycled) != 0 {
+		panic("fibheap: Recycle expects an element removed from a heap")
+	}
+	*x = Element[K, V]{r: h.free, flags: recycled}
+	h.free = x
+}
+
+// alloc returns a new element of the heap h holding key and value, reusing a
+// recycled element if there is one.
+func (h *HeapOf[K, V, O]) alloc(key K, value V) *Element[K, V] {
+	n := h.free
+	if n == nil {
+		n = &Element[K, V]{key: key, Value: value, owner: h.own()}
+	} else {
+		h.free = n.r
+		*n = Element[K, V]{key: key, Value: value, owner: h.own()}
+	}
+	h.identify(n)
+	return n
+}
+
+// Init sets the key and the value of the element x, which belongs to no heap,
+// and clears the rest of it, so that x can be inserted by InsertElement. The
+// zero value of an Element is ready for Init. Init returns x, and panics if x
+// still belongs to a heap, is a tombstone of a lazily deleted element, or has
+// been recycled.
+func (x *Element[K, V]) Init(key K, value V) *Element[K, V] {
+	if x == nil || !x.detached() || x.flags&(tombstone|recycled) != 0 {
+		panic("fibheap: Init expects an element removed from a heap")
+	}
+	*x = Element[K, V]{key: key, Value: value}
+	return x
+}
+
+// InsertElement inserts the element x, which belongs to no heap, to the heap h
+// with its key and value, and gets a new ID as if it were inserted by Insert.
+// Unlike Insert, it never allocates: the caller provides the element, such as
+// one allocated in a slab, a field of a larger struct, or an element extracted
+// earlier and reused with Init. Together with ExtractMin, which detaches the
+// element it returns, this allows heaps to run without allocating in a steady
+// state. InsertElement panics if x still belongs to a heap, is a tombstone of a
+// lazily deleted element, or has been recycled.
+func (h *HeapOf[K, V, O]) InsertElement(x *Element[K, V]) {
+	h.mustNotNil("InsertElement")
+	if x == nil || !x.detached() || x.flags&(tombstone|recycled) != 0 {
+		panic("fibheap: InsertElement expects an element removed from a heap")
+	}
+	if h.min == nil {
+		h.mustOrder()
+	}
+	*x = Element[K, V]{key: x.key, Value: x.Value, owner: h.own()}
+	h.identify(x)
+	h.insert(x)
+}
@@ -0,0 +1,193 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapRecycle(t *testing.T) {
+	h := &Heap[int, *int]{}
+	v := new(int)
+	x := h.Insert(1, v)
+	h.Insert(2, nil)
+	h.ExtractMin()
+	h.Recycle(x)
+	if x.Value != nil {
+		t.Fatal("expected Recycle to clear the value")
+	}
+
+	y := h.Insert(0, v)
+	if y != x {
+		t.Fatal("expected Insert to reuse the recycled element")
+	}
+	if !h.Contains(y) {
+		t.Fatal("expected the reused element to belong to the heap")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.Size(), 2)
+	assert(t, h.ExtractMin().Key(), 0)
+	assert(t, h.ExtractMin().Key(), 2)
+	if h.Insert(3, nil) == x {
+		t.Fatal("expected the free list to be empty")
+	}
+}
+
+func TestHeapRecycleMisuse(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.SetLazyDelete(true)
+	h.Insert(0, 0)
+	x := h.Insert(1, 1)
+	y := h.Insert(2, 2)
+	h.Delete(y)
+	z := h.ExtractMin()
+	h.Recycle(z)
+	for name, fn := range map[string]func(){
+		"Recycle contained": func() { h.Recycle(x) },
+		"Recycle tombstone": func() { h.Recycle(y) },
+		"Recycle twice":     func() { h.Recycle(z) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestHeapInsertElement(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.SetStable(true)
+	var a, b Element[int, string]
+	h.InsertElement(a.Init(2, "a"))
+	h.InsertElement(b.Init(1, "b"))
+	c := h.Insert(2, "c")
+	if !h.Contains(&a) || !h.Contains(&b) {
+		t.Fatal("expected the inserted elements to belong to the heap")
+	}
+	if a.ID() == 0 || a.ID() == b.ID() || h.ByID(b.ID()) != &b {
+		t.Fatal("expected the inserted elements to get new IDs")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if h.ExtractMin() != &b || h.ExtractMin() != &a {
+		t.Fatal("expected the inserted elements to be extracted in order")
+	}
+
+	// an extracted element is reused with a new key and ID
+	id := b.ID()
+	h.InsertElement(b.Init(3, "d"))
+	if b.ID() == id {
+		t.Fatal("expected a reused element to get a new ID")
+	}
+	assert(t, h.Size(), 2)
+	if h.ExtractMin() != c {
+		t.Fatal("expected c to be the minimum")
+	}
+	if x := h.ExtractMin(); x != &b || x.Key() != 3 || x.Value != "d" {
+		t.Fatal("expected the reused element to hold its new key and value")
+	}
+
+	// an element of another heap is moved once extracted
+	g := &Heap[int, string]{}
+	g.InsertElement(&a)
+	g.ExtractMin()
+	h.InsertElement(&a)
+	if h.Min() != &a {
+		t.Fatal("expected the moved element to be the minimum")
+	}
+	if g.Contains(&a) {
+		t.Fatal("expected the element to leave the heap it was extracted from")
+	}
+}
+
+func TestHeapInsertElementMisuse(t *testing.T) {
+	h := &Heap[int, int]{}
+	h.SetLazyDelete(true)
+	h.Insert(0, 0)
+	x := h.Insert(1, 1)
+	y := h.Insert(2, 2)
+	h.Delete(y)
+	z := h.ExtractMin()
+	h.Recycle(z)
+	for name, fn := range map[string]func(){
+		"InsertElement contained": func() { h.InsertElement(x) },
+		"InsertElement tombstone": func() { h.InsertElement(y) },
+		"InsertElement recycled":  func() { h.InsertElement(z) },
+		"Init contained":          func() { x.Init(0, 0) },
+		"Init tombstone":          func() { y.Init(0, 0) },
+		"Init recycled":           func() { z.Init(0, 0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeapInsertElementAllocs(t *testing.T) {
+	h := &Heap[int, int]{}
+	for i := range 100 {
+		h.Insert(i, i)
+	}
+	i := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		x := h.ExtractMin()
+		h.InsertElement(x.Init(x.Key()+100, i))
+		i++
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocation in a steady state, got %v per run", allocs)
+	}
+}
+
+func benchmarkInsertExtract(b *testing.B, recycle bool) {
+	pairs := benchmarkPairs(1000)
+	h := &Heap[int, int]{}
+	for _, p := range pairs {
+		h.Insert(p.Key, p.Value)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := h.ExtractMin()
+		k := x.Key() + len(pairs)
+		if recycle {
+			h.Recycle(x)
+		}
+		h.Insert(k, i)
+	}
+}
+
+func BenchmarkHeapInsertExtract(b *testing.B) {
+	benchmarkInsertExtract(b, false)
+}
+
+func BenchmarkHeapInsertExtractRecycle(b *testing.B) {
+	benchmarkInsertExtract(b, true)
+}
+
+func BenchmarkHeapInsertExtractElement(b *testing.B) {
+	pairs := benchmarkPairs(1000)
+	h := &Heap[int, int]{}
+	for _, p := range pairs {
+		h.Insert(p.Key, p.Value)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := h.ExtractMin()
+		h.InsertElement(x.Init(x.Key()+len(pairs), i))
+	}
+}
@@ -0,0 +1,32 @@
+package fibheap
+
+// Potential returns the potential of the heap h in the amortized analysis of
+// Fibonacci heaps, the number of roots plus twice the number of marked
+// elements. The potential is the work which the operations so far have
+// deferred: the next consolidation visits every root, and cascading cuts turn
+// marked elements into roots. A potential growing without bound, for example
+// because elements are inserted and decreased but rarely extracted, predicts a
+// long ExtractMin. See SetConsolidateThreshold to spread that work instead. A
+// nil heap has no potential.
+func (h *HeapOf[K, V, O]) Potential() int {
+	if h == nil {
+		return 0
+	}
+	return h.roots + 2*h.marked
+}
+
+// SetWorkBudget registers fn to be called with the work of every consolidation
+// of the heap h exceeding budget, counted as the number of roots visited plus
+// the number of links made, such as to warn about the operations stalling a
+// latency-sensitive service. Consolidations happen in ExtractMin and the other
+// operations removing the minimum, and in the operations consolidating eagerly
+// as set by SetConsolidateThreshold. A nil fn removes the callback. The
+// function fn is called synchronously after the consolidation, and must not
+// modify the heap.
+func (h *HeapOf[K, V, O]) SetWorkBudget(budget int, fn func(work int)) {
+	h.mustNotNil("SetWorkBudget")
+	if budget < 0 {
+		panic("fibheap: SetWorkBudget expects a non-negative budget")
+	}
+	h.budget, h.overBudget = budget, fn
+}
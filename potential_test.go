@@ -0,0 +1,76 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHeapPotential(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 1000)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(10000), i)
+	}
+	assert(t, h.Potential(), 1000)
+	h.ExtractMin()
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 50; i++ {
+			if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+				h.Decreasing(x, x.Key()-r.Intn(1000))
+			}
+		}
+		h.ExtractMin()
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+		marked := 0
+		for x := range h.Elements() {
+			if x.Marked() {
+				marked++
+			}
+		}
+		assert(t, h.Potential(), h.Stats().Roots+2*marked)
+	}
+	// melding adds the potentials, which clearing drops
+	g := h.Clone()
+	p := h.Potential()
+	h.Meld(g)
+	assert(t, h.Potential(), 2*p)
+	h.Clear()
+	assert(t, h.Potential(), 0)
+	var nilHeap *Heap[int, int]
+	assert(t, nilHeap.Potential(), 0)
+}
+
+func TestHeapWorkBudget(t *testing.T) {
+	h := &Heap[int, int]{}
+	var works []int
+	h.SetWorkBudget(100, func(work int) { works = append(works, work) })
+	for i := 0; i < 1000; i++ {
+		h.Insert(i, i)
+	}
+	h.ExtractMin()
+	if len(works) != 1 || works[0] < 999 {
+		t.Fatalf("expected one consolidation over budget, got %v", works)
+	}
+	for i := 0; i < 100; i++ {
+		h.ExtractMin()
+	}
+	assert(t, len(works), 1)
+	h.SetWorkBudget(0, nil)
+	for i := 0; i < 1000; i++ {
+		h.Insert(i, i)
+	}
+	h.ExtractMin()
+	assert(t, len(works), 1)
+	mustPanic(t, "SetWorkBudget", "fibheap: SetWorkBudget expects", func() { h.SetWorkBudget(-1, nil) })
+
+	s := &SyncHeap[int, int]{}
+	s.SetWorkBudget(0, func(int) { works = append(works, 0) })
+	s.Insert(1, 1)
+	s.Insert(2, 2)
+	assert(t, s.Potential(), 2)
+	s.ExtractMin()
+	assert(t, len(works), 2)
+}
@@ -0,0 +1,114 @@
+package fibheap
+
+// DeleteWhere removes every element of the heap h for which pred reports true
+// for its key and value, including suspended and pinned elements, and returns
+// how many were removed, such as to cancel every job of a tenant without
+// keeping their handles. Unlike deleting the elements one by one, DeleteWhere
+// visits the heap once and then consolidates it once, in amortized O(n) time,
+// and removes the tombstones left by lazy deletion along the way. The heap is
+// rebuilt only if an element of its trees matched. The function pred must not
+// use the heap h.
+func (h *HeapOf[K, V, O]) DeleteWhere(pred func(K, V) bool) int {
+	h.mustNotNil("DeleteWhere")
+	return h.deleteWhere(func(e *Element[K, V]) bool {
+		return pred(e.key, e.Value)
+	})
+}
+
+// deleteWhere implements DeleteWhere for the elements for which match reports
+// true, which Replay also uses.
+func (h *HeapOf[K, V, O]) deleteWhere(match func(e *Element[K, V]) bool) int {
+	removed := h.prune(match)
+	if len(removed) == 0 {
+		return 0
+	}
+	if h.tracer != nil {
+		op := traceOp[K, V]{Op: opDeleteWhere, IDs: make([]uint64, len(removed))}
+		for i, x := range removed {
+			op.IDs[i] = x.id
+		}
+		h.record(op)
+	}
+	for _, x := range removed {
+		h.extracted(x)
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+	return len(removed)
+}
+
+// prune removes the elements of the heap h for which match reports true, and
+// returns them without reporting them to the callbacks. If any of them is in a
+// tree, the trees are taken apart into single roots, which are consolidated
+// again.
+func (h *HeapOf[K, V, O]) prune(match func(e *Element[K, V]) bool) []*Element[K, V] {
+	var removed []*Element[K, V]
+	for e := range h.suspended {
+		if match(e) {
+			delete(h.suspended, e)
+			e.flags &^= suspended
+			e.owner = nil
+			removed = append(removed, e)
+		}
+	}
+	for e := range h.pinned {
+		if match(e) {
+			delete(h.pinned, e)
+			e.flags &^= pinned
+			e.owner = nil
+			h.elements--
+			removed = append(removed, e)
+		}
+	}
+	if h.min == nil {
+		return removed
+	}
+
+	var nodes []*Element[K, V]
+	var matched []bool
+	found := false
+	for stack := []*Element[K, V]{h.min}; len(stack) > 0; {
+		list := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for e := list; ; {
+			m := e.flags&tombstone == 0 && match(e)
+			nodes = append(nodes, e)
+			matched = append(matched, m)
+			found = found || m
+			if e.children != nil {
+				stack = append(stack, e.children)
+			}
+			if e = e.r; e == list {
+				break
+			}
+		}
+	}
+	if !found {
+		return removed
+	}
+
+	var roots *Element[K, V]
+	h.roots, h.tombstones, h.marked = 0, 0, 0
+	for i, e := range nodes {
+		e.p, e.children = nil, nil
+		e.degree, e.below = 0, 0
+		switch {
+		case e.flags&tombstone != 0:
+			e.l, e.r = nil, nil
+		case matched[i]:
+			e.l, e.r = nil, nil
+			e.owner = nil
+			h.elements--
+			removed = append(removed, e)
+		default:
+			roots = roots.append(e)
+			h.roots++
+		}
+	}
+	h.min = roots
+	if h.min != nil {
+		h.consolidate()
+	}
+	return removed
+}
@@ -0,0 +1,125 @@
+package fibheap
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHeapDeleteWhere(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	elements := make([]*Element[int, int], 300)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(100), i)
+	}
+	h.ExtractMin()
+	for i := 0; i < 50; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+			h.Decreasing(x, x.Key()-r.Intn(20))
+		}
+	}
+	h.SetLazyDelete(true)
+	for i := 0; i < 20; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) && x != h.Min() {
+			h.Delete(x)
+		}
+	}
+	h.Pin(elements[30])
+	h.Pin(elements[31])
+	h.Suspend(elements[60])
+	h.Suspend(elements[61])
+	var moved []int
+	h.SetOnMove(func(x *Element[int, int], to *Heap[int, int]) {
+		if to != nil {
+			t.Fatal("deleted elements should move to no heap")
+		}
+		moved = append(moved, x.Value)
+	})
+
+	var expected []int
+	for _, x := range elements {
+		if h.Contains(x) && x.Value%3 == 0 {
+			expected = append(expected, x.Value)
+		}
+	}
+	size := h.Size()
+	if n := h.DeleteWhere(func(_ int, v int) bool { return v%3 == 0 }); n != len(expected) {
+		t.Fatalf("expected %d deleted elements, got %d", len(expected), n)
+	}
+	slices.Sort(moved)
+	if !slices.Equal(moved, expected) {
+		t.Fatal("every deleted element should be reported")
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.Tombstones(), 0)
+	assert(t, h.Suspended(), 1)
+	assert(t, h.Size(), size-len(expected)+1)
+	for _, x := range elements {
+		if x.Value%3 == 0 && h.Contains(x) {
+			t.Fatalf("element %d should be deleted", x.Value)
+		}
+	}
+	h.Unpin(elements[31])
+	h.Resume(elements[61])
+	prev := h.ExtractMin()
+	for x := h.ExtractMin(); x != nil; x = h.ExtractMin() {
+		if x.Value%3 == 0 || x.Key() < prev.Key() {
+			t.Fatalf("unexpected element %d after deleting", x.Value)
+		}
+		prev = x
+	}
+}
+
+func TestHeapDeleteWhereNone(t *testing.T) {
+	h := &Heap[int, int]{}
+	for i := 0; i < 100; i++ {
+		h.Insert(i, i)
+	}
+	h.ExtractMin()
+	before := shape(h)
+	if h.DeleteWhere(func(k, _ int) bool { return k > 1000 }) != 0 {
+		t.Fatal("no element should be deleted")
+	}
+	if shape(h) != before {
+		t.Fatal("the heap should be left unchanged")
+	}
+	if h.DeleteWhere(func(int, int) bool { return true }) != 99 || h.Size() != 0 || h.Min() != nil {
+		t.Fatal("every element should be deleted")
+	}
+
+	s := &SyncHeap[int, string]{}
+	s.Insert(1, "a")
+	s.Insert(2, "b")
+	if s.DeleteWhere(func(_ int, v string) bool { return v == "a" }) != 1 || s.Min().Value != "b" {
+		t.Fatal("SyncHeap.DeleteWhere should delete the element a")
+	}
+}
+
+func TestHeapDeleteWhereTrace(t *testing.T) {
+	h := &Heap[int, int]{}
+	var trace bytes.Buffer
+	if err := h.StartTrace(&trace); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		h.Insert(i%10, i)
+	}
+	h.ExtractMin()
+	h.DeleteWhere(func(_, v int) bool { return v%4 == 1 })
+	h.ExtractMin()
+	if err := h.StopTrace(); err != nil {
+		t.Fatal(err)
+	}
+	g := &Heap[int, int]{}
+	if err := g.Replay(&trace); err != nil {
+		t.Fatal(err)
+	}
+	if shape(g) != shape(h) {
+		t.Fatal("the replayed heap differs from the traced one")
+	}
+}
@@ -0,0 +1,39 @@
+package fibheap
+
+// Handle is the constraint satisfied by the element handles of the heaps of
+// this module, such as *Element.
+type Handle[K any, V any] interface {
+	comparable
+	Key() K
+	Pair() Pair[K, V]
+}
+
+// PriorityQueue is the interface of the heaps of this module, whose elements
+// are referred to by handles of type E. It is satisfied by *Heap, *MinMaxHeap
+// and by the heaps of the packages binaryheap, binomialheap, pairingheap,
+// radixheap, slabheap and strictfibheap, so that algorithms written against it
+// can swap heap implementations. The methods behave as those of Heap, except
+// for their running times, and for the keys a monotone heap such as radixheap
+// rejects.
+type PriorityQueue[K any, V any, E Handle[K, V]] interface {
+	Size() int
+	Insert(key K, value V) E
+	Min() E
+	ExtractMin() E
+	Decreasing(x E, key K)
+	Delete(x E)
+}
+
+// MeldableHeap is a PriorityQueue of type H which can absorb another heap of
+// the same type.
+type MeldableHeap[K any, V any, E Handle[K, V], H any] interface {
+	PriorityQueue[K, V, E]
+	Meld(g H)
+}
+
+var _ MeldableHeap[int, any, *Element[int, any], *Heap[int, any]] = (*Heap[int, any])(nil)
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() Pair[K, V] {
+	return Pair[K, V]{Key: e.key, Value: e.Value}
+}
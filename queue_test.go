@@ -0,0 +1,98 @@
+package fibheap_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+	"github.com/ksw2000/go-fibheap/binaryheap"
+	"github.com/ksw2000/go-fibheap/binomialheap"
+	"github.com/ksw2000/go-fibheap/internal/testsuite"
+	"github.com/ksw2000/go-fibheap/pairingheap"
+	"github.com/ksw2000/go-fibheap/radixheap"
+	"github.com/ksw2000/go-fibheap/slabheap"
+	"github.com/ksw2000/go-fibheap/strictfibheap"
+)
+
+// heapSort sorts keys with any priority queue, decreasing the key of one of
+// them and deleting another on the way.
+func heapSort[E fibheap.Handle[int, string]](q fibheap.PriorityQueue[int, string, E], keys []int) []int {
+	var elements []E
+	for _, k := range keys {
+		elements = append(elements, q.Insert(k, "value"))
+	}
+	q.Decreasing(elements[0], -1)
+	q.Delete(elements[1])
+	var sorted []int
+	for q.Size() > 0 {
+		sorted = append(sorted, q.ExtractMin().Pair().Key)
+	}
+	return sorted
+}
+
+func TestPriorityQueue(t *testing.T) {
+	keys := []int{5, 7, 3, 9, 1, 8, 2}
+	for name, sorted := range map[string][]int{
+		"fibheap":       heapSort(&fibheap.Heap[int, string]{}, keys),
+		"binaryheap":    heapSort(&binaryheap.Heap[int, string]{}, keys),
+		"binomialheap":  heapSort(&binomialheap.Heap[int, string]{}, keys),
+		"pairingheap":   heapSort(&pairingheap.Heap[int, string]{}, keys),
+		"radixheap":     heapSort(&radixheap.Heap[int, string]{}, keys),
+		"strictfibheap": heapSort(&strictfibheap.Heap[int, string]{}, keys),
+		"slabheap":      heapSort(&slabheap.Heap[int, string]{}, keys),
+	} {
+		expected := []int{-1, 1, 2, 3, 8, 9}
+		if len(sorted) != len(expected) {
+			t.Fatalf("%s: expected %v, got %v", name, expected, sorted)
+		}
+		for i := range expected {
+			if sorted[i] != expected[i] {
+				t.Fatalf("%s: expected %v, got %v", name, expected, sorted)
+			}
+		}
+	}
+}
+
+// sortNaN sorts keys holding NaN with any priority queue, and reports whether
+// the NaN keys came first.
+func sortNaN[E fibheap.Handle[float64, any]](q fibheap.PriorityQueue[float64, any, E]) bool {
+	nan := math.NaN()
+	for _, k := range []float64{3, nan, 1, nan, 2} {
+		q.Insert(k, nil)
+	}
+	x := q.Insert(4, nil)
+	q.Decreasing(x, nan)
+	var sorted []float64
+	for q.Size() > 0 {
+		sorted = append(sorted, q.ExtractMin().Key())
+	}
+	for i, k := range sorted {
+		if (i < 3) != math.IsNaN(k) || (i >= 3 && k != float64(i-2)) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPriorityQueueNaN(t *testing.T) {
+	for name, ok := range map[string]bool{
+		"fibheap":       sortNaN(&fibheap.Heap[float64, any]{}),
+		"binaryheap":    sortNaN(&binaryheap.Heap[float64, any]{}),
+		"binomialheap":  sortNaN(&binomialheap.Heap[float64, any]{}),
+		"pairingheap":   sortNaN(&pairingheap.Heap[float64, any]{}),
+		"strictfibheap": sortNaN(&strictfibheap.Heap[float64, any]{}),
+		"slabheap":      sortNaN(&slabheap.Heap[float64, any]{}),
+	} {
+		if !ok {
+			t.Errorf("%s: expected the NaN keys first", name)
+		}
+	}
+}
+
+func TestHeapConformance(t *testing.T) {
+	testsuite.ConformanceMeld(t, func() *fibheap.Heap[int, int] { return &fibheap.Heap[int, int]{} }, (*fibheap.Heap[int, int]).Check)
+}
+
+func TestMinMaxHeapConformance(t *testing.T) {
+	testsuite.Conformance(t, func() *fibheap.MinMaxHeap[int, int] { return &fibheap.MinMaxHeap[int, int]{} }, nil)
+}
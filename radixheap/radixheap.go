@@ -0,0 +1,236 @@
+// Package radixheap implements a radix heap, a monotone priority queue of
+// integer keys with the same API as the Fibonacci heap of package fibheap. A
+// monotone queue never holds a key less than the last extracted one, its
+// floor, which suits event queues keyed by time such as timers. The elements
+// are kept in buckets by the highest bit in which their key differs from the
+// floor, and ExtractMin moves the elements of the lowest bucket to lower ones,
+// at most once per bit of the key. The bounds are:
+// fetching the minimum is Θ(1), except after deleting it,
+// inserting, decreasing a key and deleting are Θ(1),
+// and extracting the minimum is amortized O(b) for keys of b bits.
+//
+// Keys are compared as integers. Insert and Decreasing panic on a key less
+// than the floor, which Floor returns.
+package radixheap
+
+import (
+	"math/bits"
+	"unsafe"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Integer is the constraint of the keys of a radix heap.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Element is an element of a radix heap. It remains a valid handle until it is
+// extracted or deleted.
+type Element[K Integer, V any] struct {
+	key K
+	// bucket and index locate the element in the buckets of its heap, and
+	// bucket is -1 once the element was removed.
+	bucket int
+	index  int
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// Heap represents the radix heap. Its zero value is an empty heap whose floor
+// is the smallest value of K.
+type Heap[K Integer, V any] struct {
+	// buckets[i] holds the elements whose key differs from the floor in bit
+	// i-1 and no higher bit, so that buckets[0] holds the keys equal to it.
+	buckets [65][]*Element[K, V]
+	// last is the floor mapped by radix.
+	last uint64
+	// min is the minimum element, or nil if the heap is empty or the minimum
+	// was deleted and has not been looked for again.
+	min      *Element[K, V]
+	elements int
+}
+
+var _ fibheap.PriorityQueue[int, any, *Element[int, any]] = (*Heap[int, any])(nil)
+
+// Size returns the number of elements in the heap h
+func (h *Heap[K, V]) Size() int {
+	return h.elements
+}
+
+// Floor returns the key of the element last extracted from the heap h, or the
+// smallest value of K if none was. Keys less than the floor cannot be inserted.
+func (h *Heap[K, V]) Floor() K {
+	switch {
+	case h.last == 0:
+		// which is the smallest key of an unsigned or 64-bit K, or the floor
+		// before the first extraction
+		return smallest[K]()
+	case signed[K]():
+		return K(int64(h.last ^ 1<<63))
+	}
+	return K(h.last)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with running time Θ(1). Insert panics if key is less than
+// the floor of the heap h.
+func (h *Heap[K, V]) Insert(key K, value V) *Element[K, V] {
+	if radix(key) < h.last {
+		panic("radixheap: Insert expects a key not less than Floor")
+	}
+	e := &Element[K, V]{key: key, Value: value}
+	h.put(e)
+	if h.elements == 0 || (h.min != nil && key < h.min.key) {
+		h.min = e
+	}
+	h.elements++
+	return e
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1). After
+// the minimum was deleted, Min looks for the next one in the lowest bucket,
+// which ExtractMin would scan anyway.
+func (h *Heap[K, V]) Min() *Element[K, V] {
+	if h.min == nil && h.elements > 0 {
+		b := h.lowest()
+		h.min = b[0]
+		for _, e := range b[1:] {
+			if e.key < h.min.key {
+				h.min = e
+			}
+		}
+	}
+	return h.min
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with amortized
+// running time O(b) for keys of b bits, and raises the floor to its key.
+func (h *Heap[K, V]) ExtractMin() *Element[K, V] {
+	z := h.Min()
+	if z == nil {
+		return nil
+	}
+	if r := radix(z.key); r != h.last {
+		// the lowest bucket holds the minimum, and its elements differ
+		// from the new floor in lower bits only
+		i := z.bucket
+		b := h.buckets[i]
+		h.buckets[i] = b[:0]
+		h.last = r
+		for _, e := range b {
+			h.put(e)
+		}
+		clear(b)
+	}
+	h.remove(z)
+	h.elements--
+	h.min = nil
+	if b := h.buckets[0]; len(b) > 0 {
+		h.min = b[len(b)-1]
+	}
+	return z
+}
+
+// Decreasing decreases the key of the element x with running time Θ(1). If the
+// new key is larger or equal than the key of x, Decreasing does nothing.
+// Decreasing panics if x was extracted or deleted, or if key is less than the
+// floor of the heap h.
+func (h *Heap[K, V]) Decreasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	if key >= x.key {
+		return
+	}
+	if radix(key) < h.last {
+		panic("radixheap: Decreasing expects a key not less than Floor")
+	}
+	h.remove(x)
+	x.key = key
+	h.put(x)
+	if h.min != nil && key < h.min.key {
+		h.min = x
+	}
+}
+
+// Delete removes the element x from the heap h with running time Θ(1). Delete
+// panics if x was extracted or deleted.
+func (h *Heap[K, V]) Delete(x *Element[K, V]) {
+	h.mustContain(x, "Delete")
+	h.remove(x)
+	h.elements--
+	if h.min == x {
+		h.min = nil
+		if b := h.buckets[0]; len(b) > 0 {
+			h.min = b[len(b)-1]
+		}
+	}
+}
+
+// put adds the element x to the bucket of its key.
+func (h *Heap[K, V]) put(x *Element[K, V]) {
+	i := bits.Len64(radix(x.key) ^ h.last)
+	x.bucket, x.index = i, len(h.buckets[i])
+	h.buckets[i] = append(h.buckets[i], x)
+}
+
+// remove removes the element x from its bucket, moving the last element of the
+// bucket to its place.
+func (h *Heap[K, V]) remove(x *Element[K, V]) {
+	b := h.buckets[x.bucket]
+	last := b[len(b)-1]
+	b[x.index], last.index = last, x.index
+	b[len(b)-1] = nil
+	h.buckets[x.bucket] = b[:len(b)-1]
+	x.bucket = -1
+}
+
+// lowest returns the lowest non-empty bucket of the non-empty heap h.
+func (h *Heap[K, V]) lowest() []*Element[K, V] {
+	for _, b := range h.buckets {
+		if len(b) > 0 {
+			return b
+		}
+	}
+	panic("radixheap: no element in a non-empty heap")
+}
+
+// mustContain panics if the element x is not in a heap.
+func (h *Heap[K, V]) mustContain(x *Element[K, V], method string) {
+	if x == nil || x.bucket < 0 {
+		panic("radixheap: " + method + " expects an element of the heap")
+	}
+}
+
+// signed reports whether K is a signed integer type.
+func signed[K Integer]() bool {
+	return ^K(0) < 0
+}
+
+// smallest returns the smallest value of K.
+func smallest[K Integer]() K {
+	if !signed[K]() {
+		return 0
+	}
+	// the sign bit alone
+	return K(1) << (8*unsafe.Sizeof(K(0)) - 1)
+}
+
+// radix maps the key k to an uint64 of the same order, so that the keys of
+// signed types can be compared bitwise.
+func radix[K Integer](k K) uint64 {
+	if signed[K]() {
+		return uint64(int64(k)) ^ 1<<63
+	}
+	return uint64(k)
+}
@@ -0,0 +1,178 @@
+package radixheap
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// check verifies that every element of the heap h is in the bucket of its key
+// at its index, that no key is less than the floor, and the minimum and the
+// size of h.
+func (h *Heap[K, V]) check() error {
+	count := 0
+	for i, b := range h.buckets {
+		for j, e := range b {
+			count++
+			if e.bucket != i || e.index != j {
+				return fmt.Errorf("element %v at %d:%d records %d:%d", e.key, i, j, e.bucket, e.index)
+			}
+			if r := radix(e.key); r < h.last || bits.Len64(r^h.last) != i {
+				return fmt.Errorf("element %v is in bucket %d with floor %v", e.key, i, h.Floor())
+			}
+			if h.min != nil && e.key < h.min.key {
+				return fmt.Errorf("element %v is less than the minimum %v", e.key, h.min.key)
+			}
+		}
+	}
+	if count != h.elements {
+		return fmt.Errorf("found %d elements, but Size is %d", count, h.elements)
+	}
+	return nil
+}
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if h.Min() != nil || h.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, -1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if h.Size() != 6 || h.Min().Key() != -1 {
+		t.Fatalf("expected 6 elements and minimum -1, got %d and %d", h.Size(), h.Min().Key())
+	}
+	for _, expected := range []int{-1, 2, 3, 5, 8, 9} {
+		if x := h.ExtractMin(); x.Key() != expected {
+			t.Fatalf("expected %d, got %d", expected, x.Key())
+		}
+		if h.Floor() != expected {
+			t.Fatalf("expected floor %d, got %d", expected, h.Floor())
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+}
+
+func TestHeapFloor(t *testing.T) {
+	if f := (&Heap[int8, any]{}).Floor(); f != math.MinInt8 {
+		t.Fatalf("expected floor %d, got %d", math.MinInt8, f)
+	}
+	if f := (&Heap[int64, any]{}).Floor(); f != math.MinInt64 {
+		t.Fatalf("expected floor %d, got %d", int64(math.MinInt64), f)
+	}
+	if f := (&Heap[uint16, any]{}).Floor(); f != 0 {
+		t.Fatalf("expected floor 0, got %d", f)
+	}
+
+	h := &Heap[int8, int]{}
+	h.Insert(math.MinInt8, 0)
+	h.Insert(-3, 1)
+	h.ExtractMin()
+	if f := h.Floor(); f != math.MinInt8 {
+		t.Fatalf("expected floor %d, got %d", math.MinInt8, f)
+	}
+	x := h.Insert(100, 2)
+	h.ExtractMin()
+	if f := h.Floor(); f != -3 {
+		t.Fatalf("expected floor -3, got %d", f)
+	}
+	// keys equal to the floor are accepted
+	h.Decreasing(x, -3)
+	h.Insert(-3, 3)
+
+	for name, f := range map[string]func(){
+		"Insert":     func() { h.Insert(-4, 4) },
+		"Decreasing": func() { h.Decreasing(x, -4) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s should panic on a key less than the floor", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestHeapDecreasingDelete(t *testing.T) {
+	h := &Heap[uint, int]{}
+	elements := make([]*Element[uint, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(uint(i+100), i)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[73], 100)
+	h.Decreasing(elements[50], 300)
+	if x := h.Min(); x != elements[73] || x.Value != 73 {
+		t.Fatalf("expected element 73 as the minimum, got %v", x.Value)
+	}
+	h.Delete(elements[73])
+	h.Delete(elements[99])
+	if err := h.check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 99; i++ {
+		if i == 73 {
+			continue
+		}
+		if x := h.ExtractMin(); x != elements[i] {
+			t.Fatalf("expected element %d, got %d", i, x.Value)
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	h.Delete(elements[0])
+}
+
+func TestHeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	var elements []*Element[int, int]
+	var model []int
+	for i := 0; i < 20000; i++ {
+		floor := max(h.Floor(), -1000)
+		switch op := r.Intn(10); {
+		case op < 4 || len(elements) == 0:
+			k := floor + r.Intn(1000)
+			elements = append(elements, h.Insert(k, i))
+			model = append(model, k)
+		case op < 6:
+			j := r.Intn(len(elements))
+			k := max(h.Floor(), elements[j].Key()-r.Intn(100))
+			h.Decreasing(elements[j], k)
+			model[j] = elements[j].Key()
+		case op < 7:
+			j := r.Intn(len(elements))
+			h.Delete(elements[j])
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		default:
+			m := slices.Min(model)
+			x := h.ExtractMin()
+			if x.Key() != m {
+				t.Fatalf("expected the minimum %d, got %d", m, x.Key())
+			}
+			j := slices.Index(elements, x)
+			elements = slices.Delete(elements, j, j+1)
+			model = slices.Delete(model, j, j+1)
+		}
+		if i%100 == 0 {
+			if err := h.check(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
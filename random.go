@@ -0,0 +1,86 @@
+package fibheap
+
+import "math/rand/v2"
+
+// ExtractRandom fetches and removes an element chosen uniformly at random among
+// the elements of the heap h which could be extracted, that is, excluding
+// suspended and pinned elements, such as to shed load from a queue without
+// favoring any priority. The element is drawn from r, or from the global
+// source of math/rand/v2 if r is nil, and removed as Delete does. It returns
+// nil if no element can be extracted.
+//
+// The first call counts the elements of every subtree of h in O(n) time, and
+// the counts are then kept up to date by the heap. An element is drawn by
+// walking down from the roots, choosing each subtree with a probability
+// proportional to its size, in O(r + d log n) time for a heap of r roots whose
+// trees are d deep. The walk along the roots is amortized into the
+// consolidation of Delete, and the trees are O(log n) deep unless many
+// elements were cut from their parents, so that ExtractRandom usually runs in
+// amortized O(log n) time. While the counts are kept, cutting an element from
+// its parent, as Decreasing and Delete do, updates the counts of its ancestors
+// in O(d) time. Meld keeps the counts if both heaps have them, while Union,
+// Clear and unmarshaling drop them.
+//
+// Tombstones left by SetLazyDelete stay in the counts until they are purged,
+// and a draw which falls on one is made again, so that a draw takes (n+t)/n
+// times as long for n elements and t tombstones.
+func (h *HeapOf[K, V, O]) ExtractRandom(r *rand.Rand) *Element[K, V] {
+	if h == nil || h.min == nil {
+		return nil
+	}
+	if !h.sized {
+		countList(h.min)
+		h.sized = true
+	}
+	total := 0
+	for e := h.min; ; {
+		total += e.below + 1
+		if e = e.r; e == h.min {
+			break
+		}
+	}
+	for {
+		var i int
+		if r != nil {
+			i = r.IntN(total)
+		} else {
+			i = rand.IntN(total)
+		}
+		x := h.min
+		for i > x.below {
+			i -= x.below + 1
+			x = x.r
+		}
+		// i is the index of the drawn element in the subtree of x, where x
+		// itself comes first
+		for i > 0 {
+			i--
+			x = x.children
+			for i > x.below {
+				i -= x.below + 1
+				x = x.r
+			}
+		}
+		if x.flags&tombstone == 0 {
+			h.Delete(x)
+			return x
+		}
+	}
+}
+
+// countList sets the number of elements below every element in the circular
+// list starting at x and their descendants, and returns the number of elements
+// in the list and below it.
+func countList[K any, V any](x *Element[K, V]) int {
+	if x == nil {
+		return 0
+	}
+	n := 0
+	for e := x; ; {
+		e.below = countList(e.children)
+		n += e.below + 1
+		if e = e.r; e == x {
+			return n
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package fibheap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestHeapExtractRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	h := &Heap[int, int]{}
+	for i := 0; i < 4; i++ {
+		h.Insert(i, i)
+	}
+	// reinserting the drawn elements reshapes the trees between the draws
+	counts := make([]int, 4)
+	for i := 0; i < 4000; i++ {
+		x := h.ExtractRandom(r)
+		counts[x.Value]++
+		h.Insert(x.Key(), x.Value)
+	}
+	for v, n := range counts {
+		if n < 850 || n > 1150 {
+			t.Fatalf("element %d was drawn %d times out of 4000", v, n)
+		}
+	}
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, h.Size(), 4)
+}
+
+func TestHeapExtractRandomHeld(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 10)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+	h.Pin(elements[1])
+	h.Suspend(elements[2])
+	h.SetLazyDelete(true)
+	h.Delete(elements[5])
+
+	var drawn []int
+	for x := h.ExtractRandom(r); x != nil; x = h.ExtractRandom(r) {
+		drawn = append(drawn, x.Value)
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Sort(drawn)
+	if !slices.Equal(drawn, []int{3, 4, 6, 7, 8, 9}) {
+		t.Fatalf("unexpected elements %v", drawn)
+	}
+	assert(t, h.Size(), 1)
+
+	// released elements can be drawn again
+	h.Unpin(elements[1])
+	h.Resume(elements[2])
+	drawn = drawn[:0]
+	for x := h.ExtractRandom(r); x != nil; x = h.ExtractRandom(r) {
+		drawn = append(drawn, x.Value)
+	}
+	slices.Sort(drawn)
+	if !slices.Equal(drawn, []int{1, 2}) {
+		t.Fatalf("unexpected elements %v", drawn)
+	}
+}
+
+func TestHeapExtractRandomRecycle(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	h := &Heap[int, int]{}
+	for i := 0; i < 100; i++ {
+		h.Insert(i, i)
+	}
+	h.ExtractRandom(r)
+	// recycled elements are reused with another ID, and must not be drawn
+	// twice
+	for i := 0; i < 50; i++ {
+		h.Recycle(h.ExtractMin())
+	}
+	for i := 100; i < 150; i++ {
+		h.Insert(i, i)
+	}
+	g := &Heap[int, int]{}
+	g.Insert(1000, 1000)
+	h.Meld(g)
+
+	seen := map[int]bool{}
+	for x := h.ExtractRandom(nil); x != nil; x = h.ExtractRandom(nil) {
+		if seen[x.Value] {
+			t.Fatalf("element %d drawn twice", x.Value)
+		}
+		seen[x.Value] = true
+	}
+	assert(t, len(seen), 100)
+	if !seen[1000] {
+		t.Fatal("the melded element was not drawn")
+	}
+}
+
+func TestHeapExtractRandomCounts(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	h := &Heap[int, int]{}
+	h.SetLazyDelete(true)
+	var elements []*Element[int, int]
+	for i := 0; i < 500; i++ {
+		elements = append(elements, h.Insert(r.IntN(1000), i))
+	}
+	h.ExtractMin()
+	h.ExtractRandom(r)
+	if !h.sized {
+		t.Fatal("expected ExtractRandom to count the subtrees")
+	}
+	// every kind of operation keeps the counts right
+	for i := 0; i < 2000; i++ {
+		x := elements[r.IntN(len(elements))]
+		switch op := r.IntN(10); {
+		case !h.Contains(x) || op == 0:
+			elements = append(elements, h.Insert(r.IntN(1000), i))
+		case op == 1:
+			h.Decreasing(x, x.Key()-r.IntN(100))
+		case op == 2:
+			h.Increasing(x, x.Key()+r.IntN(100))
+		case op == 3:
+			h.Delete(x)
+		case op == 4 && x.flags == 0:
+			h.Pin(x)
+			h.Unpin(x)
+		case op == 5 && x.flags == 0:
+			h.Suspend(x)
+			h.Resume(x)
+		case op == 6:
+			h.ReplaceMin(r.IntN(1000), i)
+		case op == 7:
+			h.ExtractMin()
+		case op == 8:
+			h.DeleteWhere(func(k, _ int) bool { return k%97 == 0 })
+		default:
+			h.ExtractRandom(r)
+		}
+		if err := h.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Clone().Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// melding keeps the counts only if both heaps have them
+	g := &Heap[int, int]{}
+	for i := 0; i < 100; i++ {
+		g.Insert(i, i)
+	}
+	g.ExtractRandom(r)
+	h.Meld(g)
+	if !h.sized {
+		t.Fatal("expected Meld to keep the counts of both heaps")
+	}
+	g.Insert(0, 0)
+	h.Meld(g)
+	if h.sized {
+		t.Fatal("expected Meld to drop the counts missing from g")
+	}
+	h.ExtractRandom(r)
+	if err := h.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeapExtractRandomWrappers(t *testing.T) {
+	m := &MaxHeap[int, int]{}
+	m.Insert(1, 1)
+	if x := m.ExtractRandom(nil); x == nil || x.Value != 1 || m.Size() != 0 {
+		t.Fatal("expected MaxHeap.ExtractRandom to extract the only element")
+	}
+	s := NewSyncHeap[int, int](0)
+	s.Insert(1, 1)
+	if x := s.ExtractRandom(nil); x == nil || x.Value != 1 || s.Size() != 0 {
+		t.Fatal("expected SyncHeap.ExtractRandom to extract the only element")
+	}
+}
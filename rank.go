@@ -0,0 +1,101 @@
+package fibheap
+
+// Rank returns the number of elements of the heap h which come before the
+// element x in extraction order, so that x is the Rank(x)-th element extracted
+// if nothing else changes, counting from zero, and Select(Rank(x)) is x. The
+// elements whose key equals the key of x are counted in the order in which
+// Select and MinN list them, which is the order of extraction in a stable heap
+// but may differ from it otherwise. Pinned and suspended elements are not
+// counted, as by MinN. If x itself is held, Rank returns the number of elements
+// which would come before it if it were released, leaving out those with an
+// equal key unless the heap is stable.
+//
+// The heap is not modified. Rank searches the trees best first from the roots
+// as Select does, in O(r + k log(r+k)) time for a heap of r roots, where k is
+// the rank. Rank panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Rank(x *Element[K, V]) int {
+	h.mustContain(x, "Rank")
+	if h.min == nil {
+		// x is held, and the trees are empty
+		return 0
+	}
+	if x.flags&(suspended|pinned) != 0 {
+		return h.count(func(e *Element[K, V]) bool {
+			return h.before(e, x)
+		})
+	}
+	n := 0
+	h.ascend(false, func(e *Element[K, V]) bool {
+		if e == x {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// CountLess returns the number of elements of the heap h whose key is less than
+// key, such as the number of queued jobs of a higher priority, without
+// extracting them. Pinned and suspended elements are not counted. CountLess
+// searches the trees from the roots, skipping the subtrees whose root has a key
+// not less than key, in O(r + k log n) time for a heap of r roots, where k is
+// the result, since no element has more than O(log n) children.
+func (h *HeapOf[K, V, O]) CountLess(key K) int {
+	if h == nil || h.min == nil {
+		return 0
+	}
+	return h.count(func(e *Element[K, V]) bool {
+		return h.order.Less(e.key, key)
+	})
+}
+
+// Select returns the element of rank k of the heap h, the k-th element which
+// ExtractMin would return counting from zero, without extracting it. Elements
+// with equal keys come in the order of MinN, which is the order of extraction
+// only in a stable heap. It returns nil if k is negative or not less than the
+// number of elements which can be extracted. Select runs in O(r + k log(r+k))
+// time as MinN.
+func (h *HeapOf[K, V, O]) Select(k int) *Element[K, V] {
+	if k < 0 || h == nil || h.min == nil {
+		return nil
+	}
+	var x *Element[K, V]
+	h.ascend(false, func(e *Element[K, V]) bool {
+		if k == 0 {
+			x = e
+			return false
+		}
+		k--
+		return true
+	})
+	return x
+}
+
+// count returns the number of elements in the trees of the heap h, which must
+// not be empty, for which before reports true. The function before must be
+// monotonic along the heap order: the subtrees of an element for which it
+// reports false are skipped.
+func (h *HeapOf[K, V, O]) count(before func(e *Element[K, V]) bool) int {
+	n := 0
+	stack := []*Element[K, V]{h.min}
+	for len(stack) > 0 {
+		list := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for e := list; ; {
+			// tombstones keep the heap order of their subtrees
+			if before(e) {
+				if e.flags&tombstone == 0 {
+					n++
+				}
+				if e.children != nil {
+					stack = append(stack, e.children)
+				}
+			}
+			if e = e.r; e == list {
+				break
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,131 @@
+package fibheap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHeapRankSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	elements := make([]*Element[int, int], 500)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(100), i)
+	}
+	h.ExtractMin()
+	for i := 0; i < 100; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+			h.Decreasing(x, x.Key()-r.Intn(50))
+		}
+	}
+	h.SetLazyDelete(true)
+	for i := 0; i < 50; i++ {
+		if x := elements[r.Intn(len(elements))]; h.Contains(x) {
+			h.Delete(x)
+		}
+	}
+
+	order := h.MinN(h.Size())
+	for k, x := range order {
+		if rank := h.Rank(x); rank != k {
+			t.Fatalf("expected rank %d, got %d", k, rank)
+		}
+		if y := h.Select(k); y != x {
+			t.Fatalf("Select(%d) returned another element than MinN", k)
+		}
+	}
+	if h.Select(-1) != nil || h.Select(len(order)) != nil {
+		t.Fatal("Select should return nil out of range")
+	}
+	for _, key := range []int{-100, 0, 10, 50, 99, 1000} {
+		expected := 0
+		for _, x := range order {
+			if x.Key() < key {
+				expected++
+			}
+		}
+		assert(t, h.CountLess(key), expected)
+	}
+	assert(t, h.Size(), len(order))
+}
+
+func TestHeapRankDuplicates(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 300)
+	for i := range elements {
+		elements[i] = h.Insert(r.Intn(5), i)
+	}
+	h.ExtractMin()
+	for _, x := range elements {
+		if !h.Contains(x) {
+			continue
+		}
+		if y := h.Select(h.Rank(x)); y != x {
+			t.Fatalf("Select(Rank(x)) returned %d instead of %d", y.Value, x.Value)
+		}
+	}
+}
+
+func TestHeapRankMelded(t *testing.T) {
+	// the elements of both heaps get distinct sequence numbers, so that they
+	// are extracted in insertion order
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	g := &Heap[int, int]{}
+	g.SetStable(true)
+	var elements []*Element[int, int]
+	for i := 0; i < 100; i++ {
+		elements = append(elements, h.Insert(i%3, i), g.Insert(i%3, -i))
+	}
+	h.Meld(g)
+	ranks := make(map[*Element[int, int]]int)
+	for _, x := range elements {
+		ranks[x] = h.Rank(x)
+		if y := h.Select(ranks[x]); y != x {
+			t.Fatalf("Select(Rank(x)) returned %d instead of %d", y.Value, x.Value)
+		}
+	}
+	slices.SortStableFunc(elements, func(a, b *Element[int, int]) int {
+		return a.Key() - b.Key()
+	})
+	for k, x := range elements {
+		if y := h.ExtractMin(); y != x {
+			t.Fatalf("expected %d at rank %d, got %d", x.Value, k, y.Value)
+		}
+		assert(t, ranks[x], k)
+	}
+}
+
+func TestHeapRankHeld(t *testing.T) {
+	h := &Heap[int, string]{}
+	a := h.Insert(1, "a")
+	b := h.Insert(2, "b")
+	c := h.Insert(3, "c")
+	h.Pin(a)
+	assert(t, h.Rank(c), 1)
+	assert(t, h.Rank(a), 0)
+	assert(t, h.CountLess(3), 1)
+	if h.Select(0) != b {
+		t.Fatal("Select should skip pinned elements")
+	}
+	h.Suspend(b)
+	h.Suspend(c)
+	assert(t, h.Rank(c), 0)
+	assert(t, h.CountLess(10), 0)
+	mustPanic(t, "Rank", "fibheap: Rank", func() { h.Rank(&Element[int, string]{}) })
+
+	s := &SyncHeap[int, int]{}
+	x, _ := s.Insert(1, 1)
+	s.Insert(0, 0)
+	if s.Rank(x) != 1 || s.CountLess(1) != 1 || s.Select(1) != x {
+		t.Fatal("unexpected ranks of the SyncHeap")
+	}
+
+	var nilHeap *Heap[int, string]
+	if nilHeap.CountLess(0) != 0 || nilHeap.Select(0) != nil {
+		t.Fatal("a nil heap should be empty")
+	}
+}
@@ -0,0 +1,46 @@
+package fibheap
+
+// ReplaceMin extracts the minimum of the heap h and inserts the key-value pair
+// (key, value) in one step, reusing the element of the minimum for the new
+// pair, and returns it. It is faster than ExtractMin followed by Insert, which
+// is the inner loop of k-way merges and sliding windows: no element is
+// allocated, and if the new key is not larger than the old one, the minimum
+// stays in place with running time Θ(1), unless the heap is stable and the keys
+// are equal. Otherwise, its children are moved to the root list as by
+// Increasing, and the heap is consolidated with amortized running time
+// O(log n).
+//
+// The returned element is the former minimum, so handles to it now refer to
+// the new pair; read the old pair from Min beforehand if needed. The element
+// keeps its ID, and callbacks see it as extracted and inserted again, except
+// for SetOnMove since it does not leave the heap. If the heap h is empty,
+// ReplaceMin inserts the pair as Insert.
+func (h *HeapOf[K, V, O]) ReplaceMin(key K, value V) *Element[K, V] {
+	h.mustNotNil("ReplaceMin")
+	x := h.min
+	if x == nil {
+		return h.Insert(key, value)
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opReplaceMin, ID: x.id, Key: key, Value: value})
+	}
+	// a stable heap extracts the new pair after the elements with an equal
+	// key, as it would if the pair were inserted
+	restructure := h.order.Less(x.key, key) || (h.stable && !h.order.Less(key, x.key))
+	x.key, x.Value = key, value
+	if h.stable {
+		h.sequence(x)
+	}
+	if restructure {
+		h.unchild(x)
+		h.consolidate()
+	}
+	if h.observer != nil {
+		h.observer.Extracted()
+		h.observer.Inserted()
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+	return x
+}
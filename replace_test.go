@@ -0,0 +1,132 @@
+package fibheap
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHeapReplaceMin(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	rec := &recorder{}
+	h.SetObserver(rec)
+	x := h.ReplaceMin(5, 0)
+	if h.Min() != x || h.Size() != 1 {
+		t.Fatal("ReplaceMin on an empty heap should insert the pair")
+	}
+	model := []int{5}
+	for i := 1; i < 200; i++ {
+		k := r.Intn(1000)
+		h.Insert(k, i)
+		model = append(model, k)
+	}
+	for i := 0; i < 2000; i++ {
+		min := h.Min()
+		slices.Sort(model)
+		if min.Key() != model[0] {
+			t.Fatalf("replacement %d: expected minimum %d, got %d", i, model[0], min.Key())
+		}
+		k := model[0] + r.Intn(100) - 20
+		if x := h.ReplaceMin(k, i); x != min || x.Key() != k || x.Value != i {
+			t.Fatal("ReplaceMin should reuse the element of the minimum")
+		}
+		model[0] = k
+		if i%100 == 0 {
+			if err := h.Check(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	assert(t, h.Size(), 200)
+	assert(t, rec.inserts, 2200)
+	assert(t, rec.extracts, 2000)
+	slices.Sort(model)
+	for _, k := range model {
+		assert(t, h.ExtractMin().Key(), k)
+	}
+}
+
+func TestHeapReplaceMinStable(t *testing.T) {
+	h := &Heap[int, string]{}
+	h.SetStable(true)
+	h.Insert(1, "a")
+	h.Insert(1, "b")
+	h.Insert(2, "c")
+	h.ExtractMin()
+	h.Insert(1, "d")
+	// the replaced pair goes after the pairs with an equal key
+	h.ReplaceMin(1, "e")
+	if x := h.Min(); x.Value != "d" {
+		t.Fatalf("expected d, got %s", x.Value)
+	}
+	h.ReplaceMin(0, "f")
+	for _, expected := range []string{"f", "e", "c"} {
+		if x := h.ExtractMin(); x.Value != expected {
+			t.Fatalf("expected %s, got %s", expected, x.Value)
+		}
+	}
+}
+
+func TestHeapReplaceMinTrace(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	var trace bytes.Buffer
+	if err := h.StartTrace(&trace); err != nil {
+		t.Fatal(err)
+	}
+	h.ReplaceMin(50, 0)
+	for i := 1; i < 100; i++ {
+		h.Insert(r.Intn(100), i)
+	}
+	for i := 0; i < 100; i++ {
+		h.ReplaceMin(h.Min().Key()+r.Intn(20)-5, i)
+	}
+	if err := h.StopTrace(); err != nil {
+		t.Fatal(err)
+	}
+	g := &Heap[int, int]{}
+	if err := g.Replay(&trace); err != nil {
+		t.Fatal(err)
+	}
+	if shape(g) != shape(h) {
+		t.Fatal("the replayed heap differs from the traced one")
+	}
+}
+
+// benchmarkMerge merges k sorted runs through the heap h, taking the next key of
+// a run with next.
+func benchmarkMerge(b *testing.B, k int, next func(h *Heap[int, int], key, run int)) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := &Heap[int, int]{}
+		for run := 0; run < k; run++ {
+			h.Insert(run, run)
+		}
+		for n := 0; n < 100000; n++ {
+			min := h.Min()
+			next(h, min.Key()+k, min.Value)
+		}
+	}
+}
+
+func BenchmarkHeapReplaceMin(b *testing.B) {
+	b.Run("ReplaceMin", func(b *testing.B) {
+		benchmarkMerge(b, 64, func(h *Heap[int, int], key, run int) {
+			h.ReplaceMin(key, run)
+		})
+	})
+	b.Run("ExtractMin+Insert", func(b *testing.B) {
+		benchmarkMerge(b, 64, func(h *Heap[int, int], key, run int) {
+			h.ExtractMin()
+			h.Insert(key, run)
+		})
+	})
+	b.Run("ExtractMin+Recycle+Insert", func(b *testing.B) {
+		benchmarkMerge(b, 64, func(h *Heap[int, int], key, run int) {
+			h.Recycle(h.ExtractMin())
+			h.Insert(key, run)
+		})
+	})
+}
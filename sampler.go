@@ -0,0 +1,136 @@
+package fibheap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Sample is a snapshot of the shape of a heap.
+type Sample struct {
+	// Time is the time at which the sample was taken.
+	Time time.Time
+	// Stats describes the shape of the heap, as returned by Stats.
+	Stats
+	// OldestAge is the time the oldest element has spent in the heap, or zero
+	// if the sampler cannot tell the age of elements.
+	OldestAge time.Duration
+}
+
+// Sampler periodically records samples of the shape of a heap into a ring
+// buffer, so that the evolution of the heap can be inspected later.
+type Sampler[K any, V any] struct {
+	// Locker, if non-nil, is held while the heap is sampled. It should be the
+	// lock which guards the heap against concurrent modification. A SyncHeap
+	// sampled by a sampler of NewSyncSampler is locked anyway.
+	Locker sync.Locker
+	// Enqueued, if non-nil, reports the time at which an element was inserted
+	// into the heap, typically recorded in its value. It is used to compute
+	// the age of the oldest element.
+	Enqueued func(e *Element[K, V]) time.Time
+
+	heap    sampled[K, V]
+	mu      sync.Mutex
+	samples []Sample
+	next    int
+	full    bool
+}
+
+// sampled is implemented by the heaps a Sampler can sample.
+type sampled[K any, V any] interface {
+	sample(now time.Time, enqueued func(e *Element[K, V]) time.Time) Sample
+}
+
+// NewSampler returns a sampler of the heap h which keeps the last size samples.
+// NewSampler panics if size is not positive.
+func NewSampler[K any, V any, O Order[K]](h *HeapOf[K, V, O], size int) *Sampler[K, V] {
+	return newSampler[K, V](h, size)
+}
+
+// NewSyncSampler returns a sampler of the heap s which keeps the last size
+// samples. The heap s is locked while it is sampled. NewSyncSampler panics if
+// size is not positive.
+func NewSyncSampler[K any, V any, O Order[K]](s *SyncHeapOf[K, V, O], size int) *Sampler[K, V] {
+	return newSampler[K, V](s, size)
+}
+
+func newSampler[K any, V any](h sampled[K, V], size int) *Sampler[K, V] {
+	if size <= 0 {
+		panic("fibheap: NewSampler expects a positive size")
+	}
+	return &Sampler[K, V]{
+		heap:    h,
+		samples: make([]Sample, size),
+	}
+}
+
+// Sample takes a sample of the heap, records it and returns it. Taking a sample
+// visits every element of the heap.
+func (s *Sampler[K, V]) Sample() Sample {
+	if s.Locker != nil {
+		s.Locker.Lock()
+	}
+	sample := s.heap.sample(time.Now(), s.Enqueued)
+	if s.Locker != nil {
+		s.Locker.Unlock()
+	}
+
+	s.mu.Lock()
+	s.samples[s.next] = sample
+	s.next++
+	if s.next == len(s.samples) {
+		s.next = 0
+		s.full = true
+	}
+	s.mu.Unlock()
+	return sample
+}
+
+// Run takes a sample every interval until the context ctx is done.
+func (s *Sampler[K, V]) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Sample()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Samples returns the recorded samples from the oldest to the newest.
+func (s *Sampler[K, V]) Samples() []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		return append([]Sample(nil), s.samples[:s.next]...)
+	}
+	return append(append([]Sample(nil), s.samples[s.next:]...), s.samples[:s.next]...)
+}
+
+// sample returns a sample of the heap h taken at now, computing the age of the
+// oldest element with enqueued if it is not nil.
+func (h *HeapOf[K, V, O]) sample(now time.Time, enqueued func(e *Element[K, V]) time.Time) Sample {
+	sample := Sample{Time: now}
+	var visit func(e *Element[K, V])
+	if enqueued != nil {
+		visit = func(e *Element[K, V]) {
+			if e.flags&tombstone != 0 {
+				return
+			}
+			if age := now.Sub(enqueued(e)); age > sample.OldestAge {
+				sample.OldestAge = age
+			}
+		}
+	}
+	sample.Stats = h.stats(visit)
+	return sample
+}
+
+func (s *SyncHeapOf[K, V, O]) sample(now time.Time, enqueued func(e *Element[K, V]) time.Time) Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.sample(now, enqueued)
+}
@@ -0,0 +1,91 @@
+package fibheap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSamplerSample(t *testing.T) {
+	h := &Heap[int, time.Time]{}
+	start := time.Now().Add(-time.Minute)
+	elements := make([]*Element[int, time.Time], 64)
+	for i := range elements {
+		elements[i] = h.Insert(i, start.Add(time.Duration(i)*time.Second))
+	}
+
+	s := NewSampler(h, 4)
+	s.Enqueued = func(e *Element[int, time.Time]) time.Time {
+		return e.Value
+	}
+	sample := s.Sample()
+	assert(t, sample.Size, 64)
+	assert(t, sample.Roots, 64)
+	assert(t, sample.MaxDegree, 0)
+	if sample.OldestAge < time.Minute {
+		t.Fatalf("expected the oldest age to be at least a minute, got %v", sample.OldestAge)
+	}
+
+	h.ExtractMin()
+	h.Decreasing(elements[63], -1)
+	h.Decreasing(elements[62], -2)
+	sample = s.Sample()
+	assert(t, sample.Size, 63)
+	assert(t, sample.MaxDegree, 5)
+	assert(t, sample.Marked, 1)
+}
+
+func TestSamplerRing(t *testing.T) {
+	h := &Heap[int, any]{}
+	s := NewSampler(h, 3)
+	for i := 0; i < 5; i++ {
+		h.Insert(i, nil)
+		s.Sample()
+	}
+	samples := s.Samples()
+	assert(t, len(samples), 3)
+	for i, sample := range samples {
+		assert(t, sample.Size, i+3)
+	}
+}
+
+func TestSamplerRun(t *testing.T) {
+	var mu sync.Mutex
+	h := &Heap[int, any]{}
+	s := NewSampler(h, 8)
+	s.Locker = &mu
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		mu.Lock()
+		h.Insert(i, nil)
+		mu.Unlock()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+	if len(s.Samples()) == 0 {
+		t.Fatal("Run should record samples")
+	}
+}
+
+func TestSyncSampler(t *testing.T) {
+	h := NewSyncHeap[int, any](0)
+	s := NewSyncSampler(h, 2)
+	for i := 0; i < 10; i++ {
+		h.Insert(i, nil)
+	}
+	h.ExtractMin()
+	sample := s.Sample()
+	stats := h.Stats()
+	assert(t, sample.Size, stats.Size)
+	assert(t, sample.Roots, stats.Roots)
+	assert(t, sample.Height, stats.Height)
+	assert(t, sample.MaxDegree, stats.MaxDegree)
+}
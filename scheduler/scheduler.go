@@ -0,0 +1,273 @@
+// Package scheduler runs functions at given times. A Scheduler keeps the
+// pending tasks in a priority queue keyed by their deadline, and a single
+// goroutine sleeps until the earliest deadline, so no timer is created per
+// task. By default, the queue is a Fibonacci heap, so scheduling and
+// cancelling a task costs O(log n) at most, and moving a task to an earlier
+// deadline decreases its key, which takes amortized running time Θ(1). The
+// default queue is stable, so tasks due at the same time run in the order they
+// were scheduled. Any other fibheap.PriorityQueue can be selected with
+// WithQueue, such as a pairing heap of package pairingheap, or a radix heap of
+// package radixheap, which suits deadlines as they only move forward.
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Handle refers to a task scheduled by Schedule. The zero value refers to no
+// task.
+type Handle struct {
+	t *Task
+}
+
+// Task is a scheduled function. It is exported so that the priority queues given
+// to WithQueue can be typed, and is only used through a Handle otherwise.
+type Task struct {
+	fn func()
+	// e is the element of the queue holding a pending task, and is nil once
+	// the task has run or was cancelled.
+	e any
+}
+
+// Option configures a Scheduler created by New.
+type Option func(*Scheduler)
+
+// WithQueue makes the scheduler keep its tasks in the empty priority queue q,
+// for example a *binaryheap.Heap[time.Duration, *scheduler.Task], whose small
+// constant factors suit short queues. Deadlines are moved earlier with
+// Decreasing, and later by deleting and reinserting the task. The queue must
+// not be used by anything else. WithQueue panics if q is not empty.
+//
+// A monotone queue, such as a *radixheap.Heap[time.Duration, *scheduler.Task],
+// rejects keys less than the deadline of the last task run, which it reports
+// with a Floor method. Deadlines before the floor have passed, and are moved to
+// the floor, so that the task still runs as soon as possible.
+func WithQueue[E fibheap.Handle[time.Duration, *Task]](q fibheap.PriorityQueue[time.Duration, *Task, E]) Option {
+	if q.Size() != 0 {
+		panic("scheduler: WithQueue expects an empty queue")
+	}
+	a := &adapter[E]{q: q}
+	if m, ok := q.(interface{ Floor() time.Duration }); ok {
+		a.floor = m.Floor
+	}
+	return func(s *Scheduler) {
+		s.tasks = a
+	}
+}
+
+// queue is the priority queue of a scheduler, which keeps the element of each
+// pending task in the task.
+type queue interface {
+	insert(t *Task, at time.Duration)
+	min() (*Task, time.Duration)
+	extractMin() *Task
+	update(t *Task, at time.Duration)
+	delete(t *Task)
+	size() int
+}
+
+// adapter implements queue with a fibheap.PriorityQueue.
+type adapter[E fibheap.Handle[time.Duration, *Task]] struct {
+	q fibheap.PriorityQueue[time.Duration, *Task, E]
+	// floor returns the smallest deadline q accepts, if q is monotone.
+	floor func() time.Duration
+}
+
+func (a *adapter[E]) insert(t *Task, at time.Duration) {
+	t.e = a.q.Insert(a.clamp(at), t)
+}
+
+func (a *adapter[E]) min() (*Task, time.Duration) {
+	var none E
+	if m := a.q.Min(); m != none {
+		p := m.Pair()
+		return p.Value, p.Key
+	}
+	return nil, 0
+}
+
+func (a *adapter[E]) extractMin() *Task {
+	t := a.q.ExtractMin().Pair().Value
+	t.e = nil
+	return t
+}
+
+func (a *adapter[E]) update(t *Task, at time.Duration) {
+	e := t.e.(E)
+	at = a.clamp(at)
+	switch key := e.Key(); {
+	case at < key:
+		a.q.Decreasing(e, at)
+	case at > key:
+		a.q.Delete(e)
+		a.insert(t, at)
+	}
+}
+
+func (a *adapter[E]) delete(t *Task) {
+	a.q.Delete(t.e.(E))
+	t.e = nil
+}
+
+func (a *adapter[E]) size() int {
+	return a.q.Size()
+}
+
+// clamp returns the deadline at, or the floor of a monotone queue if at is
+// less.
+func (a *adapter[E]) clamp(at time.Duration) time.Duration {
+	if a.floor != nil {
+		return max(at, a.floor())
+	}
+	return at
+}
+
+// Scheduler runs scheduled functions at their deadlines on its own goroutine.
+// It is safe for concurrent use. A Scheduler is created by New and stopped by
+// Stop.
+type Scheduler struct {
+	mu sync.Mutex
+	// tasks is keyed by the deadlines as durations since epoch, which keeps
+	// the monotonic clock reading of the deadlines.
+	tasks queue
+	epoch time.Time
+	// wake is signaled when a task becomes the earliest one
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	stop    sync.Once
+}
+
+// New returns a scheduler configured by opts and starts its goroutine.
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		epoch:   time.Now(),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.tasks == nil {
+		h := &fibheap.Heap[time.Duration, *Task]{}
+		h.SetStable(true)
+		s.tasks = &adapter[*fibheap.Element[time.Duration, *Task]]{q: h}
+	}
+	go s.run()
+	return s
+}
+
+// Schedule schedules fn to run at the time at, and returns a handle to cancel or
+// reschedule it. Tasks whose deadline has already passed run as soon as
+// possible. The function fn runs on the goroutine of the scheduler s, which
+// runs no other task meanwhile, so long work should be started on its own
+// goroutine. Schedule panics if fn is nil.
+func (s *Scheduler) Schedule(at time.Time, fn func()) Handle {
+	if fn == nil {
+		panic("scheduler: Schedule expects a non-nil function")
+	}
+	t := &Task{fn: fn}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks.insert(t, at.Sub(s.epoch))
+	if m, _ := s.tasks.min(); m == t {
+		s.signal()
+	}
+	return Handle{t}
+}
+
+// Cancel cancels the task h, and reports whether it was pending, that is, it
+// had neither run nor been cancelled.
+func (s *Scheduler) Cancel(h Handle) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h.t == nil || h.t.e == nil {
+		return false
+	}
+	s.tasks.delete(h.t)
+	return true
+}
+
+// Reschedule moves the task h to the time at, and reports whether it was
+// pending. A task which has run or was cancelled is not scheduled again.
+func (s *Scheduler) Reschedule(h Handle, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h.t == nil || h.t.e == nil {
+		return false
+	}
+	s.tasks.update(h.t, at.Sub(s.epoch))
+	if m, _ := s.tasks.min(); m == h.t {
+		s.signal()
+	}
+	return true
+}
+
+// Pending returns the number of tasks which have neither run nor been
+// cancelled.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasks.size()
+}
+
+// Stop stops the goroutine of the scheduler s, and waits for the task it is
+// running, if any, to return. Pending tasks do not run after Stop returns, and
+// tasks scheduled afterwards never run.
+func (s *Scheduler) Stop() {
+	s.stop.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+}
+
+// signal wakes up the goroutine of the scheduler s to look at the earliest
+// deadline again. It is called with the lock held.
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run runs the tasks whose deadline has passed one at a time, so that a task
+// can still be cancelled while earlier ones run, and sleeps until the next
+// deadline or a signal.
+func (s *Scheduler) run() {
+	defer close(s.stopped)
+	timer := time.NewTimer(0)
+	timer.Stop()
+	for {
+		s.mu.Lock()
+		now := time.Since(s.epoch)
+		if m, at := s.tasks.min(); m != nil && at <= now {
+			t := s.tasks.extractMin()
+			s.mu.Unlock()
+			t.fn()
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+			continue
+		}
+		var next <-chan time.Time
+		if m, at := s.tasks.min(); m != nil {
+			timer.Reset(at - now)
+			next = timer.C
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-next:
+		case <-s.wake:
+			timer.Stop()
+		case <-s.done:
+			return
+		}
+	}
+}
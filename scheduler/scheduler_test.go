@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksw2000/go-fibheap/binaryheap"
+	"github.com/ksw2000/go-fibheap/binomialheap"
+	"github.com/ksw2000/go-fibheap/pairingheap"
+	"github.com/ksw2000/go-fibheap/radixheap"
+	"github.com/ksw2000/go-fibheap/slabheap"
+	"github.com/ksw2000/go-fibheap/strictfibheap"
+)
+
+// queues returns the options selecting every queue a scheduler can use, in
+// addition to the default one.
+func queues() map[string][]Option {
+	return map[string][]Option{
+		"default":       nil,
+		"binaryheap":    {WithQueue(&binaryheap.Heap[time.Duration, *Task]{})},
+		"binomialheap":  {WithQueue(&binomialheap.Heap[time.Duration, *Task]{})},
+		"pairingheap":   {WithQueue(&pairingheap.Heap[time.Duration, *Task]{})},
+		"radixheap":     {WithQueue(&radixheap.Heap[time.Duration, *Task]{})},
+		"slabheap":      {WithQueue(&slabheap.Heap[time.Duration, *Task]{})},
+		"strictfibheap": {WithQueue(&strictfibheap.Heap[time.Duration, *Task]{})},
+	}
+}
+
+// forEachQueue runs test with a scheduler of every queue.
+func forEachQueue(t *testing.T, test func(t *testing.T, s *Scheduler)) {
+	for name, opts := range queues() {
+		t.Run(name, func(t *testing.T) {
+			s := New(opts...)
+			defer s.Stop()
+			test(t, s)
+		})
+	}
+}
+
+// recorder records the order in which tasks run.
+type recorder struct {
+	mu   sync.Mutex
+	runs []int
+	done chan int
+}
+
+func newRecorder() *recorder {
+	return &recorder{done: make(chan int, 100)}
+}
+
+func (r *recorder) task(i int) func() {
+	return func() {
+		r.mu.Lock()
+		r.runs = append(r.runs, i)
+		r.mu.Unlock()
+		r.done <- i
+	}
+}
+
+func (r *recorder) wait(t *testing.T, n int) []int {
+	t.Helper()
+	for ; n > 0; n-- {
+		select {
+		case <-r.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for tasks")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.runs)
+}
+
+func TestScheduler(t *testing.T) {
+	forEachQueue(t, func(t *testing.T, s *Scheduler) {
+		r := newRecorder()
+		now := time.Now()
+		for _, i := range []int{3, 1, 4, 2, 5} {
+			s.Schedule(now.Add(time.Duration(i)*10*time.Millisecond), r.task(i))
+		}
+		// a deadline in the past runs at once
+		s.Schedule(now.Add(-time.Second), r.task(0))
+		if runs := r.wait(t, 6); !slices.Equal(runs, []int{0, 1, 2, 3, 4, 5}) {
+			t.Fatalf("expected the tasks to run in order of deadline, got %v", runs)
+		}
+		if n := s.Pending(); n != 0 {
+			t.Fatalf("expected no pending task, got %d", n)
+		}
+	})
+}
+
+func TestSchedulerFIFO(t *testing.T) {
+	s := New()
+	defer s.Stop()
+	r := newRecorder()
+	at := time.Now().Add(20 * time.Millisecond)
+	for i := 0; i < 20; i++ {
+		s.Schedule(at, r.task(i))
+	}
+	runs := r.wait(t, 20)
+	for i, run := range runs {
+		if run != i {
+			t.Fatalf("expected tasks due at the same time to run in order, got %v", runs)
+		}
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	forEachQueue(t, func(t *testing.T, s *Scheduler) {
+		r := newRecorder()
+		now := time.Now()
+		a := s.Schedule(now.Add(20*time.Millisecond), r.task(1))
+		s.Schedule(now.Add(40*time.Millisecond), r.task(2))
+		if !s.Cancel(a) {
+			t.Fatal("Cancel should cancel a pending task")
+		}
+		if s.Cancel(a) || s.Cancel(Handle{}) {
+			t.Fatal("Cancel should report tasks which are not pending")
+		}
+		if runs := r.wait(t, 1); !slices.Equal(runs, []int{2}) {
+			t.Fatalf("expected only the second task to run, got %v", runs)
+		}
+		if s.Reschedule(a, now) {
+			t.Fatal("Reschedule should not schedule a cancelled task again")
+		}
+	})
+}
+
+func TestSchedulerReschedule(t *testing.T) {
+	forEachQueue(t, func(t *testing.T, s *Scheduler) {
+		r := newRecorder()
+		now := time.Now()
+		a := s.Schedule(now.Add(time.Hour), r.task(1))
+		b := s.Schedule(now.Add(10*time.Millisecond), r.task(2))
+		s.Schedule(now.Add(30*time.Millisecond), r.task(3))
+		// moving a task earlier wakes the scheduler up, and later delays it
+		if !s.Reschedule(a, now.Add(20*time.Millisecond)) || !s.Reschedule(b, now.Add(40*time.Millisecond)) {
+			t.Fatal("Reschedule should move pending tasks")
+		}
+		if runs := r.wait(t, 3); !slices.Equal(runs, []int{1, 3, 2}) {
+			t.Fatalf("expected the rescheduled order, got %v", runs)
+		}
+		if s.Reschedule(a, now) {
+			t.Fatal("Reschedule should not schedule a task which has run")
+		}
+	})
+}
+
+func TestSchedulerPast(t *testing.T) {
+	forEachQueue(t, func(t *testing.T, s *Scheduler) {
+		r := newRecorder()
+		now := time.Now()
+		s.Schedule(now, r.task(1))
+		r.wait(t, 1)
+		// deadlines before the one of the task run, which a monotone queue
+		// rejects, run at once as well
+		s.Schedule(now.Add(-time.Second), r.task(2))
+		h := s.Schedule(now.Add(time.Hour), r.task(3))
+		s.Reschedule(h, now.Add(-time.Hour))
+		if runs := r.wait(t, 2); !slices.Equal(runs, []int{1, 2, 3}) && !slices.Equal(runs, []int{1, 3, 2}) {
+			t.Fatalf("expected the past tasks to run, got %v", runs)
+		}
+	})
+}
+
+func TestSchedulerStop(t *testing.T) {
+	s := New()
+	r := newRecorder()
+	s.Schedule(time.Now().Add(10*time.Millisecond), r.task(1))
+	s.Stop()
+	s.Stop()
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case <-r.done:
+		t.Fatal("a pending task should not run after Stop")
+	default:
+	}
+	if n := s.Pending(); n != 1 {
+		t.Fatalf("expected 1 pending task, got %d", n)
+	}
+}
+
+func TestWithQueueMisuse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithQueue should panic on a non-empty queue")
+		}
+	}()
+	h := &binaryheap.Heap[time.Duration, *Task]{}
+	h.Insert(0, &Task{})
+	WithQueue(h)
+}
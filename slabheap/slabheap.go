@@ -0,0 +1,349 @@
+// Package slabheap implements a Fibonacci heap whose elements are stored in
+// contiguous slabs. The API and the amortized bounds are those of package
+// fibheap, but the elements are allocated a slab at a time, and the trees are
+// linked by 32-bit slot indices instead of pointers. This halves the size of the
+// links, keeps elements inserted together close in memory, and lets the heap
+// reuse the slots of removed elements, so that a long-running heap allocates
+// only when it grows.
+//
+// The price of the slab layout is the lifetime of the handles: a removed
+// element keeps its key and value until its slot is reused, which happens on
+// a later Insert. The element returned by ExtractMin must thus be read before
+// the next Insert into the heap.
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package slabheap
+
+import (
+	"cmp"
+	"math"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+const (
+	slabShift = 10
+	// slabSize is the number of elements per slab.
+	slabSize = 1 << slabShift
+	slabMask = slabSize - 1
+)
+
+// Element is an element of a slab heap. It lives in a slab of the heap, so
+// handles stay valid until the element is extracted or deleted, after which the
+// heap may reuse it for another element.
+type Element[K any, V any] struct {
+	// slot indices of the parent, the siblings and a child, where slot 0
+	// stands for none
+	p, l, r, child uint32
+	// store mark in the LSB
+	degree uint32
+	// slot of the element itself, 0 once it has been removed
+	index uint32
+	key   K
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// Heap represents the slab heap. The keys of a Heap are of an ordered type, and
+// its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order O
+	slabs [][]Element[K, V]
+	// used is the number of slots taken from the slabs so far, and free is
+	// the first slot of the list of reusable slots linked by r
+	used     uint32
+	free     uint32
+	min      uint32
+	elements int
+	// degree table of consolidate, kept between calls
+	table []uint32
+}
+
+var _ fibheap.PriorityQueue[int, any, *Element[int, any]] = (*Heap[int, any])(nil)
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("slabheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less}
+}
+
+// Size returns the number of elements in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.elements
+}
+
+// at returns the element in the slot i.
+func (h *HeapOf[K, V, O]) at(i uint32) *Element[K, V] {
+	return &h.slabs[i>>slabShift][i&slabMask]
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with amortized running time Θ(1)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	if h.elements == 0 {
+		h.mustOrder()
+	}
+	i := h.alloc()
+	e := h.at(i)
+	*e = Element[K, V]{l: i, r: i, index: i, key: key, Value: value}
+	h.elements++
+	if h.min == 0 {
+		h.min = i
+	} else {
+		h.splice(h.min, i)
+		if h.order.Less(key, h.at(h.min).key) {
+			h.min = i
+		}
+	}
+	return e
+}
+
+// alloc returns a free slot, reusing the slot of a removed element if there is
+// one.
+func (h *HeapOf[K, V, O]) alloc() uint32 {
+	if i := h.free; i != 0 {
+		h.free = h.at(i).r
+		return i
+	}
+	if h.used == 0 {
+		// slot 0 stands for none
+		h.used = 1
+	}
+	if h.used == math.MaxUint32 {
+		panic("slabheap: too many elements")
+	}
+	if int(h.used>>slabShift) == len(h.slabs) {
+		h.slabs = append(h.slabs, make([]Element[K, V], slabSize))
+	}
+	i := h.used
+	h.used++
+	return i
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1)
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	if h.min == 0 {
+		return nil
+	}
+	return h.at(h.min)
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with amortized
+// running time O(log n). The key and the value of the returned element remain
+// readable until the next Insert.
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	if h.min == 0 {
+		return nil
+	}
+	i := h.min
+	z := h.at(i)
+	if c := z.child; c != 0 {
+		for j := c; ; {
+			e := h.at(j)
+			e.p = 0
+			e.degree &^= 1
+			if j = e.r; j == c {
+				break
+			}
+		}
+		h.splice(i, c)
+		z.child = 0
+	}
+	if z.r == i {
+		h.min = 0
+	} else {
+		h.min = z.r
+		h.unlink(i)
+		h.consolidate()
+	}
+	h.elements--
+	z.index = 0
+	z.p = 0
+	z.degree = 0
+	z.r = h.free
+	h.free = i
+	return z
+}
+
+// Decreasing decreases the key of the element x with amortized running time
+// Θ(1). If the new key is larger or equal than the key of x, Decreasing does
+// nothing. Decreasing panics if x does not belong to the heap h.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	h.mustContain(x, "Decreasing")
+	if !h.order.Less(key, x.key) {
+		return
+	}
+	x.key = key
+	if p := x.p; p != 0 && h.order.Less(key, h.at(p).key) {
+		h.cut(x.index, p)
+		h.cascadingCut(p)
+	}
+	if h.order.Less(key, h.at(h.min).key) {
+		h.min = x.index
+	}
+}
+
+// Delete removes the element x from the heap h with amortized running time
+// O(log n). The element x acts as negative infinity: it is cut from its parent
+// and then extracted as the minimum. Delete panics if x does not belong to the
+// heap h.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	h.mustContain(x, "Delete")
+	if p := x.p; p != 0 {
+		h.cut(x.index, p)
+		h.cascadingCut(p)
+	}
+	h.min = x.index
+	h.ExtractMin()
+}
+
+// mustContain panics with a message naming the method if the element x does not
+// belong to the heap h.
+func (h *HeapOf[K, V, O]) mustContain(x *Element[K, V], method string) {
+	if x == nil || x.index == 0 || x.index >= h.used || h.at(x.index) != x {
+		panic("slabheap: " + method + " expects an element of the heap")
+	}
+}
+
+// splice joins the circular list starting at the slot j into the circular list
+// of the slot i, right after i.
+func (h *HeapOf[K, V, O]) splice(i, j uint32) {
+	x, y := h.at(i), h.at(j)
+	r, l := x.r, y.l
+	x.r = j
+	y.l = i
+	h.at(r).l = l
+	h.at(l).r = r
+}
+
+// unlink removes the slot i from its circular list.
+func (h *HeapOf[K, V, O]) unlink(i uint32) {
+	x := h.at(i)
+	h.at(x.l).r = x.r
+	h.at(x.r).l = x.l
+	x.l, x.r = i, i
+}
+
+// link removes the root y from the root list, and makes y a child of the root
+// x.
+func (h *HeapOf[K, V, O]) link(y, x uint32) {
+	h.unlink(y)
+	c, p := h.at(y), h.at(x)
+	c.p = x
+	c.degree &^= 1
+	if p.child == 0 {
+		p.child = y
+	} else {
+		h.splice(p.child, y)
+	}
+	p.degree += 2
+}
+
+// cut moves the slot i from the children of the slot p to the root list.
+func (h *HeapOf[K, V, O]) cut(i, p uint32) {
+	x, y := h.at(i), h.at(p)
+	if x.r == i {
+		y.child = 0
+	} else {
+		if y.child == i {
+			y.child = x.r
+		}
+		h.unlink(i)
+	}
+	y.degree -= 2
+	x.p = 0
+	x.degree &^= 1
+	h.splice(h.min, i)
+}
+
+// cascadingCut marks the slot i if it lost its first child, or cuts it and
+// continues with its parent if it lost its second one.
+func (h *HeapOf[K, V, O]) cascadingCut(i uint32) {
+	for {
+		x := h.at(i)
+		p := x.p
+		if p == 0 {
+			return
+		}
+		if x.degree&1 == 0 {
+			x.degree |= 1
+			return
+		}
+		h.cut(i, p)
+		i = p
+	}
+}
+
+// consolidate links the roots of equal degree until every root has a distinct
+// degree, and finds the new minimum.
+func (h *HeapOf[K, V, O]) consolidate() {
+	a := h.table
+	clear(a)
+	end := h.at(h.min).l
+	for w := h.min; ; {
+		next := h.at(w).r
+		x := w
+		d := h.at(x).degree >> 1
+		for ; int(d) < len(a) && a[d] != 0; d++ {
+			y := a[d]
+			if h.order.Less(h.at(y).key, h.at(x).key) {
+				x, y = y, x
+			}
+			h.link(y, x)
+			a[d] = 0
+		}
+		for int(d) >= len(a) {
+			a = append(a, 0)
+		}
+		a[d] = x
+		if w == end {
+			break
+		}
+		w = next
+	}
+	h.table = a
+	h.min = 0
+	for _, i := range a {
+		if i != 0 && (h.min == 0 || h.order.Less(h.at(i).key, h.at(h.min).key)) {
+			h.min = i
+		}
+	}
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !valid[K](h.order) {
+		panic("slabheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
+
+// valid reports whether the ordering o can compare keys, which the zero Func
+// cannot.
+func valid[K any, O fibheap.Order[K]](o O) bool {
+	f, ok := any(o).(fibheap.Func[K])
+	return !ok || f != nil
+}
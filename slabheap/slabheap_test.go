@@ -0,0 +1,162 @@
+package slabheap
+
+import (
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+	"github.com/ksw2000/go-fibheap/internal/testsuite"
+)
+
+// check verifies the links, the degrees, the heap order and the size of the
+// heap h.
+func check[K any, V any, O fibheap.Order[K]](t *testing.T, h *HeapOf[K, V, O]) {
+	t.Helper()
+	if h.min == 0 {
+		if h.elements != 0 {
+			t.Fatalf("empty root list with size %d", h.elements)
+		}
+		return
+	}
+	var count int
+	var walk func(first, parent uint32)
+	walk = func(first, parent uint32) {
+		for i := first; ; {
+			x := h.at(i)
+			count++
+			if x.index != i {
+				t.Fatalf("slot %d holds index %d", i, x.index)
+			}
+			if x.p != parent || h.at(x.r).l != i || h.at(x.l).r != i {
+				t.Fatalf("slot %d is badly linked", i)
+			}
+			if parent != 0 && h.order.Less(x.key, h.at(parent).key) {
+				t.Fatalf("slot %d is smaller than its parent", i)
+			}
+			if parent == 0 && h.order.Less(x.key, h.at(h.min).key) {
+				t.Fatalf("root %d is smaller than the minimum", i)
+			}
+			degree := 0
+			if c := x.child; c != 0 {
+				for j := c; ; {
+					degree++
+					if j = h.at(j).r; j == c {
+						break
+					}
+				}
+				walk(c, i)
+			}
+			if int(x.degree>>1) != degree {
+				t.Fatalf("slot %d has degree %d but %d children", i, x.degree>>1, degree)
+			}
+			if i = x.r; i == first {
+				break
+			}
+		}
+	}
+	walk(h.min, 0)
+	if count != h.elements {
+		t.Fatalf("counted %d elements instead of %d", count, h.elements)
+	}
+}
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if h.Min() != nil || h.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if h.Size() != 6 || h.Min().Key() != 1 {
+		t.Fatalf("expected 6 elements and minimum 1, got %d and %d", h.Size(), h.Min().Key())
+	}
+	for _, expected := range []int{1, 2, 3, 5, 8, 9} {
+		if x := h.ExtractMin(); x.Key() != expected {
+			t.Fatalf("expected %d, got %d", expected, x.Key())
+		}
+		check(t, h)
+	}
+}
+
+func TestHeapSlots(t *testing.T) {
+	h := &Heap[int, int]{}
+	// slot 0 is never used
+	for i := 0; i < 3*slabSize-1; i++ {
+		h.Insert(i, i)
+	}
+	if len(h.slabs) != 3 {
+		t.Fatalf("expected 3 slabs, got %d", len(h.slabs))
+	}
+	x := h.ExtractMin()
+	if x.Pair() != (fibheap.Pair[int, int]{Key: 0, Value: 0}) {
+		t.Fatalf("unexpected pair %v", x.Pair())
+	}
+	// the slot of the extracted element is reused without growing the slabs
+	if y := h.Insert(-1, -1); y != x {
+		t.Fatal("expected Insert to reuse the free slot")
+	}
+	if len(h.slabs) != 3 {
+		t.Fatalf("expected 3 slabs, got %d", len(h.slabs))
+	}
+	check(t, h)
+}
+
+func TestHeapMisuse(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	y := g.Insert(2, nil)
+	g.Insert(0, nil)
+	h.Insert(3, nil)
+	h.ExtractMin()
+	for name, fn := range map[string]func(){
+		"Decreasing extracted": func() { h.Decreasing(x, 0) },
+		"Delete extracted":     func() { h.Delete(x) },
+		"Delete foreign":       func() { h.Delete(y) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestHeapRandom(t *testing.T) {
+	h := NewHeapFunc[int, int](func(a, b int) bool { return a < b })
+	testsuite.Random(t, h, func(h *HeapFunc[int, int]) error {
+		check(t, h)
+		return nil
+	})
+}
+
+func TestHeapConformance(t *testing.T) {
+	// check reports to the test given, so the structure is only checked by
+	// TestHeapRandom
+	testsuite.Conformance(t, func() *Heap[int, int] { return &Heap[int, int]{} }, nil)
+}
+
+func BenchmarkInsertExtract(b *testing.B) {
+	b.Run("slabheap", func(b *testing.B) {
+		benchmarkInsertExtract[*Element[int, int]](b, &Heap[int, int]{})
+	})
+	b.Run("fibheap", func(b *testing.B) {
+		benchmarkInsertExtract[*fibheap.Element[int, int]](b, &fibheap.Heap[int, int]{})
+	})
+}
+
+func benchmarkInsertExtract[E fibheap.Handle[int, int]](b *testing.B, q fibheap.PriorityQueue[int, int, E]) {
+	const n = 100000
+	for i := 0; i < n; i++ {
+		q.Insert((i*7919)%n, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := q.ExtractMin().Key()
+		q.Insert(k+n, i)
+	}
+}
@@ -0,0 +1,70 @@
+package softheap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Select returns the k-th smallest key of s, counting from 0, with running time
+// O(n). The keys of s are reordered. Select panics if k is out of the range of
+// s.
+//
+// Select partitions s around a pivot found with a soft heap of error rate 1/3:
+// extracting a third of the keys yields a pivot which has at least a third of
+// the keys below or at it, and at most a third of the keys above it are
+// corrupted, so that every partition discards a constant fraction of the keys.
+// The constant factors of the soft heap are large, however, and sorting s is
+// faster in practice.
+func Select[K cmp.Ordered](s []K, k int) K {
+	if k < 0 || k >= len(s) {
+		panic("softheap: Select expects an index in the range of the keys")
+	}
+	for {
+		if len(s) <= 32 {
+			slices.Sort(s)
+			return s[k]
+		}
+		pivot := approxMedian(s)
+		// s[:i] < pivot, s[i:j] == pivot, s[j:] > pivot
+		i, j, n := 0, 0, len(s)
+		for j < n {
+			switch c := cmp.Compare(s[j], pivot); {
+			case c < 0:
+				s[i], s[j] = s[j], s[i]
+				i++
+				j++
+			case c > 0:
+				n--
+				s[j], s[n] = s[n], s[j]
+			default:
+				j++
+			}
+		}
+		switch {
+		case k < i:
+			s = s[:i]
+		case k < j:
+			return pivot
+		default:
+			s = s[j:]
+			k -= j
+		}
+	}
+}
+
+// approxMedian returns a key of s whose rank is between a third and two thirds
+// of the length of s.
+func approxMedian[K cmp.Ordered](s []K) K {
+	h := NewHeap[K, struct{}](1.0 / 3)
+	for _, key := range s {
+		h.Insert(key, struct{}{})
+	}
+	e, _ := h.ExtractMin()
+	pivot := e.Key()
+	for i := len(s) / 3; i > 1; i-- {
+		if e, _ = h.ExtractMin(); cmp.Less(pivot, e.Key()) {
+			pivot = e.Key()
+		}
+	}
+	return pivot
+}
@@ -0,0 +1,49 @@
+package softheap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 10, 33, 100, 1000, 10000} {
+		s := make([]int, n)
+		for i := range s {
+			// few distinct keys exercise the partition around equal keys
+			s[i] = r.Intn(n/2 + 1)
+		}
+		sorted := slices.Sorted(slices.Values(s))
+		for _, k := range []int{0, n / 3, n / 2, n - 1} {
+			if got := Select(slices.Clone(s), k); got != sorted[k] {
+				t.Fatalf("n=%d: expected key %d at %d, got %d", n, sorted[k], k, got)
+			}
+		}
+	}
+	s := []float64{3, math.NaN(), 1, 2}
+	if got := Select(s, 0); !math.IsNaN(got) {
+		t.Fatalf("expected NaN first, got %v", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an index out of range")
+		}
+	}()
+	Select([]int{1}, 1)
+}
+
+func BenchmarkSelect(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	s := make([]int, 1<<16)
+	for i := range s {
+		s[i] = r.Int()
+	}
+	c := make([]int, len(s))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(c, s)
+		Select(c, len(c)/2)
+	}
+}
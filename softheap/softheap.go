@@ -0,0 +1,329 @@
+// Package softheap implements Chazelle's soft heap, in the simplified form of
+// Kaplan and Zwick. A soft heap trades exactness for speed: with an error rate
+// ε, every operation runs in amortized O(1) time, except Insert which runs in
+// amortized O(log 1/ε), but the heap may corrupt keys by raising them. At any
+// time, at most εn of the items in the heap are corrupted, where n is the number
+// of insertions so far. ExtractMin returns an item whose current key is the
+// smallest one, together with that current key, which is larger than the key of
+// the item if the item is corrupted.
+//
+// The corruption bound is what makes soft heaps useful: Select finds the k-th
+// smallest key in linear time, and soft heaps are at the heart of the fastest
+// known deterministic minimum spanning tree algorithms.
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc.
+package softheap
+
+import (
+	"cmp"
+	"math"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Item is an item of a soft heap.
+type Item[K any, V any] struct {
+	key K
+	// The value stored with this item.
+	Value V
+	next  *Item[K, V]
+}
+
+// Key returns the key the item e was inserted with, which is never corrupted.
+func (e *Item[K, V]) Key() K {
+	return e.key
+}
+
+// node is a node of a tree of the heap. It holds a list of items whose current
+// key is ckey, which is at most the current key of its children.
+type node[K any, V any] struct {
+	ckey        K
+	rank, size  int
+	left, right *node[K, V]
+	first, last *Item[K, V]
+	count       int
+}
+
+// tree is a tree in the list of trees of the heap, which is sorted by rank.
+type tree[K any, V any] struct {
+	root       *node[K, V]
+	prev, next *tree[K, V]
+	// tree of the smallest root among this tree and the following ones
+	sufmin *tree[K, V]
+}
+
+// Heap represents the soft heap. The keys of a Heap are of an ordered type. Its
+// zero value is an empty heap with an error rate of zero, which corrupts no key.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order O
+	// nodes of rank larger than r may hold several items, and thus corrupt
+	// keys; r is 0 for an error rate of zero
+	r     int
+	first *tree[K, V]
+	// rank of the last tree
+	rank  int
+	items int
+}
+
+// NewHeap returns an empty heap with the error rate epsilon, which must be
+// between 0 and 1. An error rate of zero makes an exact heap.
+func NewHeap[K cmp.Ordered, V any](epsilon float64) *Heap[K, V] {
+	return &Heap[K, V]{r: threshold(epsilon, "NewHeap")}
+}
+
+// NewHeapFunc returns an empty heap with the error rate epsilon which orders
+// keys with less. The function less must report whether a is strictly smaller
+// than b, and define a strict weak ordering. An error rate of zero makes an
+// exact heap.
+func NewHeapFunc[K any, V any](epsilon float64, less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("softheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less, r: threshold(epsilon, "NewHeapFunc")}
+}
+
+// threshold returns the rank above which nodes may hold several items for the
+// error rate epsilon, 0 if no node may.
+func threshold(epsilon float64, method string) int {
+	if !(epsilon >= 0 && epsilon <= 1) {
+		panic("softheap: " + method + " expects an error rate in [0, 1]")
+	}
+	if epsilon == 0 {
+		return 0
+	}
+	return int(math.Ceil(math.Log2(1/epsilon))) + 5
+}
+
+// Size returns the number of items in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.items
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted item with amortized running time O(log 1/ε)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Item[K, V] {
+	if h.first == nil {
+		h.mustOrder()
+	}
+	e := &Item[K, V]{key: key, Value: value}
+	t := &tree[K, V]{root: &node[K, V]{ckey: key, size: 1, first: e, last: e, count: 1}}
+	t.sufmin = t
+	h.items++
+	if h.first == nil {
+		h.first = t
+		return e
+	}
+	t.next = h.first
+	h.first.prev = t
+	h.first = t
+	h.repeatedCombine(0)
+	return e
+}
+
+// Min returns an item of the smallest current key in the heap h, and its
+// current key, with running time Θ(1). It returns a nil item if h is empty.
+func (h *HeapOf[K, V, O]) Min() (*Item[K, V], K) {
+	if h.first == nil {
+		var zero K
+		return nil, zero
+	}
+	x := h.first.sufmin.root
+	return x.first, x.ckey
+}
+
+// ExtractMin removes an item of the smallest current key from the heap h, and
+// returns it with its current key, with amortized running time O(1). The item
+// is corrupted if its key is smaller than its current key. ExtractMin returns
+// a nil item if h is empty.
+func (h *HeapOf[K, V, O]) ExtractMin() (*Item[K, V], K) {
+	if h.first == nil {
+		var zero K
+		return nil, zero
+	}
+	t := h.first.sufmin
+	x := t.root
+	e, ckey := x.first, x.ckey
+	if x.first = e.next; x.first == nil {
+		x.last = nil
+	}
+	e.next = nil
+	x.count--
+	h.items--
+	if 2*x.count <= x.size {
+		if !x.leaf() {
+			h.sift(x)
+			h.updateSuffixMin(t)
+		} else if x.count == 0 {
+			h.remove(t)
+		}
+	}
+	return e, ckey
+}
+
+// Meld moves all the items of the heap g into the heap h, leaving g empty, with
+// amortized running time O(1). Meld panics if g and h have different error
+// rates.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if g.r != h.r {
+		panic("softheap: Meld expects a heap with the same error rate")
+	}
+	if g == h || g.first == nil {
+		return
+	}
+	if h.first == nil {
+		h.mustOrder()
+	}
+	items := h.items + g.items
+	if h.first != nil && g.rank > h.rank {
+		h.first, g.first = g.first, h.first
+		h.rank, g.rank = g.rank, h.rank
+	}
+	if h.first == nil {
+		h.first, h.rank = g.first, g.rank
+	} else {
+		h.mergeInto(g.first)
+		h.repeatedCombine(g.rank)
+	}
+	h.items = items
+	g.first, g.rank, g.items = nil, 0, 0
+}
+
+// mergeInto inserts the trees of the list starting at t into the list of trees
+// of the heap h, keeping it sorted by rank. The last tree of t must not have a
+// larger rank than the last tree of h.
+func (h *HeapOf[K, V, O]) mergeInto(t *tree[K, V]) {
+	u := h.first
+	for t != nil {
+		for t.root.rank > u.root.rank {
+			u = u.next
+		}
+		next := t.next
+		t.prev, t.next = u.prev, u
+		if u.prev == nil {
+			h.first = t
+		} else {
+			u.prev.next = t
+		}
+		u.prev = t
+		t = next
+	}
+}
+
+// repeatedCombine combines the trees of equal rank of the heap h, which come in
+// runs of at most three after a meld, until no two trees have the same rank.
+// Trees of ranks larger than k are distinct and stop the combination.
+func (h *HeapOf[K, V, O]) repeatedCombine(k int) {
+	t := h.first
+	for t.next != nil {
+		if t.root.rank == t.next.root.rank {
+			if u := t.next.next; u == nil || u.root.rank != t.root.rank {
+				t.root = h.combine(t.root, t.next.root)
+				h.removeLink(t.next)
+				// t may have the rank of the next tree now
+				continue
+			}
+		} else if t.root.rank > k {
+			break
+		}
+		t = t.next
+	}
+	if t.root.rank > h.rank {
+		h.rank = t.root.rank
+	}
+	h.updateSuffixMin(t)
+}
+
+// combine returns a new root whose children are the roots x and y of equal
+// rank.
+func (h *HeapOf[K, V, O]) combine(x, y *node[K, V]) *node[K, V] {
+	z := &node[K, V]{left: x, right: y, rank: x.rank + 1, size: 1}
+	if h.r != 0 && z.rank > h.r {
+		z.size = (3*x.size + 1) / 2
+	}
+	h.sift(z)
+	return z
+}
+
+// sift refills the list of items of the node x from its children, until it
+// holds as many items as its size or x has no children left. The current key
+// of x rises to the current key of the child it takes items from.
+func (h *HeapOf[K, V, O]) sift(x *node[K, V]) {
+	for x.count < x.size && !x.leaf() {
+		if x.left == nil || (x.right != nil && h.order.Less(x.right.ckey, x.left.ckey)) {
+			x.left, x.right = x.right, x.left
+		}
+		y := x.left
+		if x.first == nil {
+			x.first = y.first
+		} else {
+			x.last.next = y.first
+		}
+		x.last = y.last
+		x.count += y.count
+		x.ckey = y.ckey
+		y.first, y.last, y.count = nil, nil, 0
+		if y.leaf() {
+			x.left = nil
+		} else {
+			h.sift(y)
+		}
+	}
+}
+
+// leaf reports whether the node x has no children.
+func (x *node[K, V]) leaf() bool {
+	return x.left == nil && x.right == nil
+}
+
+// updateSuffixMin updates the suffix minimum of the tree t and the trees
+// before it.
+func (h *HeapOf[K, V, O]) updateSuffixMin(t *tree[K, V]) {
+	for ; t != nil; t = t.prev {
+		if t.next == nil || !h.order.Less(t.next.sufmin.root.ckey, t.root.ckey) {
+			t.sufmin = t
+		} else {
+			t.sufmin = t.next.sufmin
+		}
+	}
+}
+
+// remove removes the tree t from the heap h.
+func (h *HeapOf[K, V, O]) remove(t *tree[K, V]) {
+	h.removeLink(t)
+	if t.next == nil {
+		h.rank = 0
+		if t.prev != nil {
+			h.rank = t.prev.root.rank
+		}
+	}
+	h.updateSuffixMin(t.prev)
+}
+
+// removeLink unlinks the tree t from the list of trees of the heap h.
+func (h *HeapOf[K, V, O]) removeLink(t *tree[K, V]) {
+	if t.prev == nil {
+		h.first = t.next
+	} else {
+		t.prev.next = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if f, ok := any(h.order).(fibheap.Func[K]); ok && f == nil {
+		panic("softheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
@@ -0,0 +1,203 @@
+package softheap
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// check verifies the list of trees, the suffix minima, the order of the current
+// keys and the size of the heap h, and returns the number of corrupted items in
+// h.
+func check[K any, V any, O fibheap.Order[K]](t *testing.T, h *HeapOf[K, V, O]) int {
+	t.Helper()
+	items, corrupted := 0, 0
+	var walk func(x *node[K, V])
+	walk = func(x *node[K, V]) {
+		count := 0
+		for e := x.first; e != nil; e = e.next {
+			count++
+			if h.order.Less(x.ckey, e.key) {
+				t.Fatal("an item has a larger key than its current key")
+			}
+			if h.order.Less(e.key, x.ckey) {
+				corrupted++
+			}
+			if e.next == nil && e != x.last {
+				t.Fatal("a list of items has a wrong last item")
+			}
+		}
+		if count != x.count {
+			t.Fatalf("a node counts %d items instead of %d", x.count, count)
+		}
+		items += count
+		for _, c := range []*node[K, V]{x.left, x.right} {
+			if c == nil {
+				continue
+			}
+			if c.rank >= x.rank {
+				t.Fatalf("a child has rank %d under a node of rank %d", c.rank, x.rank)
+			}
+			if h.order.Less(c.ckey, x.ckey) {
+				t.Fatal("a child has a smaller current key than its parent")
+			}
+			if c.count == 0 {
+				t.Fatal("a child has no items")
+			}
+			walk(c)
+		}
+	}
+	var prev *tree[K, V]
+	for u := h.first; u != nil; prev, u = u, u.next {
+		if u.prev != prev {
+			t.Fatal("a tree is badly linked")
+		}
+		if prev != nil && prev.root.rank >= u.root.rank {
+			t.Fatalf("a tree of rank %d follows a tree of rank %d", u.root.rank, prev.root.rank)
+		}
+		if u.root.count == 0 {
+			t.Fatal("a root has no items")
+		}
+		min := u
+		for v := u.next; v != nil; v = v.next {
+			if h.order.Less(v.root.ckey, min.root.ckey) {
+				min = v
+			}
+		}
+		if h.order.Less(min.root.ckey, u.sufmin.root.ckey) {
+			t.Fatal("a suffix minimum is not the smallest root")
+		}
+		walk(u.root)
+	}
+	if prev != nil && prev.root.rank != h.rank {
+		t.Fatalf("the last tree has rank %d, but the heap has rank %d", prev.root.rank, h.rank)
+	}
+	if items != h.items {
+		t.Fatalf("counted %d items instead of %d", items, h.items)
+	}
+	return corrupted
+}
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if e, _ := h.Min(); e != nil {
+		t.Fatal("expected an empty heap")
+	}
+	if e, _ := h.ExtractMin(); e != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if e, key := h.Min(); h.Size() != 6 || e.Key() != 1 || key != 1 {
+		t.Fatalf("expected 6 items and minimum 1, got %d and %d", h.Size(), key)
+	}
+	for _, expected := range []int{1, 2, 3, 5, 8, 9} {
+		if e, key := h.ExtractMin(); e.Key() != expected || key != expected {
+			t.Fatalf("expected %d, got %d with current key %d", expected, e.Key(), key)
+		}
+		check(t, h)
+	}
+}
+
+func TestHeapExact(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	var model []int
+	for i := 0; i < 10000; i++ {
+		if r.Intn(3) > 0 || len(model) == 0 {
+			k := r.Intn(1000)
+			h.Insert(k, i)
+			model = append(model, k)
+			continue
+		}
+		min := slices.Min(model)
+		if e, key := h.ExtractMin(); e.Key() != min || key != min {
+			t.Fatalf("operation %d: expected %d, got %d with current key %d", i, min, e.Key(), key)
+		}
+		model = slices.Delete(model, slices.Index(model, min), slices.Index(model, min)+1)
+		if i%100 == 0 && check(t, h) != 0 {
+			t.Fatalf("operation %d: an exact heap corrupted keys", i)
+		}
+	}
+}
+
+func TestHeapCorruption(t *testing.T) {
+	for _, epsilon := range []float64{1, 0.5, 0.1, 0.01} {
+		r := rand.New(rand.NewSource(1))
+		h := NewHeap[int, int](epsilon)
+		g := NewHeap[int, int](epsilon)
+		inserts := 0
+		var last int
+		for i := 0; i < 10000; i++ {
+			switch op := r.Intn(10); {
+			case op < 6 || h.Size() == 0:
+				h.Insert(r.Intn(1<<20), i)
+				inserts++
+				last = math.MinInt
+			case op < 7:
+				for n := r.Intn(100); n > 0; n-- {
+					g.Insert(r.Intn(1<<20), i)
+					inserts++
+				}
+				if r.Intn(2) == 0 {
+					h.Meld(g)
+				} else {
+					g.Meld(h)
+					h, g = g, h
+				}
+				last = math.MinInt
+			default:
+				e, key := h.ExtractMin()
+				if key < e.Key() {
+					t.Fatalf("ε=%v, operation %d: current key %d is smaller than the key %d", epsilon, i, key, e.Key())
+				}
+				// without insertions, current keys are extracted in order
+				if key < last {
+					t.Fatalf("ε=%v, operation %d: extracted %d after %d", epsilon, i, key, last)
+				}
+				last = key
+			}
+			if i%250 == 0 {
+				if c := check(t, h); float64(c) > epsilon*float64(inserts) {
+					t.Fatalf("ε=%v, operation %d: %d corrupted items after %d insertions", epsilon, i, c, inserts)
+				}
+			}
+		}
+	}
+}
+
+func TestHeapFunc(t *testing.T) {
+	h := NewHeapFunc[string, int](0.25, func(a, b string) bool { return len(a) < len(b) })
+	for _, s := range []string{"ccc", "a", "bb"} {
+		h.Insert(s, len(s))
+	}
+	if e, _ := h.ExtractMin(); e.Key() != "a" || e.Value != 1 {
+		t.Fatalf("expected a, got %s", e.Key())
+	}
+	check(t, h)
+}
+
+func TestHeapMisuse(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"NewHeap negative":    func() { NewHeap[int, int](-0.5) },
+		"NewHeap NaN":         func() { NewHeap[int, int](math.NaN()) },
+		"NewHeapFunc large":   func() { NewHeapFunc[int, int](2, cmp.Less[int]) },
+		"NewHeapFunc nil":     func() { NewHeapFunc[int, int](0.5, nil) },
+		"zero HeapFunc":       func() { (&HeapFunc[int, int]{}).Insert(1, 1) },
+		"Meld different rate": func() { NewHeap[int, int](0.5).Meld(NewHeap[int, int](0.1)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
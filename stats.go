@@ -0,0 +1,70 @@
+package fibheap
+
+// Stats describes the shape of a heap at the time it was taken.
+type Stats struct {
+	// Size is the number of elements in the heap.
+	Size int
+	// Roots is the length of the root list, that is, the number of trees.
+	Roots int
+	// MaxDegree is the largest number of children of a single element.
+	MaxDegree int
+	// Marked is the number of marked elements, which lost a child since they
+	// became the child of another element.
+	Marked int
+	// Height is the number of levels of the highest tree, or zero for an
+	// empty heap.
+	Height int
+	// Depths is the histogram of the depths of the elements in their trees:
+	// Depths[d] is the number of elements at depth d, where roots are at
+	// depth zero. Its length is Height. Tombstones are counted as elements.
+	Depths []int
+	// Suspended and Pinned are the numbers of elements held out of the trees
+	// by Suspend and Pin.
+	Suspended int
+	Pinned    int
+	// Tombstones is the number of lazily deleted elements still in the trees.
+	Tombstones int
+}
+
+// Stats returns statistics about the shape of the heap h with running time
+// Θ(n). A long root list announces an expensive consolidation at the next
+// ExtractMin, while the degrees and the height stay logarithmic in the size of
+// the heap.
+func (h *HeapOf[K, V, O]) Stats() Stats {
+	if h == nil {
+		// a nil heap is empty
+		h = &HeapOf[K, V, O]{}
+	}
+	return h.stats(nil)
+}
+
+// stats returns the statistics of the heap h, calling visit, if not nil, with
+// every element in its trees, including tombstones.
+func (h *HeapOf[K, V, O]) stats(visit func(e *Element[K, V])) Stats {
+	s := Stats{
+		Size:       h.elements,
+		Suspended:  len(h.suspended),
+		Pinned:     len(h.pinned),
+		Tombstones: h.tombstones,
+	}
+	walk(h.min, 0, func(e *Element[K, V], depth int) {
+		if depth == len(s.Depths) {
+			s.Depths = append(s.Depths, 0)
+		}
+		s.Depths[depth]++
+		if e.getMark() {
+			s.Marked++
+		}
+		if d := e.getDegree(); d > s.MaxDegree {
+			s.MaxDegree = d
+		}
+		if visit != nil {
+			visit(e)
+		}
+	})
+	s.Height = len(s.Depths)
+	if s.Height > 0 {
+		s.Roots = s.Depths[0]
+	}
+	return s
+}
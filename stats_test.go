@@ -0,0 +1,48 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapStats(t *testing.T) {
+	h := &Heap[int, int]{}
+	s := h.Stats()
+	assert(t, s.Size, 0)
+	assert(t, s.Roots, 0)
+	assert(t, s.Height, 0)
+
+	elements := make([]*Element[int, int], 65)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	s = h.Stats()
+	assert(t, s.Roots, 65)
+	assert(t, s.Height, 1)
+
+	// consolidating 64 elements builds a single binomial tree of degree 6
+	h.ExtractMin()
+	s = h.Stats()
+	assert(t, s.Size, 64)
+	assert(t, s.Roots, 1)
+	assert(t, s.MaxDegree, 6)
+	assert(t, s.Height, 7)
+	assert(t, s.Marked, 0)
+	for d, n := range []int{1, 6, 15, 20, 15, 6, 1} {
+		assert(t, s.Depths[d], n)
+	}
+
+	// cutting a grandchild marks its parent
+	var leaf *Element[int, int]
+	for _, e := range elements[1:] {
+		if e.p != nil && e.p.p != nil && e.children == nil {
+			leaf = e
+			break
+		}
+	}
+	h.Decreasing(leaf, -1)
+	h.Pin(elements[2])
+	s = h.Stats()
+	assert(t, s.Marked, 1)
+	assert(t, s.Pinned, 1)
+	assert(t, s.Roots, 2)
+}
@@ -0,0 +1,733 @@
+// Package strictfibheap implements a strict Fibonacci heap, after Brodal,
+// Lagogiannis and Tarjan, "Strict Fibonacci Heaps" (STOC 2012). It has the
+// same API as the Fibonacci heap of package fibheap, but its bounds are
+// worst-case rather than amortized, so that no single operation pays for a
+// long sequence of cheap ones:
+// fetching the minimum is Θ(1),
+// inserting is Θ(1),
+// decreasing a key is Θ(1),
+// melding is Θ(1),
+// extracting the minimum and deleting are O(log n).
+// The constant factors are larger than those of package fibheap, which remains
+// faster in total for most workloads.
+//
+// Keys of ordered types are compared as cmp.Compare does, or with a custom
+// comparison function given to NewHeapFunc. Elements with equal keys are
+// extracted in insertion order.
+package strictfibheap
+
+import (
+	"cmp"
+	"sync/atomic"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// seq numbers the elements of all heaps, so that elements with equal keys are
+// totally ordered even across melded heaps.
+var seq atomic.Uint64
+
+// Element is an element of a strict Fibonacci heap. Elements keep their
+// identity while the heap moves them between the nodes of its tree, so they
+// remain valid handles until they are extracted or deleted.
+type Element[K any, V any] struct {
+	n   *node[K, V]
+	seq uint64
+	key K
+	// The value stored with this element.
+	Value V
+}
+
+// Key returns the key of the element e
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Pair returns the key and the value of the element e
+func (e *Element[K, V]) Pair() fibheap.Pair[K, V] {
+	return fibheap.Pair[K, V]{Key: e.key, Value: e.Value}
+}
+
+// activeRecord is shared by the active nodes of a heap, so that melding makes
+// every node of a heap passive at once by clearing active.
+type activeRecord struct {
+	active bool
+}
+
+// passive is the record of nodes which are passive on their own.
+var passive = &activeRecord{}
+
+// The fix-list an active node is in.
+const (
+	fixNone uint8 = iota
+	// fixRoot holds active roots, by rank.
+	fixRoot
+	// fixLoss1 holds active nodes with loss 1, by rank.
+	fixLoss1
+	// fixLoss2 holds active nodes with loss 2 or more.
+	fixLoss2
+)
+
+// link links an element into an intrusive circular list.
+type link[T any] struct {
+	prev, next *T
+}
+
+// node is a node of the tree of a heap. The children of a node are kept in a
+// circular list, active ones before passive ones. For the root, passive
+// linkable children come last.
+type node[K any, V any] struct {
+	e      *Element[K, V]
+	parent *node[K, V]
+	left   *node[K, V]
+	right  *node[K, V]
+	child  *node[K, V]
+	degree int
+	active *activeRecord
+	// the rank and the loss of an active node
+	rank    *rankRecord[K, V]
+	loss    int
+	fixKind uint8
+	fix     link[node[K, V]]
+	// the position of a nonroot node in the queue of the heap
+	queue link[node[K, V]]
+}
+
+func fixAt[K any, V any](x *node[K, V]) *link[node[K, V]]   { return &x.fix }
+func queueAt[K any, V any](x *node[K, V]) *link[node[K, V]] { return &x.queue }
+
+// rankRecord is shared by the active nodes of equal rank. The records of a heap
+// form a list in increasing rank, which is extended on demand.
+type rankRecord[K any, V any] struct {
+	rank int
+	prev *rankRecord[K, V]
+	next *rankRecord[K, V]
+	// active roots and active nodes with loss 1 of this rank
+	roots, loss1   *node[K, V]
+	nroots, nloss1 int
+	// position in the lists of ranks shared by several active roots, or by
+	// several nodes with loss 1
+	rootPair, lossPair link[rankRecord[K, V]]
+}
+
+func rootPairAt[K any, V any](r *rankRecord[K, V]) *link[rankRecord[K, V]] { return &r.rootPair }
+func lossPairAt[K any, V any](r *rankRecord[K, V]) *link[rankRecord[K, V]] { return &r.lossPair }
+
+// Heap represents the strict Fibonacci heap. The keys of a Heap are of an
+// ordered type, and its zero value is an empty heap.
+type Heap[K cmp.Ordered, V any] = HeapOf[K, V, fibheap.Ordered[K]]
+
+// HeapFunc is a Heap whose keys are ordered by a comparison function. It is
+// created by NewHeapFunc.
+type HeapFunc[K any, V any] = HeapOf[K, V, fibheap.Func[K]]
+
+// HeapOf is a Heap whose keys are ordered by O. It is used through its aliases
+// Heap and HeapFunc.
+type HeapOf[K any, V any, O fibheap.Order[K]] struct {
+	order    O
+	root     *node[K, V]
+	elements int
+	// rootPassive is the leftmost passive child of the root.
+	rootPassive *node[K, V]
+	// queue holds every nonroot node, and is rotated by ExtractMin.
+	queue  *node[K, V]
+	active *activeRecord
+	ranks  *rankRecord[K, V]
+	// rootPairs and lossPairs hold the ranks shared by several active roots,
+	// and by several nodes with loss 1.
+	rootPairs, lossPairs *rankRecord[K, V]
+	// loss2 holds the active nodes with loss 2 or more.
+	loss2 *node[K, V]
+}
+
+var _ fibheap.MeldableHeap[int, any, *Element[int, any], *Heap[int, any]] = (*Heap[int, any])(nil)
+
+// NewHeapFunc returns an empty heap which orders keys with less. The function
+// less must report whether a is strictly smaller than b, and define a strict
+// weak ordering.
+func NewHeapFunc[K any, V any](less func(a, b K) bool) *HeapFunc[K, V] {
+	if less == nil {
+		panic("strictfibheap: NewHeapFunc expects a non-nil less function")
+	}
+	return &HeapFunc[K, V]{order: less}
+}
+
+// Size returns the number of elements in the heap h
+func (h *HeapOf[K, V, O]) Size() int {
+	return h.elements
+}
+
+// Insert inserts the key-value pair (key, value) to the heap h and returns the
+// inserted element with worst-case running time Θ(1)
+func (h *HeapOf[K, V, O]) Insert(key K, value V) *Element[K, V] {
+	if h.elements == 0 {
+		h.mustOrder()
+	}
+	e := &Element[K, V]{seq: seq.Add(1), key: key, Value: value}
+	n := &node[K, V]{e: e, active: passive}
+	n.left, n.right = n, n
+	e.n = n
+	h.elements++
+	if h.root == nil {
+		h.root = n
+		return e
+	}
+	if h.before(n, h.root) {
+		n, h.root = h.root, n
+		h.rootPassive = nil
+	}
+	h.addRootChild(n)
+	ringAdd(&h.queue, n, queueAt)
+	h.activeRootReduction()
+	h.rootDegreeReduction()
+	return e
+}
+
+// Min fetches the minimum key from the heap h with running time Θ(1)
+func (h *HeapOf[K, V, O]) Min() *Element[K, V] {
+	if h.root == nil {
+		return nil
+	}
+	return h.root.e
+}
+
+// ExtractMin fetches and removes the minimum key from the heap h with
+// worst-case running time O(log n)
+func (h *HeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	old := h.root
+	if old == nil {
+		return nil
+	}
+	e := old.e
+	e.n = nil
+	h.elements--
+	if old.child == nil {
+		h.reset()
+		return e
+	}
+
+	// the child with the smallest key becomes the root, and adopts the other
+	// children of the old root
+	x := old.child
+	for c := x.right; c != old.child; c = c.right {
+		if h.before(c, x) {
+			x = c
+		}
+	}
+	children := make([]*node[K, V], 0, old.degree-1+x.degree)
+	for c := old.child; ; {
+		if c != x {
+			children = append(children, c)
+		}
+		if c = c.right; c == old.child {
+			break
+		}
+	}
+	if c := x.child; c != nil {
+		for {
+			children = append(children, c)
+			if isActive(c) {
+				// active children of x become active roots with loss zero
+				h.unfix(c)
+				c.loss = 0
+			}
+			if c = c.right; c == x.child {
+				break
+			}
+		}
+	}
+	if isActive(x) {
+		h.unfix(x)
+		x.active = passive
+	}
+	ringRemove(&h.queue, x, queueAt)
+	x.parent, x.left, x.right, x.child, x.degree = nil, x, x, nil, 0
+	h.root = x
+	h.rootPassive = nil
+	for _, c := range children {
+		h.addRootChild(c)
+		if isActive(c) && c.fixKind == fixNone {
+			h.refix(c)
+		}
+	}
+
+	// move passive children of the first nodes of the queue to the root, which
+	// bounds the degree of nonroot nodes
+	for i := 0; i < 2 && h.queue != nil; i++ {
+		y := h.queue
+		h.queue = y.queue.next
+		for j := 0; j < 2 && y.child != nil; j++ {
+			z := y.child.left
+			if isActive(z) {
+				break
+			}
+			h.detach(z)
+			h.addRootChild(z)
+		}
+	}
+
+	for h.lossReduction() {
+	}
+	for h.activeRootReduction() || h.rootDegreeReduction() {
+	}
+	return e
+}
+
+// Decreasing decreases the key of the element x with worst-case running time
+// Θ(1). If the new key is larger or equal than the key of x, Decreasing does
+// nothing. Decreasing panics if x was extracted or deleted.
+func (h *HeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	if x.n == nil {
+		panic("strictfibheap: Decreasing expects an element of the heap")
+	}
+	if !h.order.Less(key, x.key) {
+		return
+	}
+	x.key = key
+	h.decrease(x.n, false)
+}
+
+// Delete removes the element x from the heap h with worst-case running time
+// O(log n). Delete panics if x was extracted or deleted.
+func (h *HeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	if x.n == nil {
+		panic("strictfibheap: Delete expects an element of the heap")
+	}
+	h.decrease(x.n, true)
+	h.ExtractMin()
+}
+
+// Meld moves every element of the heap g into the heap h with worst-case
+// running time Θ(1), leaving g empty. The heap g must order keys in the same
+// way as the heap h.
+func (h *HeapOf[K, V, O]) Meld(g *HeapOf[K, V, O]) {
+	if h == nil || g == nil {
+		panic("strictfibheap: Meld expects non-nil heap h and g")
+	}
+	if h == g {
+		panic("strictfibheap: Meld expects two different heaps")
+	}
+	order := h.order
+	if !valid[K](order) {
+		order = g.order
+	}
+	if h.elements < g.elements {
+		*h, *g = *g, *h
+	}
+	h.order, g.order = order, order
+	if g.root == nil {
+		return
+	}
+
+	// the nodes of the smaller heap g become passive
+	if g.active != nil {
+		g.active.active = false
+	}
+	n := g.root
+	if h.before(n, h.root) {
+		// every child of the passive root of g is linkable
+		n, h.root = h.root, n
+		h.rootPassive = h.root.child
+	}
+	h.addRootChild(n)
+	ringAdd(&h.queue, n, queueAt)
+	h.queue = ringConcat(h.queue, g.queue, queueAt)
+	h.elements += g.elements
+	g.reset()
+	h.activeRootReduction()
+	h.rootDegreeReduction()
+}
+
+// Union unions the two strict Fibonacci heaps h and g, and returns the new heap
+// with worst-case running time Θ(1). The heap h and g will be reset after
+// unioning.
+func (h *HeapOf[K, V, O]) Union(g *HeapOf[K, V, O]) *HeapOf[K, V, O] {
+	if h == nil || g == nil {
+		panic("strictfibheap: Union expects non-nil heap h and g")
+	}
+	m := &HeapOf[K, V, O]{order: h.order}
+	m.Meld(h)
+	if g != h {
+		m.Meld(g)
+	}
+	return m
+}
+
+// reset empties the heap h, keeping its ordering.
+func (h *HeapOf[K, V, O]) reset() {
+	*h = HeapOf[K, V, O]{order: h.order}
+}
+
+// before reports whether the node a is ordered before the node b, that is, a
+// has a smaller key, or an equal key and an earlier insertion.
+func (h *HeapOf[K, V, O]) before(a, b *node[K, V]) bool {
+	if h.order.Less(a.e.key, b.e.key) {
+		return true
+	}
+	return a.e.seq < b.e.seq && !h.order.Less(b.e.key, a.e.key)
+}
+
+// decrease restores the heap order after the key of the node x was decreased.
+// If toRoot is set, the element of x is moved to the root as if its key were
+// negative infinity.
+func (h *HeapOf[K, V, O]) decrease(x *node[K, V], toRoot bool) {
+	if x == h.root {
+		return
+	}
+	if toRoot || h.before(x, h.root) {
+		// the element of x becomes the minimum, and x holds the old minimum
+		r := h.root
+		x.e, r.e = r.e, x.e
+		x.e.n, r.e.n = x, r
+	} else if !h.before(x, x.parent) {
+		return
+	}
+	if x.parent == h.root {
+		return
+	}
+	if isActive(x) {
+		h.unfix(x)
+		x.loss = 0
+	}
+	h.cut(x)
+	h.addRootChild(x)
+	if isActive(x) {
+		h.refix(x)
+	}
+	h.lossReduction()
+	for i := 0; i < 6 && h.activeRootReduction(); i++ {
+	}
+	for i := 0; i < 4 && h.rootDegreeReduction(); i++ {
+	}
+}
+
+// activeRootReduction links two active roots of equal rank, and reports whether
+// there were two.
+func (h *HeapOf[K, V, O]) activeRootReduction() bool {
+	r := h.rootPairs
+	if r == nil {
+		return false
+	}
+	x, y := r.roots, r.roots.fix.next
+	if h.before(y, x) {
+		x, y = y, x
+	}
+	h.unfix(x)
+	h.unfix(y)
+	p := y.parent
+	h.detach(y)
+	h.checkLinkable(p)
+	addChild(x, y, true)
+	x.rank = h.nextRank(x.rank)
+	h.refix(x)
+	// keep the degree of x by moving its rightmost passive child to the root
+	if z := x.child.left; !isActive(z) {
+		h.detach(z)
+		h.addRootChild(z)
+	}
+	return true
+}
+
+// rootDegreeReduction makes the three rightmost children of the root into an
+// active root of rank one if they are passive and linkable, and reports whether
+// they were.
+func (h *HeapOf[K, V, O]) rootDegreeReduction() bool {
+	if h.root.degree < 3 {
+		return false
+	}
+	x := h.root.child.left
+	y := x.left
+	z := y.left
+	if !linkable(x) || !linkable(y) || !linkable(z) {
+		return false
+	}
+	if h.before(y, x) {
+		x, y = y, x
+	}
+	if h.before(z, y) {
+		y, z = z, y
+	}
+	if h.before(y, x) {
+		x, y = y, x
+	}
+	h.detach(x)
+	h.detach(y)
+	h.detach(z)
+	if h.active == nil {
+		h.active = &activeRecord{active: true}
+	}
+	x.active, y.active = h.active, h.active
+	x.loss, y.loss = 0, 0
+	x.fixKind, y.fixKind = fixNone, fixNone
+	y.rank = h.rankZero()
+	x.rank = h.nextRank(y.rank)
+	addChild(y, z, false)
+	addChild(x, y, true)
+	h.addRootChild(x)
+	h.refix(x)
+	return true
+}
+
+// lossReduction moves an active node with loss 2 or more to the root, or links
+// two active nodes with loss 1 and equal rank, and reports whether it did
+// either.
+func (h *HeapOf[K, V, O]) lossReduction() bool {
+	if x := h.loss2; x != nil {
+		h.unfix(x)
+		x.loss = 0
+		h.cut(x)
+		h.addRootChild(x)
+		h.refix(x)
+		return true
+	}
+	r := h.lossPairs
+	if r == nil {
+		return false
+	}
+	x, y := r.loss1, r.loss1.fix.next
+	if h.before(y, x) {
+		x, y = y, x
+	}
+	h.unfix(y)
+	h.cut(y)
+	h.unfix(x)
+	addChild(x, y, true)
+	x.rank = h.nextRank(x.rank)
+	x.loss, y.loss = 0, 0
+	h.refix(x)
+	h.refix(y)
+	return true
+}
+
+// cut removes the nonroot node x from its parent p, which loses a child. An
+// active p loses rank, and loss too unless it is an active root.
+func (h *HeapOf[K, V, O]) cut(x *node[K, V]) {
+	p := x.parent
+	h.detach(x)
+	if !isActive(x) {
+		return
+	}
+	if !isActive(p) {
+		h.checkLinkable(p)
+		return
+	}
+	h.unfix(p)
+	p.rank = p.rank.prev
+	if isActive(p.parent) {
+		p.loss++
+	}
+	h.refix(p)
+}
+
+// checkLinkable moves the passive child p of the root to the end of the
+// children of the root if it became linkable.
+func (h *HeapOf[K, V, O]) checkLinkable(p *node[K, V]) {
+	if p.parent == h.root && linkable(p) {
+		h.detach(p)
+		h.addRootChild(p)
+	}
+}
+
+// addRootChild adds x as a child of the root, among the active, the passive or
+// the passive linkable children.
+func (h *HeapOf[K, V, O]) addRootChild(x *node[K, V]) {
+	r := h.root
+	switch {
+	case isActive(x):
+		addChild(r, x, true)
+	case linkable(x):
+		addChild(r, x, false)
+		if h.rootPassive == nil {
+			h.rootPassive = x
+		}
+	default:
+		if h.rootPassive == nil {
+			addChild(r, x, false)
+		} else {
+			insertBefore(r, x, h.rootPassive)
+		}
+		h.rootPassive = x
+	}
+}
+
+// detach removes the node x from the children of its parent.
+func (h *HeapOf[K, V, O]) detach(x *node[K, V]) {
+	p := x.parent
+	if x == h.rootPassive {
+		if x.right == p.child {
+			h.rootPassive = nil
+		} else {
+			h.rootPassive = x.right
+		}
+	}
+	if x.right == x {
+		p.child = nil
+	} else {
+		x.left.right = x.right
+		x.right.left = x.left
+		if p.child == x {
+			p.child = x.right
+		}
+	}
+	x.parent, x.left, x.right = nil, x, x
+	p.degree--
+}
+
+// addChild adds x as the leftmost or the rightmost child of p.
+func addChild[K any, V any](p, x *node[K, V], leftmost bool) {
+	if p.child == nil {
+		x.parent = p
+		x.left, x.right = x, x
+		p.child = x
+		p.degree++
+		return
+	}
+	insertBefore(p, x, p.child)
+	if !leftmost {
+		p.child = x.right
+	}
+}
+
+// insertBefore adds x as a child of p, to the left of the child b.
+func insertBefore[K any, V any](p, x, b *node[K, V]) {
+	x.parent = p
+	x.left, x.right = b.left, b
+	b.left.right = x
+	b.left = x
+	if p.child == b {
+		p.child = x
+	}
+	p.degree++
+}
+
+func isActive[K any, V any](x *node[K, V]) bool {
+	return x.active.active
+}
+
+// linkable reports whether x is passive and has no active children.
+func linkable[K any, V any](x *node[K, V]) bool {
+	return !isActive(x) && (x.child == nil || !isActive(x.child))
+}
+
+// unfix removes the node x from the fix-list it is in.
+func (h *HeapOf[K, V, O]) unfix(x *node[K, V]) {
+	if !isActive(x) {
+		// the lists of a heap made passive by Meld are gone
+		x.fixKind = fixNone
+		return
+	}
+	switch r := x.rank; x.fixKind {
+	case fixRoot:
+		ringRemove(&r.roots, x, fixAt)
+		if r.nroots--; r.nroots == 1 {
+			ringRemove(&h.rootPairs, r, rootPairAt)
+		}
+	case fixLoss1:
+		ringRemove(&r.loss1, x, fixAt)
+		if r.nloss1--; r.nloss1 == 1 {
+			ringRemove(&h.lossPairs, r, lossPairAt)
+		}
+	case fixLoss2:
+		ringRemove(&h.loss2, x, fixAt)
+	}
+	x.fixKind = fixNone
+}
+
+// refix adds the active node x to the fix-list matching its state.
+func (h *HeapOf[K, V, O]) refix(x *node[K, V]) {
+	switch r := x.rank; {
+	case !isActive(x.parent):
+		x.fixKind = fixRoot
+		ringAdd(&r.roots, x, fixAt)
+		if r.nroots++; r.nroots == 2 {
+			ringAdd(&h.rootPairs, r, rootPairAt)
+		}
+	case x.loss == 1:
+		x.fixKind = fixLoss1
+		ringAdd(&r.loss1, x, fixAt)
+		if r.nloss1++; r.nloss1 == 2 {
+			ringAdd(&h.lossPairs, r, lossPairAt)
+		}
+	case x.loss >= 2:
+		x.fixKind = fixLoss2
+		ringAdd(&h.loss2, x, fixAt)
+	}
+}
+
+// rankZero returns the record of rank zero of the heap h.
+func (h *HeapOf[K, V, O]) rankZero() *rankRecord[K, V] {
+	if h.ranks == nil {
+		h.ranks = &rankRecord[K, V]{}
+	}
+	return h.ranks
+}
+
+// nextRank returns the record of the rank following r.
+func (h *HeapOf[K, V, O]) nextRank(r *rankRecord[K, V]) *rankRecord[K, V] {
+	if r.next == nil {
+		r.next = &rankRecord[K, V]{rank: r.rank + 1, prev: r}
+	}
+	return r.next
+}
+
+// ringAdd adds x at the end of the circular list starting at *head, whose links
+// are found by at.
+func ringAdd[T any](head **T, x *T, at func(*T) *link[T]) {
+	if *head == nil {
+		at(x).prev, at(x).next = x, x
+		*head = x
+		return
+	}
+	first := *head
+	last := at(first).prev
+	at(x).prev, at(x).next = last, first
+	at(last).next = x
+	at(first).prev = x
+}
+
+// ringRemove removes x from the circular list starting at *head.
+func ringRemove[T any](head **T, x *T, at func(*T) *link[T]) {
+	l := at(x)
+	if l.next == x {
+		*head = nil
+	} else {
+		at(l.prev).next = l.next
+		at(l.next).prev = l.prev
+		if *head == x {
+			*head = l.next
+		}
+	}
+	l.prev, l.next = nil, nil
+}
+
+// ringConcat appends the circular list b to the circular list a, and returns
+// the start of the result.
+func ringConcat[T any](a, b *T, at func(*T) *link[T]) *T {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	lastA, lastB := at(a).prev, at(b).prev
+	at(lastA).next = b
+	at(b).prev = lastA
+	at(lastB).next = a
+	at(a).prev = lastB
+	return a
+}
+
+// mustOrder panics if the heap h cannot compare keys, that is, it is the zero
+// value of a HeapFunc.
+func (h *HeapOf[K, V, O]) mustOrder() {
+	if !valid[K](h.order) {
+		panic("strictfibheap: a heap ordered by a function must be created by NewHeapFunc")
+	}
+}
+
+// valid reports whether the ordering o can compare keys, which the zero Func
+// cannot.
+func valid[K any, O fibheap.Order[K]](o O) bool {
+	f, ok := any(o).(fibheap.Func[K])
+	return !ok || f != nil
+}
@@ -0,0 +1,247 @@
+package strictfibheap
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"github.com/ksw2000/go-fibheap/internal/testsuite"
+)
+
+// check validates the structure of the heap h and the bounds of the strict
+// Fibonacci heap invariants.
+func (h *HeapOf[K, V, O]) check() error {
+	if h.root == nil {
+		if h.elements != 0 || h.queue != nil {
+			return fmt.Errorf("empty heap with %d elements", h.elements)
+		}
+		return nil
+	}
+	if h.root.parent != nil || isActive(h.root) {
+		return fmt.Errorf("root has a parent or is active")
+	}
+	count, activeRoots, totalLoss := 0, 0, 0
+	var walk func(x *node[K, V]) error
+	walk = func(x *node[K, V]) error {
+		count++
+		if x.e.n != x {
+			return fmt.Errorf("element %v does not point to its node", x.e.key)
+		}
+		if isActive(x) {
+			if !isActive(x.parent) {
+				activeRoots++
+				if x.loss != 0 || x.fixKind != fixRoot {
+					return fmt.Errorf("active root %v has loss %d", x.e.key, x.loss)
+				}
+			} else if kind := [3]uint8{fixNone, fixLoss1, fixLoss2}[min(x.loss, 2)]; x.fixKind != kind {
+				return fmt.Errorf("node %v with loss %d is in fix-list %d", x.e.key, x.loss, x.fixKind)
+			}
+			totalLoss += x.loss
+		}
+		if x.child == nil {
+			if x.degree != 0 || (isActive(x) && x.rank.rank != 0) {
+				return fmt.Errorf("leaf %v has degree %d", x.e.key, x.degree)
+			}
+			return nil
+		}
+		degree, rank, passiveSeen, linkableSeen := 0, 0, false, false
+		for c := x.child; ; {
+			degree++
+			if c.parent != x || c.right.left != c || c.left.right != c {
+				return fmt.Errorf("broken links at %v", c.e.key)
+			}
+			if h.before(c, x) {
+				return fmt.Errorf("child %v is before its parent %v", c.e.key, x.e.key)
+			}
+			switch {
+			case isActive(c):
+				rank++
+				if passiveSeen {
+					return fmt.Errorf("active child %v after a passive one", c.e.key)
+				}
+			case x == h.root && linkable(c):
+				if !passiveSeen && c != h.rootPassive {
+					return fmt.Errorf("wrong leftmost passive child of the root")
+				}
+				passiveSeen, linkableSeen = true, true
+			default:
+				if x == h.root && (linkableSeen || !passiveSeen && c != h.rootPassive) {
+					return fmt.Errorf("passive child %v of the root is misplaced", c.e.key)
+				}
+				passiveSeen = true
+			}
+			if err := walk(c); err != nil {
+				return err
+			}
+			if c = c.right; c == x.child {
+				break
+			}
+		}
+		if degree != x.degree {
+			return fmt.Errorf("node %v has %d children, but degree %d", x.e.key, degree, x.degree)
+		}
+		if isActive(x) && rank != x.rank.rank {
+			return fmt.Errorf("node %v has %d active children, but rank %d", x.e.key, rank, x.rank.rank)
+		}
+		if x == h.root && !passiveSeen && h.rootPassive != nil {
+			return fmt.Errorf("root has no passive child")
+		}
+		return nil
+	}
+	if err := walk(h.root); err != nil {
+		return err
+	}
+	if count != h.elements {
+		return fmt.Errorf("found %d elements, but Size is %d", count, h.elements)
+	}
+	queued := 0
+	if q := h.queue; q != nil {
+		for x := q; ; {
+			queued++
+			if x == h.root || queued > h.elements {
+				return fmt.Errorf("broken queue")
+			}
+			if x = x.queue.next; x == q {
+				break
+			}
+		}
+	}
+	if queued != h.elements-1 {
+		return fmt.Errorf("queue holds %d nodes, expected %d", queued, h.elements-1)
+	}
+	for r := h.ranks; r != nil; r = r.next {
+		if (r.nroots >= 2) != (r.rootPair.next != nil) || (r.nloss1 >= 2) != (r.lossPair.next != nil) {
+			return fmt.Errorf("rank %d is misplaced in the pair lists", r.rank)
+		}
+	}
+
+	// the bounds which make the worst-case running times hold
+	bound := 2*bits.Len(uint(h.elements)) + 6
+	if activeRoots > bound+1 {
+		return fmt.Errorf("%d active roots, expected at most %d", activeRoots, bound+1)
+	}
+	if totalLoss > bound+1 {
+		return fmt.Errorf("total loss %d, expected at most %d", totalLoss, bound+1)
+	}
+	if h.root.degree > bound+3 {
+		return fmt.Errorf("root degree %d, expected at most %d", h.root.degree, bound+3)
+	}
+	return nil
+}
+
+func TestHeap(t *testing.T) {
+	h := &Heap[int, string]{}
+	if h.Min() != nil || h.ExtractMin() != nil {
+		t.Fatal("expected an empty heap")
+	}
+	for _, k := range []int{5, 3, 8, 1, 9, 2} {
+		h.Insert(k, "")
+	}
+	if h.Size() != 6 || h.Min().Key() != 1 {
+		t.Fatalf("expected 6 elements and minimum 1, got %d and %d", h.Size(), h.Min().Key())
+	}
+	for _, expected := range []int{1, 2, 3, 5, 8, 9} {
+		if x := h.ExtractMin(); x.Key() != expected {
+			t.Fatalf("expected %d, got %d", expected, x.Key())
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+}
+
+func TestHeapStable(t *testing.T) {
+	h := &Heap[int, int]{}
+	for i := 0; i < 100; i++ {
+		h.Insert(i%3, i)
+	}
+	prev := h.ExtractMin()
+	for h.Size() > 0 {
+		x := h.ExtractMin()
+		if x.Key() == prev.Key() && x.Value < prev.Value {
+			t.Fatalf("element %d extracted after element %d with equal key", x.Value, prev.Value)
+		}
+		prev = x
+	}
+}
+
+func TestHeapDecreasingDelete(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i+100, i)
+	}
+	h.ExtractMin()
+	h.Decreasing(elements[73], 0)
+	h.Decreasing(elements[50], 300)
+	if x := h.Min(); x != elements[73] || x.Value != 73 {
+		t.Fatalf("expected element 73 as the minimum, got %v", x.Value)
+	}
+	h.Delete(elements[73])
+	h.Delete(elements[99])
+	for i := 1; i < 99; i++ {
+		if i == 73 {
+			continue
+		}
+		if x := h.ExtractMin(); x != elements[i] {
+			t.Fatalf("expected element %d, got %d", i, x.Value)
+		}
+	}
+	if h.Size() != 0 {
+		t.Fatalf("expected an empty heap, got %d elements", h.Size())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Should panic()")
+		}
+	}()
+	h.Decreasing(elements[0], -1)
+}
+
+func TestHeapInsertMany(t *testing.T) {
+	h := &Heap[int, any]{}
+	for i := 1 << 16; i > 0; i-- {
+		h.Insert(i, nil)
+	}
+	// the root degree stays logarithmic, so no extraction has to consolidate
+	// the inserted elements at once
+	if err := h.check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 100; i++ {
+		if x := h.ExtractMin(); x.Key() != i {
+			t.Fatalf("expected %d, got %d", i, x.Key())
+		}
+		if err := h.check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHeapUnion(t *testing.T) {
+	h := NewHeapFunc[int, any](func(a, b int) bool { return a > b })
+	g := NewHeapFunc[int, any](func(a, b int) bool { return a > b })
+	for i := 0; i < 10; i++ {
+		h.Insert(i, nil)
+	}
+	for i := 10; i < 30; i++ {
+		g.Insert(i, nil)
+	}
+	k := h.Union(g)
+	if h.Size() != 0 || g.Size() != 0 {
+		t.Fatal("h and g should be clear after Union")
+	}
+	if err := k.check(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 29; i >= 0; i-- {
+		if x := k.ExtractMin(); x.Key() != i {
+			t.Fatalf("expected %d, got %d", i, x.Key())
+		}
+	}
+}
+
+func TestHeapConformance(t *testing.T) {
+	testsuite.ConformanceMeld(t, func() *Heap[int, int] { return &Heap[int, int]{} }, (*Heap[int, int]).check)
+}
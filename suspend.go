@@ -0,0 +1,71 @@
+package fibheap
+
+// Suspend removes the element x from the heap h and holds it until Resume is
+// called, preserving the element, its key and its value. A suspended element is
+// not counted by Size and cannot be extracted. Suspend panics if x is already
+// suspended or pinned.
+func (h *HeapOf[K, V, O]) Suspend(x *Element[K, V]) {
+	h.mustContain(x, "Suspend")
+	if x.flags&(suspended|pinned) != 0 {
+		panic("fibheap: Suspend expects an element which is not suspended or pinned")
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opSuspend, ID: x.id})
+	}
+	h.park(x, suspended)
+	h.elements--
+	if h.suspended == nil {
+		h.suspended = make(map[*Element[K, V]]struct{})
+	}
+	h.suspended[x] = struct{}{}
+	if h.watches != nil {
+		h.notify()
+	}
+}
+
+// Resume reinserts the element x suspended by Suspend into the heap h with
+// amortized running time Θ(1). Resume panics if x is not suspended in the heap
+// h.
+func (h *HeapOf[K, V, O]) Resume(x *Element[K, V]) {
+	if !h.Contains(x) || x.flags&suspended == 0 {
+		panic("fibheap: Resume expects an element suspended in the heap")
+	}
+	if h.tracer != nil {
+		h.record(traceOp[K, V]{Op: opResume, ID: x.id})
+	}
+	delete(h.suspended, x)
+	h.elements++
+	h.unpark(x, suspended)
+}
+
+// Suspended returns the number of elements suspended in the heap h.
+func (h *HeapOf[K, V, O]) Suspended() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.suspended)
+}
+
+// park removes the element x from the trees of the heap h and sets flag on it,
+// without changing the number of elements.
+func (h *HeapOf[K, V, O]) park(x *Element[K, V], flag uint8) {
+	owner := x.owner
+	h.delete(x)
+	h.elements++
+	x.owner = owner
+	x.flags |= flag
+}
+
+// unpark clears flag on the element x parked by park and adds it back to the
+// root list of the heap h.
+func (h *HeapOf[K, V, O]) unpark(x *Element[K, V], flag uint8) {
+	x.flags &^= flag
+	h.min = h.min.append(x)
+	h.roots++
+	if h.before(x, h.min) {
+		h.min = x
+	}
+	if h.watches != nil {
+		h.notify()
+	}
+}
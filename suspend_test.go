@@ -0,0 +1,74 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapSuspendResume(t *testing.T) {
+	h := &Heap[int, int]{}
+	elements := make([]*Element[int, int], 100)
+	for i := range elements {
+		elements[i] = h.Insert(i, i)
+	}
+	h.ExtractMin()
+
+	for i := 1; i < 100; i += 2 {
+		h.Suspend(elements[i])
+	}
+	assert(t, h.Size(), 49)
+	assert(t, h.Suspended(), 50)
+	for i := 2; i < 100; i += 2 {
+		x := h.ExtractMin()
+		if x != elements[i] {
+			t.Fatalf("expected element %d, got %d", i, x.Value)
+		}
+	}
+
+	for i := 99; i > 0; i -= 2 {
+		h.Resume(elements[i])
+	}
+	assert(t, h.Size(), 50)
+	assert(t, h.Suspended(), 0)
+	for i := 1; i < 100; i += 2 {
+		x := h.ExtractMin()
+		if x != elements[i] {
+			t.Fatalf("expected element %d, got %d", i, x.Value)
+		}
+	}
+	if h.ExtractMin() != nil {
+		t.Fatal("heap should be empty")
+	}
+}
+
+func TestHeapSuspendMin(t *testing.T) {
+	h := &Heap[int, any]{}
+	a := h.Insert(1, nil)
+	h.Insert(2, nil)
+	h.Suspend(a)
+	assert(t, h.Min().Key(), 2)
+	h.Decreasing(a, 0)
+	h.Resume(a)
+	assert(t, h.Min().Key(), 0)
+}
+
+func TestHeapSuspendPanics(t *testing.T) {
+	h := &Heap[int, any]{}
+	g := &Heap[int, any]{}
+	a := h.Insert(1, nil)
+	h.Suspend(a)
+
+	for name, fn := range map[string]func(){
+		"Suspend twice":        func() { h.Suspend(a) },
+		"Resume in other heap": func() { g.Resume(a) },
+		"Resume not suspended": func() { h.Resume(h.Insert(2, nil)) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s should panic()", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,689 @@
+package fibheap
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"sync"
+	"unsafe"
+)
+
+// ErrFull is returned when inserting into a bounded heap which is at capacity.
+var ErrFull = errors.New("fibheap: heap is full")
+
+// SyncHeap represents a fibonacci heap which is safe for concurrent use by
+// multiple goroutines, such as a job queue shared by producers and consumers.
+// A SyncHeap can be bounded by a capacity, in which case inserting into a full
+// heap either fails or waits for a consumer to extract an element. The keys of
+// a SyncHeap are of an ordered type, and its zero value is an empty, unbounded
+// heap.
+//
+// Elements returned by a SyncHeap may be passed back to its methods, but their
+// keys must not be read while other goroutines may still modify them.
+//
+// SyncHeap provides the methods of Heap which insert, extract, update and
+// query elements, meld heaps and register callbacks, each holding the lock of
+// the heap. The methods which read or replace the whole structure of a heap,
+// such as All, Roots, Clone, Dot, the encodings and tracing, and the Try
+// variants returning errors are not provided.
+type SyncHeap[K cmp.Ordered, V any] = SyncHeapOf[K, V, Ordered[K]]
+
+// SyncHeapFunc is a SyncHeap whose keys are ordered by a comparison function.
+// It is created by NewSyncHeapFunc.
+type SyncHeapFunc[K any, V any] = SyncHeapOf[K, V, Func[K]]
+
+// SyncHeapOf is a SyncHeap whose keys are ordered by O. It is used through its
+// aliases SyncHeap and SyncHeapFunc.
+type SyncHeapOf[K any, V any, O Order[K]] struct {
+	mu       sync.Mutex
+	heap     HeapOf[K, V, O]
+	capacity int
+	// changed is closed and reset whenever the heap changes.
+	changed chan struct{}
+	hooks   []*insertHook[K, V]
+}
+
+// insertHook is a function registered by NotifyOnInsert.
+type insertHook[K any, V any] struct {
+	fn func(x *Element[K, V])
+}
+
+// NewSyncHeap returns an empty heap holding at most capacity elements. A
+// capacity less than or equal to zero means that the heap is unbounded.
+func NewSyncHeap[K cmp.Ordered, V any](capacity int) *SyncHeap[K, V] {
+	return &SyncHeap[K, V]{capacity: capacity}
+}
+
+// NewSyncHeapFunc returns an empty heap holding at most capacity elements, which
+// orders keys with less like NewHeapFunc.
+func NewSyncHeapFunc[K any, V any](capacity int, less func(a, b K) bool) *SyncHeapFunc[K, V] {
+	return &SyncHeapFunc[K, V]{heap: *NewHeapFunc[K, V](less), capacity: capacity}
+}
+
+// Cap returns the capacity of the heap s, or zero if s is unbounded.
+func (s *SyncHeapOf[K, V, O]) Cap() int {
+	if s.capacity < 0 {
+		return 0
+	}
+	return s.capacity
+}
+
+// Size returns the number of elements in the heap s
+func (s *SyncHeapOf[K, V, O]) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Size()
+}
+
+// SetStable sets whether the heap s extracts elements with equal keys in
+// insertion order, as Heap.SetStable. SetStable panics if the heap s is not
+// empty.
+func (s *SyncHeapOf[K, V, O]) SetStable(stable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.SetStable(stable)
+}
+
+// SetConsolidateThreshold sets the length of the root list above which the
+// heap s is consolidated on insert, as Heap.SetConsolidateThreshold.
+func (s *SyncHeapOf[K, V, O]) SetConsolidateThreshold(roots int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.SetConsolidateThreshold(roots)
+}
+
+// Stable reports whether the heap s breaks ties by insertion order.
+func (s *SyncHeapOf[K, V, O]) Stable() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Stable()
+}
+
+// Stats returns statistics about the shape of the heap s, as Heap.Stats.
+func (s *SyncHeapOf[K, V, O]) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Stats()
+}
+
+// Potential returns the potential of the heap s, as Heap.Potential.
+func (s *SyncHeapOf[K, V, O]) Potential() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Potential()
+}
+
+// SetWorkBudget registers fn to be called with the work of every consolidation
+// exceeding budget, as Heap.SetWorkBudget. The function fn is called with the
+// lock of s held.
+func (s *SyncHeapOf[K, V, O]) SetWorkBudget(budget int, fn func(work int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.SetWorkBudget(budget, fn)
+}
+
+// SetObserver sets the observer of the operations of the heap s, as
+// Heap.SetObserver. The observer is called with the lock of s held.
+func (s *SyncHeapOf[K, V, O]) SetObserver(o Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.SetObserver(o)
+}
+
+// Pinned returns the number of elements pinned in the heap s.
+func (s *SyncHeapOf[K, V, O]) Pinned() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Pinned()
+}
+
+// Suspended returns the number of elements suspended in the heap s.
+func (s *SyncHeapOf[K, V, O]) Suspended() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Suspended()
+}
+
+// OnSizeAbove registers fn to be called whenever the number of elements in the
+// heap s grows from at most mark to more than mark, as Heap.OnSizeAbove. The
+// function fn is called with the lock of s held, and must not use s.
+func (s *SyncHeapOf[K, V, O]) OnSizeAbove(mark int, fn func(size int)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked(s.heap.OnSizeAbove(mark, fn))
+}
+
+// OnSizeBelow registers fn to be called whenever the number of elements in the
+// heap s falls from at least mark to less than mark, as Heap.OnSizeBelow. The
+// function fn is called with the lock of s held, and must not use s.
+func (s *SyncHeapOf[K, V, O]) OnSizeBelow(mark int, fn func(size int)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked(s.heap.OnSizeBelow(mark, fn))
+}
+
+// OnMinBelow registers fn to be called with the minimum element whenever the
+// minimum key of the heap s crosses to less than threshold, as
+// Heap.OnMinBelow. The function fn is called with the lock of s held, and must
+// not use s.
+func (s *SyncHeapOf[K, V, O]) OnMinBelow(threshold K, fn func(min *Element[K, V])) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked(s.heap.OnMinBelow(threshold, fn))
+}
+
+// OnMinAbove registers fn to be called with the minimum element whenever the
+// minimum key of the heap s crosses to more than threshold, as
+// Heap.OnMinAbove. The function fn is called with the lock of s held, and must
+// not use s.
+func (s *SyncHeapOf[K, V, O]) OnMinAbove(threshold K, fn func(min *Element[K, V])) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked(s.heap.OnMinAbove(threshold, fn))
+}
+
+// Contains reports whether the element x belongs to the heap s.
+func (s *SyncHeapOf[K, V, O]) Contains(x *Element[K, V]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Contains(x)
+}
+
+// ByID returns the element of the heap s whose ID is id, or nil, as
+// Heap.ByID.
+func (s *SyncHeapOf[K, V, O]) ByID(id uint64) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.ByID(id)
+}
+
+// Insert inserts the key-value pair (key, value) to the heap s and returns the
+// inserted element. If the heap s is at capacity, Insert returns ErrFull.
+func (s *SyncHeapOf[K, V, O]) Insert(key K, value V) (*Element[K, V], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full() {
+		return nil, ErrFull
+	}
+	defer s.broadcast()
+	return s.insert(key, value), nil
+}
+
+// InsertElement inserts the element x, which belongs to no heap, to the heap s
+// without allocating, as Heap.InsertElement. If the heap s is at capacity,
+// InsertElement returns ErrFull and leaves x unchanged.
+func (s *SyncHeapOf[K, V, O]) InsertElement(x *Element[K, V]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full() {
+		return ErrFull
+	}
+	defer s.broadcast()
+	s.heap.InsertElement(x)
+	s.inserted(x)
+	return nil
+}
+
+// InsertAll inserts the key-value pairs of pairs to the heap s, and returns the
+// inserted elements in the same order, as Heap.InsertAll. If the pairs do not
+// fit into the capacity of the heap s, InsertAll inserts none of them and
+// returns ErrFull.
+func (s *SyncHeapOf[K, V, O]) InsertAll(pairs []Pair[K, V]) ([]*Element[K, V], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capacity > 0 && s.heap.Size()+len(pairs) > s.capacity {
+		return nil, ErrFull
+	}
+	defer s.broadcast()
+	list := s.heap.InsertAll(pairs)
+	for _, x := range list {
+		s.inserted(x)
+	}
+	return list, nil
+}
+
+// InsertBatch is like InsertAll, but does not return the inserted elements, as
+// Heap.InsertBatch.
+func (s *SyncHeapOf[K, V, O]) InsertBatch(pairs []Pair[K, V]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capacity > 0 && s.heap.Size()+len(pairs) > s.capacity {
+		return ErrFull
+	}
+	defer s.broadcast()
+	if len(s.hooks) == 0 || len(pairs) == 0 {
+		s.heap.InsertBatch(pairs)
+		return nil
+	}
+	slab := s.heap.insertBatch(pairs)
+	for i := range slab {
+		s.inserted(&slab[i])
+	}
+	return nil
+}
+
+// InsertWait inserts the key-value pair (key, value) to the heap s and returns
+// the inserted element. If the heap s is at capacity, InsertWait blocks until a
+// consumer extracts an element or the context ctx is done, in which case the
+// error of the context is returned.
+func (s *SyncHeapOf[K, V, O]) InsertWait(ctx context.Context, key K, value V) (*Element[K, V], error) {
+	s.mu.Lock()
+	for s.full() {
+		if err := s.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.insert(key, value), nil
+}
+
+// NotifyOnInsert registers fn to be called with every element inserted into
+// the heap s by Insert, InsertWait, InsertElement, InsertAll, InsertBatch and
+// ReplaceMin, such as to wake up a consumer which waits on its own channel. It
+// returns a function which unregisters fn. The function fn is called with the
+// heap locked and must not use the heap s.
+func (s *SyncHeapOf[K, V, O]) NotifyOnInsert(fn func(x *Element[K, V])) (cancel func()) {
+	if fn == nil {
+		panic("fibheap: NotifyOnInsert expects a non-nil function")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hook := &insertHook[K, V]{fn: fn}
+	s.hooks = append(s.hooks, hook)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.hooks {
+			if s.hooks[i] == hook {
+				s.hooks = append(s.hooks[:i], s.hooks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Min fetches the minimum key from the heap s
+func (s *SyncHeapOf[K, V, O]) Min() *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Min()
+}
+
+// WaitMin fetches the minimum key from the heap s without extracting it. If
+// the heap s is empty, WaitMin blocks until a producer inserts an element or
+// the context ctx is done, in which case the error of the context is returned.
+// Other goroutines may extract the returned element as soon as WaitMin returns;
+// use ExtractMinWait to consume it.
+func (s *SyncHeapOf[K, V, O]) WaitMin(ctx context.Context) (*Element[K, V], error) {
+	s.mu.Lock()
+	for s.heap.Min() == nil {
+		if err := s.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	defer s.mu.Unlock()
+	return s.heap.Min(), nil
+}
+
+// MinN returns the k smallest elements of the heap s in ascending order without
+// extracting them, as Heap.MinN.
+func (s *SyncHeapOf[K, V, O]) MinN(k int) []*Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.MinN(k)
+}
+
+// Rank returns the number of elements of the heap s which come before the
+// element x in extraction order, as Heap.Rank.
+func (s *SyncHeapOf[K, V, O]) Rank(x *Element[K, V]) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Rank(x)
+}
+
+// CountLess returns the number of elements of the heap s whose key is less than
+// key, as Heap.CountLess.
+func (s *SyncHeapOf[K, V, O]) CountLess(key K) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.CountLess(key)
+}
+
+// Select returns the element of rank k of the heap s without extracting it, as
+// Heap.Select.
+func (s *SyncHeapOf[K, V, O]) Select(k int) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Select(k)
+}
+
+// ExtractMin fetches and removes the minimum key from the heap s. It returns
+// nil if the heap s is empty.
+func (s *SyncHeapOf[K, V, O]) ExtractMin() *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractMin()
+}
+
+// ExtractMinWait fetches and removes the minimum key from the heap s. If the
+// heap s is empty, ExtractMinWait blocks until a producer inserts an element or
+// the context ctx is done, in which case the error of the context is returned.
+func (s *SyncHeapOf[K, V, O]) ExtractMinWait(ctx context.Context) (*Element[K, V], error) {
+	s.mu.Lock()
+	for s.heap.Min() == nil {
+		if err := s.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractMin(), nil
+}
+
+// ExtractMinIf fetches and removes the minimum key from the heap s only if pred
+// reports true for it, as Heap.ExtractMinIf. The function pred is called with
+// the heap locked and must not use the heap s.
+func (s *SyncHeapOf[K, V, O]) ExtractMinIf(pred func(K, V) bool) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractMinIf(pred)
+}
+
+// ExtractUntil fetches and removes, in ascending order, every element whose key
+// is less than or equal to key.
+func (s *SyncHeapOf[K, V, O]) ExtractUntil(key K) []*Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractUntil(key)
+}
+
+// ExtractWhile fetches and removes, in ascending order, the minimum element of
+// the heap s as long as pred reports true for it, as Heap.ExtractWhile. The
+// function pred is called with the heap locked and must not use the heap s.
+func (s *SyncHeapOf[K, V, O]) ExtractWhile(pred func(K, V) bool) []*Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractWhile(pred)
+}
+
+// PopN fetches and removes the n smallest elements of the heap s in ascending
+// order, as Heap.PopN.
+func (s *SyncHeapOf[K, V, O]) PopN(n int) []*Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.PopN(n)
+}
+
+// ReplaceMin extracts the minimum of the heap s and inserts the key-value pair
+// (key, value) in one step, reusing the element of the minimum, as
+// Heap.ReplaceMin.
+func (s *SyncHeapOf[K, V, O]) ReplaceMin(key K, value V) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	x := s.heap.ReplaceMin(key, value)
+	s.inserted(x)
+	return x
+}
+
+// Drain fetches and removes every element from the heap s, and returns them in
+// ascending order, as Heap.Drain.
+func (s *SyncHeapOf[K, V, O]) Drain() []*Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.Drain()
+}
+
+// ExtractRandom fetches and removes an element chosen uniformly at random, as
+// Heap.ExtractRandom. The source r must not be used concurrently by other
+// goroutines.
+func (s *SyncHeapOf[K, V, O]) ExtractRandom(r *rand.Rand) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.ExtractRandom(r)
+}
+
+// Decreasing decreases the key of the element x, as Heap.Decreasing.
+func (s *SyncHeapOf[K, V, O]) Decreasing(x *Element[K, V], key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Decreasing(x, key)
+}
+
+// DecreaseAll decreases the keys of many elements at once, as
+// Heap.DecreaseAll.
+func (s *SyncHeapOf[K, V, O]) DecreaseAll(updates []KeyUpdate[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.DecreaseAll(updates)
+}
+
+// Increasing increases the key of the element x, as Heap.Increasing.
+func (s *SyncHeapOf[K, V, O]) Increasing(x *Element[K, V], key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Increasing(x, key)
+}
+
+// Update changes the key of the element x to key in either direction, as
+// Heap.Update.
+func (s *SyncHeapOf[K, V, O]) Update(x *Element[K, V], key K) *Element[K, V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.Update(x, key)
+}
+
+// Remove removes the element x by given a key minimumKey which is smaller than
+// any key in the heap s, as Heap.Remove.
+func (s *SyncHeapOf[K, V, O]) Remove(x *Element[K, V], minimumKey K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Remove(x, minimumKey)
+}
+
+// Delete removes the element x from the heap s, as Heap.Delete.
+func (s *SyncHeapOf[K, V, O]) Delete(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Delete(x)
+}
+
+// DeleteWhere removes every element of the heap s for which pred reports true,
+// as Heap.DeleteWhere. The function pred is called with the heap locked and
+// must not use the heap s.
+func (s *SyncHeapOf[K, V, O]) DeleteWhere(pred func(K, V) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	return s.heap.DeleteWhere(pred)
+}
+
+// Clear removes every element from the heap s, as Heap.Clear.
+func (s *SyncHeapOf[K, V, O]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Clear()
+}
+
+// Meld moves every element of the heap g into the heap s, leaving g empty, as
+// Heap.Meld. Both heaps are locked, in an order which does not deadlock with a
+// concurrent Meld of s into g. If the elements of g do not fit into the
+// capacity of s, Meld returns ErrFull and leaves both heaps unchanged. Meld
+// panics if s and g are the same heap.
+func (s *SyncHeapOf[K, V, O]) Meld(g *SyncHeapOf[K, V, O]) error {
+	if s == g {
+		panic("fibheap: Meld expects two different heaps")
+	}
+	defer s.lockWith(g)()
+	if s.capacity > 0 && s.heap.Size()+g.heap.Size() > s.capacity {
+		return ErrFull
+	}
+	defer s.broadcast()
+	defer g.broadcast()
+	s.heap.Meld(&g.heap)
+	return nil
+}
+
+// Union unions the two heaps s and g, and returns the new heap, leaving s and g
+// empty, as Heap.Union. Both heaps are locked as by Meld. The new heap has the
+// capacity of s, which the elements of s and g may exceed, in which case
+// inserting into the new heap fails or waits until enough elements are
+// extracted. Callbacks registered by NotifyOnInsert are not carried over.
+func (s *SyncHeapOf[K, V, O]) Union(g *SyncHeapOf[K, V, O]) *SyncHeapOf[K, V, O] {
+	defer s.lockWith(g)()
+	defer s.broadcast()
+	defer g.broadcast()
+	return &SyncHeapOf[K, V, O]{heap: *s.heap.Union(&g.heap), capacity: s.capacity}
+}
+
+// Descend returns an iterator over the elements of the heap s from the largest
+// key to the smallest one, as Heap.Descend. The snapshot of the heap is taken
+// with the lock of s held when the iteration starts, and the lock is released
+// while the elements are yielded.
+func (s *SyncHeapOf[K, V, O]) Descend() iter.Seq[*Element[K, V]] {
+	return func(yield func(*Element[K, V]) bool) {
+		s.mu.Lock()
+		list := s.heap.descending()
+		s.mu.Unlock()
+		for _, e := range list {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Suspend removes the element x from the heap s until Resume is called, as
+// Heap.Suspend.
+func (s *SyncHeapOf[K, V, O]) Suspend(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Suspend(x)
+}
+
+// Resume reinserts the element x suspended by Suspend, as Heap.Resume.
+// Resuming an element ignores the capacity of the heap s.
+func (s *SyncHeapOf[K, V, O]) Resume(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Resume(x)
+}
+
+// Pin holds the element x back from extraction until Unpin is called, as
+// Heap.Pin.
+func (s *SyncHeapOf[K, V, O]) Pin(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Pin(x)
+}
+
+// Unpin releases the element x pinned by Pin, as Heap.Unpin.
+func (s *SyncHeapOf[K, V, O]) Unpin(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.broadcast()
+	s.heap.Unpin(x)
+}
+
+// Recycle hands the element x, which has been removed from a heap, back to the
+// heap s for reuse by a later Insert, as Heap.Recycle.
+func (s *SyncHeapOf[K, V, O]) Recycle(x *Element[K, V]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heap.Recycle(x)
+}
+
+// insert inserts the key-value pair (key, value) to the heap s, and calls the
+// functions registered by NotifyOnInsert.
+func (s *SyncHeapOf[K, V, O]) insert(key K, value V) *Element[K, V] {
+	x := s.heap.Insert(key, value)
+	s.inserted(x)
+	return x
+}
+
+// inserted calls the functions registered by NotifyOnInsert with the element x
+// inserted to the heap s.
+func (s *SyncHeapOf[K, V, O]) inserted(x *Element[K, V]) {
+	for _, hook := range s.hooks {
+		hook.fn(x)
+	}
+}
+
+func (s *SyncHeapOf[K, V, O]) full() bool {
+	return s.capacity > 0 && s.heap.Size() >= s.capacity
+}
+
+// wait releases the lock of s until the heap s changes or the context ctx is
+// done. The lock is held again when wait returns nil, and released otherwise.
+func (s *SyncHeapOf[K, V, O]) wait(ctx context.Context) error {
+	if s.changed == nil {
+		s.changed = make(chan struct{})
+	}
+	changed := s.changed
+	s.mu.Unlock()
+	select {
+	case <-changed:
+		s.mu.Lock()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// locked returns a function calling cancel with the lock of the heap s held.
+func (s *SyncHeapOf[K, V, O]) locked(cancel func()) func() {
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		cancel()
+	}
+}
+
+// lockWith locks the heaps s and g, which may be the same heap, and returns a
+// function unlocking them. The heaps are locked in the order of their
+// addresses, so that two goroutines locking the same heaps never wait for each
+// other.
+func (s *SyncHeapOf[K, V, O]) lockWith(g *SyncHeapOf[K, V, O]) (unlock func()) {
+	if s == g {
+		s.mu.Lock()
+		return s.mu.Unlock
+	}
+	a, b := s, g
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.mu.Lock()
+	b.mu.Lock()
+	return func() {
+		b.mu.Unlock()
+		a.mu.Unlock()
+	}
+}
+
+// broadcast wakes up every goroutine waiting for the heap s to change.
+func (s *SyncHeapOf[K, V, O]) broadcast() {
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+}
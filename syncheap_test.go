@@ -0,0 +1,381 @@
+package fibheap
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSyncHeapBounded(t *testing.T) {
+	s := NewSyncHeap[int, any](2)
+	for i := 0; i < 2; i++ {
+		if _, err := s.Insert(i, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Insert(2, nil); !errors.Is(err, ErrFull) {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	assert(t, s.Size(), 2)
+	assert(t, s.Cap(), 2)
+}
+
+func TestSyncHeapInsertWait(t *testing.T) {
+	s := NewSyncHeap[int, any](1)
+	s.Insert(0, nil)
+
+	done := make(chan error)
+	go func() {
+		_, err := s.InsertWait(context.Background(), 1, nil)
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("InsertWait should block while the heap is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert(t, s.ExtractMin().Key(), 0)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	assert(t, s.Min().Key(), 1)
+}
+
+func TestSyncHeapInsertWaitCancel(t *testing.T) {
+	s := NewSyncHeap[int, any](1)
+	s.Insert(0, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.InsertWait(ctx, 1, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	assert(t, s.Size(), 1)
+
+	// the heap must still be usable after a cancelled wait
+	s.ExtractMin()
+	if _, err := s.InsertWait(context.Background(), 1, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncHeapConcurrent(t *testing.T) {
+	s := NewSyncHeap[int, any](4)
+	const producers, count = 4, 100
+	for p := 0; p < producers; p++ {
+		go func(p int) {
+			for i := 0; i < count; i++ {
+				s.InsertWait(context.Background(), p*count+i, nil)
+			}
+		}(p)
+	}
+	seen := make(map[int]bool)
+	for len(seen) < producers*count {
+		if x := s.ExtractMin(); x != nil {
+			seen[x.Key()] = true
+		} else {
+			runtime.Gosched()
+		}
+	}
+	assert(t, s.Size(), 0)
+}
+
+func TestSyncHeapExtractMinWait(t *testing.T) {
+	s := &SyncHeap[int, string]{}
+	done := make(chan *Element[int, string])
+	go func() {
+		x, err := s.ExtractMinWait(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		done <- x
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("ExtractMinWait should block while the heap is empty")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Insert(1, "one")
+	if x := <-done; x == nil || x.Value != "one" {
+		t.Fatal("ExtractMinWait should extract the element one")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ExtractMinWait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSyncHeapWaitMin(t *testing.T) {
+	s := &SyncHeap[int, string]{}
+	done := make(chan *Element[int, string])
+	go func() {
+		x, err := s.WaitMin(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		done <- x
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("WaitMin should block while the heap is empty")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Insert(1, "one")
+	if x := <-done; x == nil || x.Value != "one" {
+		t.Fatal("WaitMin should return the element one")
+	}
+	assert(t, s.Size(), 1)
+	s.ExtractMin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := s.WaitMin(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestSyncHeapNotifyOnInsert(t *testing.T) {
+	s := NewSyncHeap[int, string](1)
+	var inserted []string
+	cancel := s.NotifyOnInsert(func(x *Element[int, string]) { inserted = append(inserted, x.Value) })
+	s.Insert(1, "one")
+	s.Insert(2, "full")
+	s.ExtractMin()
+	s.InsertWait(context.Background(), 3, "three")
+	cancel()
+	s.ExtractMin()
+	s.Insert(4, "cancelled")
+	if !slices.Equal(inserted, []string{"one", "three"}) {
+		t.Fatalf("unexpected notifications %v", inserted)
+	}
+	mustPanic(t, "NotifyOnInsert(nil)", "fibheap: ", func() { s.NotifyOnInsert(nil) })
+}
+
+func TestSyncHeapInsertElement(t *testing.T) {
+	s := NewSyncHeap[int, string](1)
+	var inserted []string
+	s.NotifyOnInsert(func(x *Element[int, string]) { inserted = append(inserted, x.Value) })
+	var a, b Element[int, string]
+	if err := s.InsertElement(a.Init(1, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.InsertElement(b.Init(2, "b")); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if b.Key() != 2 || b.Value != "b" {
+		t.Fatal("expected the rejected element to be unchanged")
+	}
+	if s.ExtractMin() != &a {
+		t.Fatal("expected the inserted element to be extracted")
+	}
+	if !slices.Equal(inserted, []string{"a"}) {
+		t.Fatalf("unexpected notifications %v", inserted)
+	}
+}
+
+func TestSyncHeapInsertAll(t *testing.T) {
+	s := NewSyncHeap[int, string](3)
+	var inserted []string
+	s.NotifyOnInsert(func(x *Element[int, string]) { inserted = append(inserted, x.Value) })
+	list, err := s.InsertAll([]Pair[int, string]{{2, "b"}, {1, "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, len(list), 2)
+	if _, err := s.InsertAll([]Pair[int, string]{{3, "c"}, {4, "d"}}); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	assert(t, s.Size(), 2)
+	assert(t, s.Min().Key(), 1)
+	if err := s.InsertBatch([]Pair[int, string]{{3, "c"}, {4, "d"}}); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if err := s.InsertBatch([]Pair[int, string]{{0, "z"}}); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, s.Min().Key(), 0)
+	if x := s.ReplaceMin(5, "e"); x.Key() != 5 || s.Min().Key() != 1 {
+		t.Fatal("expected ReplaceMin to replace the minimum")
+	}
+	assert(t, s.Size(), 3)
+	if !slices.Equal(inserted, []string{"b", "a", "z", "e"}) {
+		t.Fatalf("unexpected notifications %v", inserted)
+	}
+}
+
+func TestSyncHeapAPI(t *testing.T) {
+	s := NewSyncHeapFunc[int, any](0, func(a, b int) bool { return a > b })
+	elements := make([]*Element[int, any], 10)
+	for i := range elements {
+		elements[i], _ = s.Insert(i, nil)
+	}
+	s.Decreasing(elements[0], 100)
+	s.Increasing(elements[9], -1)
+	s.Delete(elements[1])
+	s.Remove(elements[2], 1000)
+	s.Suspend(elements[3])
+	s.Pin(elements[4])
+	assert(t, s.Min().Key(), 100)
+	assert(t, s.Size(), 7)
+
+	if x := s.ExtractMinIf(func(k int, _ any) bool { return k < 100 }); x != nil {
+		t.Fatal("ExtractMinIf should not extract 100")
+	}
+	assert(t, len(s.ExtractUntil(6)), 4)
+	s.Resume(elements[3])
+	s.Unpin(elements[4])
+	assert(t, s.MinN(2)[1].Key(), 4)
+	assert(t, s.PopN(1)[0].Key(), 5)
+	assert(t, len(s.ExtractWhile(func(k int, _ any) bool { return k > 3 })), 1)
+	x, _ := s.ExtractMinWait(context.Background())
+	assert(t, x.Key(), 3)
+	s.Insert(7, nil)
+	if list := s.Drain(); len(list) != 2 || list[0].Key() != 7 || list[1].Key() != -1 {
+		t.Fatalf("unexpected drained elements %v", list)
+	}
+	assert(t, s.Size(), 0)
+}
+
+func TestSyncHeapMeld(t *testing.T) {
+	s := NewSyncHeap[int, string](3)
+	g := NewSyncHeap[int, string](0)
+	s.Insert(2, "b")
+	g.Insert(1, "a")
+	x, _ := g.Insert(3, "c")
+	g.Pin(x)
+	assert(t, g.Pinned(), 1)
+	if err := s.Meld(g); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, s.Size(), 3)
+	assert(t, s.Pinned(), 1)
+	assert(t, g.Size(), 0)
+	if !s.Contains(x) {
+		t.Fatal("expected the melded elements to belong to the heap")
+	}
+
+	g.Insert(0, "full")
+	if err := s.Meld(g); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	assert(t, g.Size(), 1)
+	mustPanic(t, "Meld", "fibheap: Meld expects", func() { s.Meld(s) })
+
+	m := s.Union(g)
+	assert(t, s.Size(), 0)
+	assert(t, g.Size(), 0)
+	assert(t, m.Size(), 4)
+	assert(t, m.Cap(), 3)
+	var keys []int
+	for e := range m.Descend() {
+		keys = append(keys, e.Key())
+		// the snapshot is not locked while it is yielded
+		m.Size()
+	}
+	if !slices.Equal(keys, []int{3, 2, 1, 0}) {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+}
+
+func TestSyncHeapMeldConcurrent(t *testing.T) {
+	a, b := NewSyncHeap[int, int](0), NewSyncHeap[int, int](0)
+	done := make(chan struct{})
+	for _, pair := range [][2]*SyncHeap[int, int]{{a, b}, {b, a}} {
+		go func() {
+			for i := range 1000 {
+				pair[0].Insert(i, i)
+				pair[0].Meld(pair[1])
+			}
+			done <- struct{}{}
+		}()
+	}
+	<-done
+	<-done
+	assert(t, a.Size()+b.Size(), 2000)
+}
+
+func TestSyncHeapWatches(t *testing.T) {
+	s := NewSyncHeap[int, any](0)
+	var events []string
+	cancel := s.OnSizeAbove(1, func(size int) { events = append(events, "above") })
+	s.OnSizeBelow(1, func(size int) { events = append(events, "below") })
+	s.OnMinBelow(0, func(min *Element[int, any]) { events = append(events, "min below") })
+	s.OnMinAbove(5, func(min *Element[int, any]) { events = append(events, "min above") })
+	x, _ := s.Insert(10, nil)
+	s.Insert(-1, nil)
+	s.ExtractMin()
+	s.Suspend(x)
+	assert(t, s.Suspended(), 1)
+	cancel()
+	s.Resume(x)
+	s.Insert(20, nil)
+	expected := []string{"min above", "above", "min below", "min above", "below", "min above"}
+	if !slices.Equal(events, expected) {
+		t.Fatalf("expected %v, got %v", expected, events)
+	}
+}
+
+func TestSyncHeapConsumers(t *testing.T) {
+	s := NewSyncHeap[int, any](8)
+	const consumers, count = 4, 400
+	results := make(chan int)
+	for c := 0; c < consumers; c++ {
+		go func() {
+			for {
+				x, err := s.ExtractMinWait(context.Background())
+				if err != nil {
+					return
+				}
+				results <- x.Key()
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < count; i++ {
+			s.InsertWait(context.Background(), i, nil)
+		}
+	}()
+	seen := make(map[int]bool)
+	for len(seen) < count {
+		seen[<-results] = true
+	}
+}
+
+func TestSyncHeapStable(t *testing.T) {
+	s := NewSyncHeap[int, int](0)
+	s.SetStable(true)
+	if !s.Stable() {
+		t.Fatal("the heap should be stable")
+	}
+	for i := 0; i < 100; i++ {
+		s.Insert(i%2, i)
+	}
+	for i := 0; i < 100; i++ {
+		assert(t, s.ExtractMin().Value, i%50*2+i/50)
+	}
+}
+
+func TestSyncHeapClear(t *testing.T) {
+	s := NewSyncHeap[int, any](1)
+	s.Insert(1, nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := s.InsertWait(context.Background(), 2, nil); err != nil {
+			t.Error(err)
+		}
+	}()
+	s.Clear()
+	<-done
+	assert(t, s.Size(), 1)
+	assert(t, s.Min().Key(), 2)
+}
@@ -0,0 +1,121 @@
+// Package timeline implements a game-time event scheduler on top of a
+// Fibonacci heap. Events are keyed by game time, so pausing the timeline or
+// changing its time scale never touches the queued keys: only the mapping from
+// the real elapsed time of the game loop to game time changes.
+package timeline
+
+import (
+	"time"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// Event is a scheduled event. The key of the event is the game time at which it
+// fires.
+type Event[V any] struct {
+	// At is the game time at which the event fires.
+	At time.Duration
+	// The value scheduled with this event.
+	Value V
+}
+
+// Timeline represents a queue of events ordered by game time. The zero value is
+// a running timeline at game time zero with time scale 1.
+type Timeline[V any] struct {
+	events fibheap.Heap[time.Duration, V]
+	now    time.Duration
+	// scale is stored as the difference to the default scale 1 so that the
+	// zero value of Timeline is usable.
+	scale  float64
+	paused bool
+}
+
+// Now returns the current game time of the timeline t.
+func (t *Timeline[V]) Now() time.Duration {
+	return t.now
+}
+
+// Len returns the number of pending events in the timeline t.
+func (t *Timeline[V]) Len() int {
+	return t.events.Size()
+}
+
+// Schedule schedules value to fire after the game time delay has passed.
+func (t *Timeline[V]) Schedule(delay time.Duration, value V) {
+	t.ScheduleAt(t.now+delay, value)
+}
+
+// ScheduleAt schedules value to fire at the game time at. Events scheduled in
+// the past fire on the next call to Advance or Seek.
+func (t *Timeline[V]) ScheduleAt(at time.Duration, value V) {
+	t.events.Insert(at, value)
+}
+
+// Next returns the game time of the earliest pending event. The boolean is false
+// if there is no pending event.
+func (t *Timeline[V]) Next() (time.Duration, bool) {
+	if m := t.events.Min(); m != nil {
+		return m.Key(), true
+	}
+	return 0, false
+}
+
+// Pause stops the game time of the timeline t. Advance does not move the game
+// time while the timeline is paused, but Seek still does.
+func (t *Timeline[V]) Pause() {
+	t.paused = true
+}
+
+// Resume restarts the game time of the timeline t after Pause.
+func (t *Timeline[V]) Resume() {
+	t.paused = false
+}
+
+// Paused reports whether the timeline t is paused.
+func (t *Timeline[V]) Paused() bool {
+	return t.paused
+}
+
+// Scale returns the time scale of the timeline t.
+func (t *Timeline[V]) Scale() float64 {
+	return t.scale + 1
+}
+
+// SetScale sets the time scale of the timeline t, which is the game time that
+// passes per unit of real time given to Advance. SetScale panics if scale is
+// negative.
+func (t *Timeline[V]) SetScale(scale float64) {
+	if scale < 0 {
+		panic("timeline: SetScale expects a non-negative scale")
+	}
+	t.scale = scale - 1
+}
+
+// Advance moves the game time of the timeline t forward by the real elapsed
+// time dt multiplied by the time scale, and returns the events that became due
+// in the order of their game time.
+func (t *Timeline[V]) Advance(dt time.Duration) []Event[V] {
+	if t.paused || dt <= 0 {
+		return nil
+	}
+	return t.Seek(t.now + time.Duration(float64(dt)*t.Scale()))
+}
+
+// Seek moves the game time of the timeline t forward to at and returns, in the
+// order of their game time, every event that fires at or before at. Seeking
+// backwards does nothing and returns nil.
+func (t *Timeline[V]) Seek(at time.Duration) []Event[V] {
+	if at < t.now {
+		return nil
+	}
+	t.now = at
+	due := t.events.ExtractUntil(at)
+	if len(due) == 0 {
+		return nil
+	}
+	events := make([]Event[V], len(due))
+	for i, e := range due {
+		events[i] = Event[V]{At: e.Key(), Value: e.Value}
+	}
+	return events
+}
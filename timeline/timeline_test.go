@@ -0,0 +1,75 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineAdvance(t *testing.T) {
+	tl := &Timeline[string]{}
+	tl.Schedule(3*time.Second, "c")
+	tl.Schedule(1*time.Second, "a")
+	tl.Schedule(2*time.Second, "b")
+
+	if events := tl.Advance(500 * time.Millisecond); events != nil {
+		t.Fatalf("expected no events, got %v", events)
+	}
+	events := tl.Advance(1500 * time.Millisecond)
+	if len(events) != 2 || events[0].Value != "a" || events[1].Value != "b" {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if tl.Now() != 2*time.Second {
+		t.Fatalf("expected game time 2s, got %v", tl.Now())
+	}
+	if tl.Len() != 1 {
+		t.Fatalf("expected 1 pending event, got %d", tl.Len())
+	}
+}
+
+func TestTimelinePauseAndScale(t *testing.T) {
+	tl := &Timeline[int]{}
+	tl.Schedule(time.Second, 1)
+
+	tl.Pause()
+	if events := tl.Advance(time.Hour); events != nil || tl.Now() != 0 {
+		t.Fatal("paused timeline should not advance")
+	}
+	tl.Resume()
+
+	if tl.Scale() != 1 {
+		t.Fatalf("expected default scale 1, got %v", tl.Scale())
+	}
+	tl.SetScale(2)
+	events := tl.Advance(500 * time.Millisecond)
+	if len(events) != 1 || events[0].At != time.Second {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	tl.SetScale(0)
+	tl.Schedule(time.Nanosecond, 2)
+	if events := tl.Advance(time.Hour); events != nil {
+		t.Fatal("timeline with scale 0 should not advance")
+	}
+}
+
+func TestTimelineSeek(t *testing.T) {
+	tl := &Timeline[int]{}
+	for i := 0; i < 100; i++ {
+		tl.ScheduleAt(time.Duration(i)*time.Millisecond, i)
+	}
+	events := tl.Seek(49 * time.Millisecond)
+	if len(events) != 50 {
+		t.Fatalf("expected 50 events, got %d", len(events))
+	}
+	for i, e := range events {
+		if e.Value != i {
+			t.Fatalf("expected event %d, got %d", i, e.Value)
+		}
+	}
+	if events := tl.Seek(0); events != nil || tl.Now() != 49*time.Millisecond {
+		t.Fatal("seeking backwards should do nothing")
+	}
+	if at, ok := tl.Next(); !ok || at != 50*time.Millisecond {
+		t.Fatalf("unexpected next event at %v", at)
+	}
+}
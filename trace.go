@@ -0,0 +1,290 @@
+package fibheap
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrReplayDiverged is returned by Replay when the replayed heap does not behave
+// as the traced one, for example because it extracts another element, or the
+// trace refers to an element the heap does not hold.
+var ErrReplayDiverged = errors.New("fibheap: replay diverged from the trace")
+
+// Operations recorded in a trace.
+const (
+	opSnapshot uint8 = iota + 1
+	opInsert
+	opInsertAll
+	opExtractMin
+	opDrain
+	opDecrease
+	opDecreaseAll
+	opIncrease
+	opDelete
+	opSuspend
+	opResume
+	opPin
+	opUnpin
+	opMeld
+	opUnion
+	opClear
+	opStable
+	opLazy
+	opThreshold
+	opDeleteWhere
+	opReplaceMin
+	// opEmptied records that the heap was melded into another heap.
+	opEmptied
+)
+
+// traceOp is a recorded operation. Elements are referred to by their IDs, and
+// the fields an operation does not use are left zero, so that encoding/gob
+// omits them.
+type traceOp[K any, V any] struct {
+	Op    uint8
+	ID    uint64
+	Key   K
+	Value V
+	// IDs, Keys and Pairs hold the arguments of the bulk operations.
+	IDs   []uint64
+	Keys  []K
+	Pairs []Pair[K, V]
+	// Int holds the new option of opStable, opLazy and opThreshold.
+	Int int
+	// Data holds the heap encoded by MarshalBinary for opSnapshot, and the
+	// melded heap for opMeld.
+	Data []byte
+}
+
+// tracer records the operations of a heap.
+type tracer struct {
+	enc *gob.Encoder
+	err error
+}
+
+// StartTrace starts recording every operation modifying the heap h to w, so that
+// Replay can reproduce the exact sequence of operations, including the tree
+// structure they lead to. The trace starts with a snapshot of h as encoded by
+// MarshalBinary, and the operations follow as encoding/gob records referring to
+// elements by ID, so keys and values must be encodable by encoding/gob. A
+// heap melded into h is recorded as a snapshot, and melding h into another
+// heap, which leaves h empty, is recorded as well. Operations on h which do not
+// change its structure, such as Recycle, are not recorded. The trace is
+// written synchronously: w should be buffered, and the first write error stops
+// the recording and is returned by StopTrace. StartTrace replaces a trace
+// already being recorded, and returns the error of writing the snapshot, in
+// which case nothing is recorded.
+func (h *HeapOf[K, V, O]) StartTrace(w io.Writer) error {
+	h.mustNotNil("StartTrace")
+	h.tracer = nil
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t := &tracer{enc: gob.NewEncoder(w)}
+	if err := t.enc.Encode(traceOp[K, V]{Op: opSnapshot, Data: data}); err != nil {
+		return err
+	}
+	h.tracer = t
+	if h.threshold != 0 {
+		h.record(traceOp[K, V]{Op: opThreshold, Int: h.threshold})
+	}
+	return h.tracer.err
+}
+
+// StopTrace stops recording the operations of the heap h, and returns the first
+// error met writing the trace, if any.
+func (h *HeapOf[K, V, O]) StopTrace() error {
+	if h == nil || h.tracer == nil {
+		return nil
+	}
+	err := h.tracer.err
+	h.tracer = nil
+	return err
+}
+
+// record writes the operation op to the trace of the heap h, which must be
+// recording.
+func (h *HeapOf[K, V, O]) record(op traceOp[K, V]) {
+	if t := h.tracer; t.err == nil {
+		t.err = t.enc.Encode(op)
+	}
+}
+
+// Replay replaces the contents of the heap h with the snapshot starting the
+// trace read from r, and applies the recorded operations in order. The heap h
+// keeps its comparison function and callbacks, which must be those of the
+// traced heap for the replay to be faithful. Replay checks that every
+// extraction takes the element the traced heap took, and returns an error
+// wrapping ErrReplayDiverged otherwise. Operations panicking on the traced heap
+// panic on h too, so that a trace reproduces the panic it led to.
+func (h *HeapOf[K, V, O]) Replay(r io.Reader) error {
+	h.mustNotNil("Replay")
+	dec := gob.NewDecoder(r)
+	// ids maps the IDs of the traced elements to the replayed ones, which
+	// differ for elements inserted by the replay
+	var ids map[uint64]*Element[K, V]
+	for n := 0; ; n++ {
+		var op traceOp[K, V]
+		if err := dec.Decode(&op); err != nil {
+			if err == io.EOF && n > 0 {
+				return nil
+			}
+			return err
+		}
+		if n == 0 && op.Op != opSnapshot {
+			return fmt.Errorf("%w: the trace does not start with a snapshot", ErrReplayDiverged)
+		}
+		if err := h.replay(op, &ids); err != nil {
+			return fmt.Errorf("%w at operation %d", err, n)
+		}
+	}
+}
+
+// replay applies the recorded operation op to the heap h, where ids maps the
+// IDs of the traced elements to the elements of h.
+func (h *HeapOf[K, V, O]) replay(op traceOp[K, V], ids *map[uint64]*Element[K, V]) error {
+	m := *ids
+	lookup := func(id uint64) (*Element[K, V], error) {
+		if x := m[id]; x != nil && h.Contains(x) {
+			return x, nil
+		}
+		return nil, fmt.Errorf("%w: element %d is not in the heap", ErrReplayDiverged, id)
+	}
+	switch op.Op {
+	case opSnapshot:
+		if err := h.UnmarshalBinary(op.Data); err != nil {
+			return err
+		}
+		m = make(map[uint64]*Element[K, V])
+		h.identified(m)
+		*ids = m
+	case opInsert:
+		m[op.ID] = h.Insert(op.Key, op.Value)
+	case opInsertAll:
+		if len(op.IDs) != len(op.Pairs) {
+			return errCorrupted
+		}
+		for i, x := range h.InsertAll(op.Pairs) {
+			m[op.IDs[i]] = x
+		}
+	case opExtractMin:
+		x := h.ExtractMin()
+		if x == nil || m[op.ID] != x {
+			return fmt.Errorf("%w: extracted another element than %d", ErrReplayDiverged, op.ID)
+		}
+		delete(m, op.ID)
+	case opDrain:
+		h.Drain()
+	case opDecrease, opIncrease, opDelete, opSuspend, opResume, opPin, opUnpin:
+		x, err := lookup(op.ID)
+		if err != nil {
+			return err
+		}
+		switch op.Op {
+		case opDecrease:
+			h.Decreasing(x, op.Key)
+		case opIncrease:
+			h.Increasing(x, op.Key)
+		case opDelete:
+			h.Delete(x)
+		case opSuspend:
+			h.Suspend(x)
+		case opResume:
+			h.Resume(x)
+		case opPin:
+			h.Pin(x)
+		case opUnpin:
+			h.Unpin(x)
+		}
+	case opDecreaseAll:
+		if len(op.IDs) != len(op.Keys) {
+			return errCorrupted
+		}
+		updates := make([]KeyUpdate[K, V], len(op.IDs))
+		for i, id := range op.IDs {
+			x, err := lookup(id)
+			if err != nil {
+				return err
+			}
+			updates[i] = KeyUpdate[K, V]{Element: x, Key: op.Keys[i]}
+		}
+		h.DecreaseAll(updates)
+	case opReplaceMin:
+		if x := h.ReplaceMin(op.Key, op.Value); m[op.ID] != x {
+			return fmt.Errorf("%w: replaced another element than %d", ErrReplayDiverged, op.ID)
+		}
+	case opDeleteWhere:
+		set := make(map[*Element[K, V]]bool, len(op.IDs))
+		for _, id := range op.IDs {
+			x, err := lookup(id)
+			if err != nil {
+				return err
+			}
+			set[x] = true
+			delete(m, id)
+		}
+		if n := h.deleteWhere(func(e *Element[K, V]) bool { return set[e] }); n != len(set) {
+			return fmt.Errorf("%w: deleted %d elements instead of %d", ErrReplayDiverged, n, len(set))
+		}
+	case opMeld:
+		g := &HeapOf[K, V, O]{order: h.order}
+		if err := g.UnmarshalBinary(op.Data); err != nil {
+			return err
+		}
+		g.identified(m)
+		h.Meld(g)
+	case opUnion:
+		h.Union(&HeapOf[K, V, O]{order: h.order})
+	case opEmptied:
+		// the elements move to a heap which the trace does not follow
+		(&HeapOf[K, V, O]{order: h.order}).Meld(h)
+	case opClear:
+		h.Clear()
+	case opStable:
+		h.SetStable(op.Int != 0)
+	case opLazy:
+		h.SetLazyDelete(op.Int != 0)
+	case opThreshold:
+		h.SetConsolidateThreshold(op.Int)
+	default:
+		return errCorrupted
+	}
+	return nil
+}
+
+// identified adds the elements of the heap h, including suspended ones, to m by
+// ID.
+func (h *HeapOf[K, V, O]) identified(m map[uint64]*Element[K, V]) {
+	h.each(func(e *Element[K, V]) bool {
+		m[e.id] = e
+		return true
+	})
+	for e := range h.suspended {
+		m[e.id] = e
+	}
+}
+
+// recordHeap writes the operation op carrying the heap g encoded by
+// MarshalBinary to the trace of the heap h, which must be recording. An error
+// encoding g stops the recording.
+func (h *HeapOf[K, V, O]) recordHeap(op uint8, g *HeapOf[K, V, O]) {
+	data, err := g.MarshalBinary()
+	if err != nil {
+		if h.tracer.err == nil {
+			h.tracer.err = err
+		}
+		return
+	}
+	h.record(traceOp[K, V]{Op: op, Data: data})
+}
+
+// boolInt returns 1 for true and 0 for false, as options are recorded.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
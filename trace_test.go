@@ -0,0 +1,256 @@
+package fibheap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// shape returns the trees of the heap h in pre-order, from the minimum, and
+// its held elements, so that heaps of the same shape compare equal.
+func shape[K any, V any, O Order[K]](h *HeapOf[K, V, O]) string {
+	var trees []string
+	walk(h.min, 0, func(e *Element[K, V], depth int) {
+		trees = append(trees, fmt.Sprint(depth, e.key, e.Value, e.degree, e.flags))
+	})
+	var held []string
+	for _, m := range []map[*Element[K, V]]struct{}{h.pinned, h.suspended} {
+		for e := range m {
+			held = append(held, fmt.Sprint(e.key, e.Value, e.flags))
+		}
+	}
+	slices.Sort(held)
+	return fmt.Sprint(trees, held, h.elements, h.tombstones, h.roots)
+}
+
+func TestHeapTrace(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := &Heap[int, int]{}
+	h.SetStable(true)
+	for i := 0; i < 50; i++ {
+		h.Insert(r.Intn(100), i)
+	}
+	h.ExtractMin()
+	var trace bytes.Buffer
+	if err := h.StartTrace(&trace); err != nil {
+		t.Fatal(err)
+	}
+	h.SetConsolidateThreshold(64)
+	h.SetLazyDelete(true)
+
+	var elements []*Element[int, int]
+	for x := range h.Elements() {
+		elements = append(elements, x)
+	}
+	pick := func() *Element[int, int] {
+		for len(elements) > 0 {
+			j := r.Intn(len(elements))
+			if x := elements[j]; h.Contains(x) {
+				return x
+			}
+			elements = slices.Delete(elements, j, j+1)
+		}
+		return nil
+	}
+	for i := 0; i < 3000; i++ {
+		switch op := r.Intn(40); {
+		case op < 12:
+			elements = append(elements, h.Insert(r.Intn(10000), i))
+		case op < 14:
+			pairs := make([]Pair[int, int], r.Intn(5))
+			for j := range pairs {
+				pairs[j] = Pair[int, int]{Key: r.Intn(10000), Value: i}
+			}
+			elements = append(elements, h.InsertAll(pairs)...)
+		case op < 20:
+			h.ExtractMin()
+		case op < 21:
+			h.PopN(3)
+		case op < 25:
+			if x := pick(); x != nil {
+				h.Update(x, x.Key()+r.Intn(200)-100)
+			}
+		case op < 26:
+			var updates []KeyUpdate[int, int]
+			for j := 0; j < 3; j++ {
+				if x := pick(); x != nil {
+					updates = append(updates, KeyUpdate[int, int]{Element: x, Key: x.Key() - 50})
+				}
+			}
+			h.DecreaseAll(updates)
+		case op < 29:
+			if x := pick(); x != nil {
+				h.Delete(x)
+			}
+		case op < 31:
+			if x := pick(); x != nil && x.flags == 0 {
+				if r.Intn(2) == 0 {
+					h.Suspend(x)
+				} else {
+					h.Pin(x)
+				}
+			}
+		case op < 33:
+			if x := pick(); x != nil && x.flags&suspended != 0 {
+				h.Resume(x)
+			} else if x != nil && x.flags&pinned != 0 {
+				h.Unpin(x)
+			}
+		case op < 35:
+			g := &Heap[int, int]{}
+			g.SetStable(true)
+			for j := r.Intn(20); j > 0; j-- {
+				elements = append(elements, g.Insert(r.Intn(10000), i))
+			}
+			g.ExtractMin()
+			h.Meld(g)
+		case op < 36:
+			if x := pick(); x != nil && x.flags == 0 && x.Key() > 0 {
+				h.Remove(x, math.MinInt)
+			}
+		case op < 37 && r.Intn(20) == 0:
+			h.Drain()
+		case op < 38 && r.Intn(20) == 0:
+			h.Clear()
+		}
+	}
+	if err := h.StopTrace(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Heap[int, int]{}
+	if err := g.Replay(bytes.NewReader(trace.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if shape(g) != shape(h) {
+		t.Fatal("the replayed heap differs from the traced one")
+	}
+	if err := g.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Replay(bytes.NewReader(nil)); err == nil {
+		t.Fatal("expected an error for an empty trace")
+	}
+}
+
+func TestHeapTraceDiverged(t *testing.T) {
+	h := &Heap[int, int]{}
+	var trace bytes.Buffer
+	if err := h.StartTrace(&trace); err != nil {
+		t.Fatal(err)
+	}
+	h.Insert(1, 1)
+	h.Insert(2, 2)
+	h.ExtractMin()
+	// a heap ordering keys in another way extracts another element
+	g := NewHeapFunc[int, int](func(a, b int) bool { return a > b })
+	if err := g.Replay(bytes.NewReader(trace.Bytes())); !errors.Is(err, ErrReplayDiverged) {
+		t.Fatalf("expected ErrReplayDiverged, got %v", err)
+	}
+}
+
+func TestHeapTraceUnion(t *testing.T) {
+	h := &Heap[string, int]{}
+	var trace bytes.Buffer
+	if err := h.StartTrace(&trace); err != nil {
+		t.Fatal(err)
+	}
+	h.Insert("b", 1)
+	m := h.Union(&Heap[string, int]{})
+	h.Insert("a", 2)
+	if err := h.UnmarshalJSON([]byte(`[{"key": "c", "value": 3}]`)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	h.ExtractMin()
+	h.Insert("d", 4)
+
+	g := &Heap[string, int]{}
+	if err := g.Replay(&trace); err != nil {
+		t.Fatal(err)
+	}
+	if shape(g) != shape(h) {
+		t.Fatal("the replayed heap differs from the traced one")
+	}
+}
+
+func TestHeapTraceMeldedAway(t *testing.T) {
+	for name, meld := range map[string]func(h, g *Heap[int, int]){
+		"Meld":  func(h, g *Heap[int, int]) { h.Meld(g) },
+		"Union": func(h, g *Heap[int, int]) { h.Union(g) },
+		"MergeDedup": func(h, g *Heap[int, int]) {
+			h.MergeDedup(g, func(a, b *Element[int, int]) *Element[int, int] { return a })
+		},
+	} {
+		g := &Heap[int, int]{}
+		var trace bytes.Buffer
+		if err := g.StartTrace(&trace); err != nil {
+			t.Fatal(err)
+		}
+		g.Insert(1, 1)
+		h := &Heap[int, int]{}
+		h.Insert(2, 2)
+		meld(h, g)
+		g.Insert(5, 5)
+		g.ExtractMin()
+		g.Insert(3, 3)
+
+		r := &Heap[int, int]{}
+		if err := r.Replay(&trace); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if shape(r) != shape(g) {
+			t.Fatalf("%s: the replayed heap differs from the traced one", name)
+		}
+	}
+}
+
+// limitedWriter fails the writes beyond n bytes.
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.n -= len(p); w.n < 0 {
+		return 0, errors.New("write failed")
+	}
+	return len(p), nil
+}
+
+func TestHeapTraceError(t *testing.T) {
+	h := &Heap[int, int]{}
+	if err := h.StartTrace(failingWriter{}); err == nil {
+		t.Fatal("expected StartTrace to fail")
+	}
+	if h.tracer != nil {
+		t.Fatal("expected no trace after a failed StartTrace")
+	}
+	if err := h.StartTrace(&limitedWriter{n: 500}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		h.Insert(i, i)
+	}
+	if err := h.StopTrace(); err == nil {
+		t.Fatal("expected StopTrace to report the write error")
+	}
+	if err := h.StopTrace(); err != nil {
+		t.Fatal("expected no error once the trace is stopped")
+	}
+	var nilHeap *Heap[int, int]
+	if err := nilHeap.StopTrace(); err != nil {
+		t.Fatal(err)
+	}
+	mustPanic(t, "StartTrace", "fibheap: StartTrace on a nil heap", func() { nilHeap.StartTrace(&bytes.Buffer{}) })
+}
@@ -0,0 +1,186 @@
+// Package ttlcache implements a cache whose entries expire a fixed time after
+// they were last set or touched. The expiration times are kept in a Fibonacci
+// heap, so expired entries are found without scanning the cache.
+//
+// Refreshing an entry only moves its expiration time later, which the heap
+// would pay with an O(log n) key increase. Instead, a refresh records the new
+// expiration time in the entry with running time Θ(1), and the heap key is
+// left early. When such a stale key reaches the minimum, the entry is found
+// alive and its key is increased then, at most once per refresh cycle of the
+// entry. Moving an expiration time earlier decreases the key, which takes
+// amortized running time Θ(1).
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ksw2000/go-fibheap"
+)
+
+// entry is a cached value. The key of its element in the heap is never later
+// than expires.
+type entry[K comparable, V any] struct {
+	value   V
+	expires time.Duration
+	e       *fibheap.Element[time.Duration, K]
+}
+
+// Cache represents the TTL cache. It is safe for concurrent use. Expired
+// entries are removed by the methods of the cache as they go, so that the
+// cache does not need a goroutine of its own, and by Expire.
+type Cache[K comparable, V any] struct {
+	// Evicted, if not nil, is called with every entry removed because it
+	// expired, without the lock of the cache held. It must be set before the
+	// cache is used.
+	Evicted func(key K, value V)
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[K]*entry[K, V]
+	// expiry is keyed by the expiration times as durations since epoch
+	expiry fibheap.Heap[time.Duration, K]
+	epoch  time.Time
+	now    func() time.Time
+}
+
+// New returns an empty cache whose entries expire ttl after they were last set
+// or touched. New panics if ttl is not positive.
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	if ttl <= 0 {
+		panic("ttlcache: New expects a positive ttl")
+	}
+	return &Cache[K, V]{
+		ttl:     ttl,
+		entries: make(map[K]*entry[K, V]),
+		epoch:   time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Len returns the number of entries of the cache c, including expired entries
+// which were not removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Get returns the value of key, and whether the cache c holds an entry of key
+// which has not expired. Get does not refresh the entry.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	en, ok := c.entries[key]
+	if !ok || en.expires <= c.since() {
+		var zero V
+		return zero, false
+	}
+	return en.value, true
+}
+
+// Set sets the value of key, which expires after the ttl of the cache c.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetTTL(key, value, c.ttl)
+}
+
+// SetTTL sets the value of key, which expires after ttl instead of the ttl of
+// the cache c. Later refreshes by Touch use the ttl of the cache again.
+func (c *Cache[K, V]) SetTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	now := c.since()
+	if en, ok := c.entries[key]; ok {
+		en.value = value
+		c.expireAt(en, now+ttl)
+	} else {
+		c.entries[key] = &entry[K, V]{
+			value:   value,
+			expires: now + ttl,
+			e:       c.expiry.Insert(now+ttl, key),
+		}
+	}
+	evicted := c.expire(now)
+	c.mu.Unlock()
+	c.evict(evicted)
+}
+
+// Touch refreshes the entry of key, which then expires after the ttl of the
+// cache c, with running time Θ(1). Touch reports whether the cache c holds an
+// entry of key which has not expired; an expired entry is not refreshed.
+func (c *Cache[K, V]) Touch(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.since()
+	en, ok := c.entries[key]
+	if !ok || en.expires <= now {
+		return false
+	}
+	c.expireAt(en, now+c.ttl)
+	return true
+}
+
+// Delete removes the entry of key from the cache c, and reports whether there
+// was one. Deleted entries are not passed to Evicted.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	en, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.expiry.Delete(en.e)
+	delete(c.entries, key)
+	return true
+}
+
+// Expire removes every expired entry from the cache c, and returns the number
+// of removed entries.
+func (c *Cache[K, V]) Expire() int {
+	c.mu.Lock()
+	evicted := c.expire(c.since())
+	c.mu.Unlock()
+	c.evict(evicted)
+	return len(evicted)
+}
+
+// since returns the current time as a duration since epoch.
+func (c *Cache[K, V]) since() time.Duration {
+	return c.now().Sub(c.epoch)
+}
+
+// expireAt moves the expiration time of the entry en to at. A later time is
+// only recorded in en, while an earlier one decreases the key of en.
+func (c *Cache[K, V]) expireAt(en *entry[K, V], at time.Duration) {
+	en.expires = at
+	if at < en.e.Key() {
+		c.expiry.Decreasing(en.e, at)
+	}
+}
+
+// expire removes the entries which expired at now, and returns them. The stale
+// keys of refreshed entries found on the way are increased to their expiration
+// time.
+func (c *Cache[K, V]) expire(now time.Duration) []fibheap.Pair[K, V] {
+	var evicted []fibheap.Pair[K, V]
+	for m := c.expiry.Min(); m != nil && m.Key() <= now; m = c.expiry.Min() {
+		en := c.entries[m.Value]
+		if en.expires > now {
+			c.expiry.Increasing(m, en.expires)
+			continue
+		}
+		c.expiry.ExtractMin()
+		delete(c.entries, m.Value)
+		evicted = append(evicted, fibheap.Pair[K, V]{Key: m.Value, Value: en.value})
+	}
+	return evicted
+}
+
+// evict passes the evicted entries to Evicted, if set.
+func (c *Cache[K, V]) evict(evicted []fibheap.Pair[K, V]) {
+	if c.Evicted == nil {
+		return
+	}
+	for _, p := range evicted {
+		c.Evicted(p.Key, p.Value)
+	}
+}
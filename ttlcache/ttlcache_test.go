@@ -0,0 +1,125 @@
+package ttlcache
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// clock is a manual clock for the tests.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newCache[V any](ttl time.Duration) (*Cache[string, V], *clock) {
+	c := New[string, V](ttl)
+	clk := &clock{t: c.epoch}
+	c.now = clk.now
+	return c, clk
+}
+
+func TestCache(t *testing.T) {
+	c, clk := newCache[int](10 * time.Second)
+	var evicted []string
+	c.Evicted = func(key string, value int) {
+		evicted = append(evicted, key)
+	}
+	c.Set("a", 1)
+	clk.advance(5 * time.Second)
+	c.Set("b", 2)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v %v", v, ok)
+	}
+
+	clk.advance(5 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should have expired")
+	}
+	if n := c.Expire(); n != 1 || len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatalf("expected a to be evicted, got %d %v", n, evicted)
+	}
+	if c.Touch("a") {
+		t.Fatal("Touch should not refresh an expired entry")
+	}
+
+	// touching b keeps it alive past its first expiration time
+	clk.advance(4 * time.Second)
+	if !c.Touch("b") {
+		t.Fatal("Touch should refresh b")
+	}
+	clk.advance(6 * time.Second)
+	if n := c.Expire(); n != 0 {
+		t.Fatalf("b should not expire yet, got %d evicted", n)
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("expected b=2, got %v %v", v, ok)
+	}
+	clk.advance(4 * time.Second)
+	if n := c.Expire(); n != 1 || c.Len() != 0 {
+		t.Fatalf("b should expire, got %d evicted and %d entries", n, c.Len())
+	}
+}
+
+func TestCacheSetTTL(t *testing.T) {
+	c, clk := newCache[int](time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// a shorter ttl moves the expiration time earlier
+	c.SetTTL("a", 3, time.Second)
+	clk.advance(2 * time.Second)
+	if n := c.Expire(); n != 1 {
+		t.Fatalf("expected a to expire, got %d evicted", n)
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Fatal("b should not expire")
+	}
+	if !c.Delete("b") || c.Delete("b") {
+		t.Fatal("Delete should report whether there was an entry")
+	}
+	if c.Len() != 0 || c.expiry.Size() != 0 {
+		t.Fatalf("expected an empty cache, got %d entries and %d keys", c.Len(), c.expiry.Size())
+	}
+}
+
+func TestCacheRandom(t *testing.T) {
+	c, clk := newCache[int](100 * time.Millisecond)
+	r := rand.New(rand.NewSource(1))
+	// model holds the expiration time of every entry
+	model := map[string]time.Time{}
+	for i := 0; i < 5000; i++ {
+		key := string(rune('a' + r.Intn(26)))
+		switch r.Intn(4) {
+		case 0:
+			ttl := time.Duration(1+r.Intn(200)) * time.Millisecond
+			c.SetTTL(key, i, ttl)
+			model[key] = clk.t.Add(ttl)
+		case 1:
+			if c.Touch(key) {
+				model[key] = clk.t.Add(c.ttl)
+			}
+		case 2:
+			clk.advance(time.Duration(r.Intn(20)) * time.Millisecond)
+		default:
+			c.Expire()
+		}
+		for k, at := range model {
+			if _, ok := c.Get(k); ok != clk.t.Before(at) {
+				t.Fatalf("operation %d: Get(%s) reported %v at %v for an expiration at %v", i, k, ok, clk.t, at)
+			}
+			if !clk.t.Before(at) {
+				delete(model, k)
+			}
+		}
+		if err := c.expiry.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
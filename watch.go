@@ -0,0 +1,83 @@
+package fibheap
+
+// watch is an edge-triggered condition registered on a heap.
+type watch[K any, V any, O Order[K]] struct {
+	cond  func(h *HeapOf[K, V, O]) bool
+	fire  func(h *HeapOf[K, V, O])
+	state bool
+}
+
+// OnSizeAbove registers fn to be called whenever the number of elements in the
+// heap h grows from at most mark to more than mark. It returns a function which
+// unregisters fn.
+func (h *HeapOf[K, V, O]) OnSizeAbove(mark int, fn func(size int)) (cancel func()) {
+	h.mustNotNil("OnSizeAbove")
+	return h.watch(func(h *HeapOf[K, V, O]) bool {
+		return h.elements > mark
+	}, func(h *HeapOf[K, V, O]) {
+		fn(h.elements)
+	})
+}
+
+// OnSizeBelow registers fn to be called whenever the number of elements in the
+// heap h falls from at least mark to less than mark. It returns a function which
+// unregisters fn.
+func (h *HeapOf[K, V, O]) OnSizeBelow(mark int, fn func(size int)) (cancel func()) {
+	h.mustNotNil("OnSizeBelow")
+	return h.watch(func(h *HeapOf[K, V, O]) bool {
+		return h.elements < mark
+	}, func(h *HeapOf[K, V, O]) {
+		fn(h.elements)
+	})
+}
+
+// OnMinBelow registers fn to be called with the minimum element whenever the
+// minimum key of the heap h crosses from at least threshold, or an empty heap,
+// to less than threshold. It returns a function which unregisters fn.
+func (h *HeapOf[K, V, O]) OnMinBelow(threshold K, fn func(min *Element[K, V])) (cancel func()) {
+	h.mustNotNil("OnMinBelow")
+	return h.watch(func(h *HeapOf[K, V, O]) bool {
+		return h.min != nil && h.order.Less(h.min.key, threshold)
+	}, func(h *HeapOf[K, V, O]) {
+		fn(h.min)
+	})
+}
+
+// OnMinAbove registers fn to be called with the minimum element whenever the
+// minimum key of the heap h crosses from at most threshold, or an empty heap, to
+// more than threshold. It returns a function which unregisters fn.
+func (h *HeapOf[K, V, O]) OnMinAbove(threshold K, fn func(min *Element[K, V])) (cancel func()) {
+	h.mustNotNil("OnMinAbove")
+	return h.watch(func(h *HeapOf[K, V, O]) bool {
+		return h.min != nil && h.order.Less(threshold, h.min.key)
+	}, func(h *HeapOf[K, V, O]) {
+		fn(h.min)
+	})
+}
+
+// watch registers the edge-triggered condition cond. The callbacks are called
+// synchronously by the operation which changes the heap, and must not modify
+// the heap.
+func (h *HeapOf[K, V, O]) watch(cond func(h *HeapOf[K, V, O]) bool, fire func(h *HeapOf[K, V, O])) func() {
+	w := &watch[K, V, O]{cond: cond, fire: fire, state: cond(h)}
+	h.watches = append(h.watches, w)
+	return func() {
+		for i := range h.watches {
+			if h.watches[i] == w {
+				h.watches = append(h.watches[:i], h.watches[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// notify fires the watches whose condition became true.
+func (h *HeapOf[K, V, O]) notify() {
+	for _, w := range h.watches {
+		state := w.cond(h)
+		if state && !w.state {
+			w.fire(h)
+		}
+		w.state = state
+	}
+}
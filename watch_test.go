@@ -0,0 +1,68 @@
+package fibheap
+
+import (
+	"testing"
+)
+
+func TestHeapOnSize(t *testing.T) {
+	h := &Heap[int, any]{}
+	var above, below []int
+	h.OnSizeAbove(3, func(size int) { above = append(above, size) })
+	cancel := h.OnSizeBelow(2, func(size int) { below = append(below, size) })
+
+	for i := 0; i < 5; i++ {
+		h.Insert(i, nil)
+	}
+	for i := 0; i < 5; i++ {
+		h.ExtractMin()
+	}
+	for i := 0; i < 5; i++ {
+		h.Insert(i, nil)
+	}
+	cancel()
+	for i := 0; i < 5; i++ {
+		h.ExtractMin()
+	}
+
+	if len(above) != 2 || above[0] != 4 || above[1] != 4 {
+		t.Fatalf("unexpected OnSizeAbove calls %v", above)
+	}
+	if len(below) != 1 || below[0] != 1 {
+		t.Fatalf("unexpected OnSizeBelow calls %v", below)
+	}
+}
+
+func TestHeapOnMin(t *testing.T) {
+	h := &Heap[int, string]{}
+	var below, above []string
+	h.OnMinBelow(10, func(min *Element[int, string]) { below = append(below, min.Value) })
+	h.OnMinAbove(10, func(min *Element[int, string]) { above = append(above, min.Value) })
+
+	x := h.Insert(20, "a")
+	h.Insert(15, "b")
+	h.Decreasing(x, 5)
+	h.Insert(1, "c")
+	h.ExtractMin()
+	h.ExtractMin()
+	h.Remove(h.Min(), 0)
+	h.Insert(30, "d")
+
+	if len(below) != 1 || below[0] != "a" {
+		t.Fatalf("unexpected OnMinBelow calls %v", below)
+	}
+	if len(above) != 3 || above[0] != "a" || above[1] != "b" || above[2] != "d" {
+		t.Fatalf("unexpected OnMinAbove calls %v", above)
+	}
+}
+
+func TestHeapOnSizePin(t *testing.T) {
+	h := &Heap[int, any]{}
+	x := h.Insert(1, nil)
+	h.Insert(2, nil)
+	calls := 0
+	h.OnSizeBelow(2, func(int) { calls++ })
+	h.Pin(x)
+	assert(t, calls, 0)
+	h.Suspend(h.Min())
+	assert(t, calls, 1)
+}